@@ -12,3 +12,25 @@ type Check interface {
 	// The function is expected to exit as soon as the provided Context is Done.
 	Execute(ctx context.Context) (details interface{}, err error)
 }
+
+// funcCheck is a Check implemented by a function
+type funcCheck struct {
+	name    string
+	execute ExecuteFunc
+}
+
+func (c *funcCheck) Name() string {
+	return c.name
+}
+
+func (c *funcCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	return c.execute(ctx)
+}
+
+// CheckRegistration is a check along with its registration options, used for registering multiple checks at once.
+type CheckRegistration struct {
+	// Check is the check to register
+	Check Check
+	// Options are the check options
+	Options []CheckOption
+}
@@ -18,6 +18,15 @@ type CheckListener interface {
 	OnCheckCompleted(name string, result Result)
 }
 
+// CheckTimeoutListener can be implemented by a CheckListener in order to be notified about check executions that
+// were abandoned after exceeding their hard timeout (see the HardTimeout option).
+// Implementations of this interface **must not block!**
+type CheckTimeoutListener interface {
+	// OnCheckTimeout is called when the execution of the check with the specified name is abandoned due to its hard timeout.
+	// The timeout result is passed as an argument, and OnCheckCompleted is called with it right after
+	OnCheckTimeout(name string, result Result)
+}
+
 // CheckListeners is a slice of check listeners
 type CheckListeners []CheckListener
 
@@ -43,3 +52,13 @@ func (c CheckListeners) OnCheckCompleted(name string, result Result) {
 		listener.OnCheckCompleted(name, result)
 	}
 }
+
+// OnCheckTimeout is called when the execution of the check with the specified name is abandoned due to its hard timeout.
+// Only the listeners that implement CheckTimeoutListener are notified
+func (c CheckListeners) OnCheckTimeout(name string, result Result) {
+	for _, listener := range c {
+		if timeoutListener, ok := listener.(CheckTimeoutListener); ok {
+			timeoutListener.OnCheckTimeout(name, result)
+		}
+	}
+}
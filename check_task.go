@@ -2,14 +2,152 @@ package gosundheit
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
 	"time"
 )
 
 type checkTask struct {
 	stopChan chan bool
-	ticker   *time.Ticker
-	check    Check
-	timeout  time.Duration
+	// doneChan is closed once the task is stopped and cleaned up
+	doneChan   chan struct{}
+	reportChan chan struct{}
+	ticker     Ticker
+	check      Check
+	timeout    time.Duration
+	// hardTimeout is the time after which an execution is abandoned, or zero for no limit.
+	hardTimeout time.Duration
+	severity    SeverityLevel
+	// maxStaleness is the maximum age of a result before it's considered failing, or zero for no limit.
+	maxStaleness time.Duration
+	// dependencies are the names of the checks that must pass for this check to execute.
+	dependencies []string
+	// unhealthyAfter is the time the check must be failing continuously before it makes the system unhealthy.
+	unhealthyAfter time.Duration
+	// classifications are the classifications the check belongs to.
+	classifications []Classification
+	// debounceWindow is the time a change in the check health must persist before it's reported, or zero for no debouncing.
+	debounceWindow time.Duration
+	// executionPeriod and initialDelay are the scheduling configuration of the check, and are zero for async checks.
+	executionPeriod time.Duration
+	initialDelay    time.Duration
+	// resultTTL is the maximum time an async check result is valid, or zero for no limit.
+	resultTTL time.Duration
+
+	// The following fields are guarded by the health lock.
+
+	// executed is set once the first execution completed.
+	executed bool
+	// lastResult is the last raw (not debounced) result of the check, or nil before the initial result is set.
+	lastResult *Result
+	// transitionSince is the time of the first raw result that differs in health from the reported result,
+	// or zero when there's no pending transition.
+	transitionSince time.Time
+	// override is the result that overrides the check results until overrideUntil, or nil when not overridden.
+	override      *Result
+	overrideUntil time.Time
+	// startupCompleted is set once the system started for checks classified only as startup checks, which are then no longer executed.
+	startupCompleted bool
+	// nextRun is the time of the next scheduled execution, or zero for async checks.
+	// In manual execution mode, it is the earliest time the check may be executed again.
+	nextRun time.Time
+}
+
+// isStale returns true when the given result is older than the max staleness of this task at the given time.
+// The results of completed startup checks are never stale, since they are no longer executed.
+func (t *checkTask) isStale(result Result, now time.Time) bool {
+	return !t.startupCompleted && t.maxStaleness > 0 && now.Sub(result.Timestamp) > t.maxStaleness
+}
+
+// isTolerated returns true when the given result is failing, but has not been failing continuously
+// for longer than the unhealthy after period of this task at the given time.
+func (t *checkTask) isTolerated(result Result, now time.Time) bool {
+	return t.unhealthyAfter > 0 && !result.IsHealthy() && result.TimeOfFirstFailure != nil &&
+		now.Sub(*result.TimeOfFirstFailure) <= t.unhealthyAfter
+}
+
+// overridden returns the result override at the given time, if the check results are overridden at that time.
+func (t *checkTask) overridden(now time.Time) (Result, bool) {
+	if t.override == nil || !now.Before(t.overrideUntil) {
+		return Result{}, false
+	}
+
+	result := *t.override
+	until := t.overrideUntil
+	result.OverriddenUntil = &until
+	return result, true
+}
+
+// debounce returns the result to report given the latest raw result, and the currently reported result if any.
+// While the health of the raw results differs from the reported one for less than the debounce window,
+// the reported result is kept, with the raw result as its details.
+// Transitions away from the initial "didn't run yet" result are never debounced.
+func (t *checkTask) debounce(result Result, reported Result, hasReported bool) Result {
+	if t.debounceWindow <= 0 || !hasReported || errors.Is(reported.Error, ErrNotRunYet) ||
+		result.IsHealthy() == reported.IsHealthy() {
+
+		t.transitionSince = time.Time{}
+		return result
+	}
+
+	if t.transitionSince.IsZero() {
+		t.transitionSince = result.Timestamp
+	}
+	if result.Timestamp.Sub(t.transitionSince) >= t.debounceWindow {
+		t.transitionSince = time.Time{}
+		return result
+	}
+
+	suppressed := reported
+	suppressed.Details = result
+	suppressed.Timestamp = result.Timestamp
+	suppressed.Duration = result.Duration
+	return suppressed
+}
+
+// isClassified returns true when the check belongs to the given classification
+func (t *checkTask) isClassified(classification Classification) bool {
+	for _, c := range t.classifications {
+		if c == classification {
+			return true
+		}
+	}
+	return false
+}
+
+// isStartupOnly returns true when the check is only classified as a startup check
+func (t *checkTask) isStartupOnly() bool {
+	for _, c := range t.classifications {
+		if c != ClassificationStartup {
+			return false
+		}
+	}
+	return len(t.classifications) > 0
+}
+
+// isAsync returns true for tasks of checks that were registered using Health.RegisterAsyncCheck()
+func (t *checkTask) isAsync() bool {
+	_, ok := t.check.(*asyncCheck)
+	return ok
+}
+
+// requestStop signals the task go routine to stop, unless a stop was already requested.
+func (t *checkTask) requestStop() {
+	select {
+	case t.stopChan <- true:
+	default:
+	}
+}
+
+// awaitDone blocks until the task is stopped and cleaned up, or returns the context error if the context is done first.
+func (t *checkTask) awaitDone(ctx context.Context) error {
+	select {
+	case <-t.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (t *checkTask) stop() {
@@ -18,12 +156,57 @@ func (t *checkTask) stop() {
 	}
 }
 
-func (t *checkTask) execute(ctx context.Context) (details interface{}, duration time.Duration, err error) {
+// execute executes the check, and calls returned once the check returns. When the task has a hard timeout
+// and the execution exceeds it, the execution is abandoned and timedOut is true, while returned is only called
+// once the abandoned execution eventually returns.
+func (t *checkTask) execute(ctx context.Context, clock Clock, returned func()) (
+	details interface{}, duration time.Duration, err error, timedOut bool) {
+
+	if t.hardTimeout <= 0 {
+		defer returned()
+		details, duration, err = t.executeCheck(ctx, clock)
+		return
+	}
+
+	type outcome struct {
+		details  interface{}
+		duration time.Duration
+		err      error
+	}
+
+	// cancel the abandoned execution, in case it does respect its context eventually
+	abandonCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	outcomeChan := make(chan outcome, 1)
+	go func() {
+		defer returned()
+		details, duration, err := t.executeCheck(abandonCtx, clock)
+		outcomeChan <- outcome{details: details, duration: duration, err: err}
+	}()
+
+	timer := clock.NewTimer(t.hardTimeout)
+	defer timer.Stop()
+	select {
+	case o := <-outcomeChan:
+		return o.details, o.duration, o.err, false
+	case <-timer.C():
+		return nil, t.hardTimeout, ErrExecutionTimeout, true
+	}
+}
+
+func (t *checkTask) executeCheck(ctx context.Context, clock Clock) (details interface{}, duration time.Duration, err error) {
 	timeoutCtx, cancel := contextWithTimeout(ctx, t.timeout)
 	defer cancel()
-	startTime := time.Now()
+	startTime := clock.Now()
+	defer func() {
+		// a panicking check must not take down the scheduler, so it is reported as a failure instead
+		if r := recover(); r != nil {
+			details = string(debug.Stack())
+			err = fmt.Errorf("check panicked: %v", r)
+		}
+		duration = clock.Now().Sub(startTime)
+	}()
 	details, err = t.check.Execute(timeoutCtx)
-	duration = time.Since(startTime)
 
 	return
 }
@@ -34,3 +217,16 @@ func contextWithTimeout(parent context.Context, t time.Duration) (context.Contex
 	}
 	return context.WithTimeout(parent, t)
 }
+
+// asyncCheck is a placeholder Check for checks whose results are reported using Health.ReportResult()
+type asyncCheck struct {
+	name string
+}
+
+func (c *asyncCheck) Name() string {
+	return c.name
+}
+
+func (c *asyncCheck) Execute(_ context.Context) (details interface{}, err error) {
+	return nil, fmt.Errorf("async check '%s' can not be executed", c.name)
+}
@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// BlobClient is the minimal blob storage API required by the blob round trip check, so that any provider can be used,
+// e.g. by wrapping the PutObject, GetObject and DeleteObject operations of S3, the object writer, reader and delete of
+// a GCS bucket handle, or the upload, download and delete of an Azure blob container client.
+// Implementations should return an error on any failure, including authentication, permission and connectivity errors.
+type BlobClient interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobClientFuncs is an adapter to allow the use of ordinary functions as a BlobClient.
+type BlobClientFuncs struct {
+	PutFunc    func(ctx context.Context, key string, data []byte) error
+	GetFunc    func(ctx context.Context, key string) ([]byte, error)
+	DeleteFunc func(ctx context.Context, key string) error
+}
+
+// Put calls f.PutFunc(ctx, key, data).
+func (f BlobClientFuncs) Put(ctx context.Context, key string, data []byte) error {
+	return f.PutFunc(ctx, key, data)
+}
+
+// Get calls f.GetFunc(ctx, key).
+func (f BlobClientFuncs) Get(ctx context.Context, key string) ([]byte, error) {
+	return f.GetFunc(ctx, key)
+}
+
+// Delete calls f.DeleteFunc(ctx, key).
+func (f BlobClientFuncs) Delete(ctx context.Context, key string) error {
+	return f.DeleteFunc(ctx, key)
+}
+
+// BlobRoundTripDetails are the details of a blob round trip check
+type BlobRoundTripDetails struct {
+	Key           string        `json:"key"`
+	WriteLatency  time.Duration `json:"writeLatency"`
+	ReadLatency   time.Duration `json:"readLatency,omitempty"`
+	DeleteLatency time.Duration `json:"deleteLatency,omitempty"`
+}
+
+type blobRoundTripCheck struct {
+	name      string
+	client    BlobClient
+	keyPrefix string
+}
+
+// NewBlobRoundTripCheck returns a Check that writes a small object to blob storage using the given client, reads it back,
+// verifies its content, and deletes it, which exercises the full data path rather than just the bucket metadata (see NewS3Check).
+// Each execution uses a unique key starting with keyPrefix, e.g. "health/", so that concurrent instances do not collide.
+// The object is deleted even when reading it fails. The key and the operations latencies are reported as the check details.
+func NewBlobRoundTripCheck(name string, client BlobClient, keyPrefix string) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &blobRoundTripCheck{name: name, client: client, keyPrefix: keyPrefix}, nil
+}
+
+func (c *blobRoundTripCheck) Name() string {
+	return c.name
+}
+
+func (c *blobRoundTripCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Errorf("failed to generate key: %v", err)
+	}
+	blobDetails := BlobRoundTripDetails{Key: c.keyPrefix + c.name + "-" + hex.EncodeToString(nonce)}
+	content := []byte(c.name + " " + time.Now().UTC().Format(time.RFC3339Nano))
+
+	start := time.Now()
+	err = c.client.Put(ctx, blobDetails.Key, content)
+	blobDetails.WriteLatency = time.Since(start)
+	if err != nil {
+		return blobDetails, errors.Errorf("failed to write '%s': %v", blobDetails.Key, err)
+	}
+
+	start = time.Now()
+	data, err := c.client.Get(ctx, blobDetails.Key)
+	blobDetails.ReadLatency = time.Since(start)
+	if err != nil {
+		err = errors.Errorf("failed to read '%s': %v", blobDetails.Key, err)
+	} else if !bytes.Equal(data, content) {
+		err = errors.Errorf("read '%s' returned %d bytes of unexpected content", blobDetails.Key, len(data))
+	}
+
+	start = time.Now()
+	deleteErr := c.client.Delete(ctx, blobDetails.Key)
+	blobDetails.DeleteLatency = time.Since(start)
+	if err == nil && deleteErr != nil {
+		err = errors.Errorf("failed to delete '%s': %v", blobDetails.Key, deleteErr)
+	}
+	return blobDetails, err
+}
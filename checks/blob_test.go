@@ -0,0 +1,105 @@
+package checks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlobStore is an in memory BlobClient, that fails the operations in failing
+type fakeBlobStore struct {
+	objects map[string][]byte
+	failing map[string]bool
+	corrupt bool
+}
+
+func newFakeBlobStore(failing ...string) *fakeBlobStore {
+	s := &fakeBlobStore{objects: make(map[string][]byte), failing: make(map[string]bool)}
+	for _, op := range failing {
+		s.failing[op] = true
+	}
+	return s
+}
+
+func (s *fakeBlobStore) client() BlobClient {
+	return BlobClientFuncs{
+		PutFunc: func(_ context.Context, key string, data []byte) error {
+			if s.failing["put"] {
+				return errors.New("AccessDenied")
+			}
+			s.objects[key] = data
+			return nil
+		},
+		GetFunc: func(_ context.Context, key string) ([]byte, error) {
+			if s.failing["get"] {
+				return nil, errors.New("connection reset")
+			}
+			if s.corrupt {
+				return []byte("corrupt"), nil
+			}
+			return s.objects[key], nil
+		},
+		DeleteFunc: func(_ context.Context, key string) error {
+			if s.failing["delete"] {
+				return errors.New("AccessDenied")
+			}
+			delete(s.objects, key)
+			return nil
+		},
+	}
+}
+
+func TestNewBlobRoundTripCheck_config(t *testing.T) {
+	_, err := NewBlobRoundTripCheck(checkName, nil, "health/")
+	assert.EqualError(t, err, "client must not be nil")
+
+	_, err = NewBlobRoundTripCheck("", newFakeBlobStore().client(), "health/")
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewBlobRoundTripCheck(t *testing.T) {
+	store := newFakeBlobStore()
+	check, err := NewBlobRoundTripCheck(checkName, store.client(), "health/")
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	blobDetails := details.(BlobRoundTripDetails)
+	assert.True(t, strings.HasPrefix(blobDetails.Key, "health/"+checkName+"-"), "key")
+	assert.True(t, blobDetails.WriteLatency > 0, "write latency")
+	assert.Empty(t, store.objects, "the object is deleted")
+
+	nextDetails, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.NotEqual(t, blobDetails.Key, nextDetails.(BlobRoundTripDetails).Key, "unique keys")
+
+	store.corrupt = true
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "read '"+details.(BlobRoundTripDetails).Key+"' returned 7 bytes of unexpected content")
+	assert.Empty(t, store.objects, "the object is deleted")
+}
+
+func TestNewBlobRoundTripCheck_failures(t *testing.T) {
+	for _, op := range []string{"put", "get", "delete"} {
+		store := newFakeBlobStore(op)
+		check, err := NewBlobRoundTripCheck(checkName, store.client(), "")
+		require.NoError(t, err)
+
+		details, err := check.Execute(context.Background())
+		key := details.(BlobRoundTripDetails).Key
+		switch op {
+		case "put":
+			assert.EqualError(t, err, "failed to write '"+key+"': AccessDenied")
+		case "get":
+			assert.EqualError(t, err, "failed to read '"+key+"': connection reset")
+			assert.Empty(t, store.objects, "the object is deleted")
+		case "delete":
+			assert.EqualError(t, err, "failed to delete '"+key+"': AccessDenied")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// DefaultCassandraQuery is the lightweight query executed by the Cassandra check by default
+const DefaultCassandraQuery = "SELECT release_version FROM system.local"
+
+// CassandraSession is the minimal Cassandra / ScyllaDB session API required by the Cassandra check,
+// so that any driver can be used, e.g. with gocql, by executing session.Query(query).WithContext(ctx) with an observer
+// that records the coordinator host.
+type CassandraSession interface {
+	// QueryRow executes the given query, scans the first result row into dest,
+	// and returns the address of the coordinator that served the query.
+	QueryRow(ctx context.Context, query string, dest ...interface{}) (coordinator string, err error)
+}
+
+// CassandraDetails are the details of a Cassandra check
+type CassandraDetails struct {
+	// Coordinator is the address of the coordinator that served the query
+	Coordinator string `json:"coordinator,omitempty"`
+	// Latency is the duration of the query
+	Latency time.Duration `json:"latency"`
+	// Result is the first column of the first result row
+	Result string `json:"result,omitempty"`
+}
+
+// CassandraCheckOption configures a Cassandra check
+type CassandraCheckOption func(c *cassandraCheck)
+
+// WithCassandraQuery sets the query executed by the Cassandra check, which must return at least one row.
+// Defaults to DefaultCassandraQuery
+func WithCassandraQuery(query string) CassandraCheckOption {
+	return func(c *cassandraCheck) {
+		c.query = query
+	}
+}
+
+type cassandraCheck struct {
+	name    string
+	session CassandraSession
+	query   string
+}
+
+// NewCassandraCheck returns a Check that executes a lightweight query using the given session, and fails on timeout or error.
+// The coordinator, latency and result of the query are reported as the check details.
+func NewCassandraCheck(name string, session CassandraSession, opts ...CassandraCheckOption) (gosundheit.Check, error) {
+	if session == nil {
+		return nil, errors.New("session must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &cassandraCheck{name: name, session: session, query: DefaultCassandraQuery}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *cassandraCheck) Name() string {
+	return c.name
+}
+
+func (c *cassandraCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var cassandraDetails CassandraDetails
+
+	start := time.Now()
+	cassandraDetails.Coordinator, err = c.session.QueryRow(ctx, c.query, &cassandraDetails.Result)
+	cassandraDetails.Latency = time.Since(start)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return cassandraDetails, errors.Wrap(err, "query failed")
+	}
+
+	return cassandraDetails, nil
+}
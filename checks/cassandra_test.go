@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cassandraQueryFunc func(ctx context.Context, query string, dest ...interface{}) (string, error)
+
+func (f cassandraQueryFunc) QueryRow(ctx context.Context, query string, dest ...interface{}) (string, error) {
+	return f(ctx, query, dest...)
+}
+
+func TestNewCassandraCheck_nilSession(t *testing.T) {
+	check, err := NewCassandraCheck(checkName, nil)
+	assert.EqualError(t, err, "session must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewCassandraCheck(t *testing.T) {
+	var executedQuery string
+	session := cassandraQueryFunc(func(_ context.Context, query string, dest ...interface{}) (string, error) {
+		executedQuery = query
+		*dest[0].(*string) = "3.11.4"
+		return "10.0.0.1:9042", nil
+	})
+
+	check, err := NewCassandraCheck(checkName, session)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCassandraQuery, executedQuery)
+	assert.Equal(t, "10.0.0.1:9042", details.(CassandraDetails).Coordinator)
+	assert.Equal(t, "3.11.4", details.(CassandraDetails).Result)
+
+	check, err = NewCassandraCheck(checkName, session, WithCassandraQuery("SELECT now() FROM system.local"))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT now() FROM system.local", executedQuery)
+}
+
+func TestNewCassandraCheck_failure(t *testing.T) {
+	check, _ := NewCassandraCheck(checkName, cassandraQueryFunc(func(_ context.Context, _ string, _ ...interface{}) (string, error) {
+		return "", errors.New("no hosts available in the pool")
+	}))
+	_, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "query failed: no hosts available in the pool")
+
+	check, _ = NewCassandraCheck(checkName, cassandraQueryFunc(func(ctx context.Context, _ string, _ ...interface{}) (string, error) {
+		// a session that does not respect the context
+		return "10.0.0.1:9042", nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = check.Execute(ctx)
+	assert.EqualError(t, err, "query failed: context canceled", "fails on timeout")
+}
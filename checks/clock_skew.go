@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ClockSkewCheckConfig configures a check for the skew of the local clock, relative to the Date header returned by a trusted endpoint.
+type ClockSkewCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the required URL of a trusted endpoint, whose responses have an accurate Date header.
+	URL string
+	// MaxSkew is the maximum allowed skew in either direction, defaults to 5 seconds.
+	// Since the Date header has a resolution of one second, it must be at least a second.
+	MaxSkew time.Duration
+	// Method is the HTTP method to use for this check, defaults to `HEAD`.
+	Method string
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+// ClockSkewDetails are the details of a clock skew check
+type ClockSkewDetails struct {
+	// Skew is the estimated offset of the local clock from the server clock, positive when the local clock is ahead
+	Skew time.Duration `json:"skew"`
+	// ServerTime is the time of the Date header
+	ServerTime time.Time `json:"serverTime"`
+	// RoundTrip is the duration of the request
+	RoundTrip time.Duration `json:"roundTrip"`
+}
+
+type clockSkewCheck struct {
+	config ClockSkewCheckConfig
+}
+
+// NewClockSkewCheck returns a Check that compares the local time against the Date header of a trusted endpoint,
+// and fails when the skew exceeds the configured maximum. It is a zero-dependency alternative to querying NTP servers,
+// e.g. for locked-down networks. The estimated skew is reported as the check details.
+func NewClockSkewCheck(config ClockSkewCheckConfig) (gosundheit.Check, error) {
+	if config.URL == "" {
+		return nil, errors.New("URL must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.MaxSkew == 0 {
+		config.MaxSkew = 5 * time.Second
+	}
+	if config.MaxSkew < time.Second {
+		return nil, errors.New("MaxSkew must be at least a second")
+	}
+	if config.Method == "" {
+		config.Method = http.MethodHead
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &clockSkewCheck{config: config}, nil
+}
+
+func (c *clockSkewCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *clockSkewCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	req, err := http.NewRequestWithContext(ctx, c.config.Method, c.config.URL, nil)
+	if err != nil {
+		return nil, errors.Errorf("unable to create check HTTP request: %v", err)
+	}
+	configureHTTPOptions(req, c.config.Options)
+
+	start := time.Now()
+	resp, err := c.config.Client.Do(req)
+	roundTrip := time.Since(start)
+	if err != nil {
+		return nil, errors.Errorf("fail to execute '%v' request: %v", c.config.Method, err)
+	}
+	_ = resp.Body.Close()
+
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return nil, errors.New("the response has no Date header")
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return nil, errors.Errorf("invalid Date header '%s': %v", date, err)
+	}
+
+	// the server time is estimated at the middle of the round trip, and of the second of the Date header, which is truncated
+	localTime := start.Add(roundTrip / 2)
+	skewDetails := ClockSkewDetails{
+		Skew:       localTime.Sub(serverTime.Add(500 * time.Millisecond)).Round(time.Millisecond),
+		ServerTime: serverTime,
+		RoundTrip:  roundTrip,
+	}
+	if skewDetails.Skew > c.config.MaxSkew || skewDetails.Skew < -c.config.MaxSkew {
+		return skewDetails, errors.Errorf("clock skew of %v exceeds the maximum of %v", skewDetails.Skew, c.config.MaxSkew)
+	}
+	return skewDetails, nil
+}
@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClockSkewCheck_config(t *testing.T) {
+	_, err := NewClockSkewCheck(ClockSkewCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "URL must not be empty")
+
+	_, err = NewClockSkewCheck(ClockSkewCheckConfig{URL: "https://www.example.com"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewClockSkewCheck(ClockSkewCheckConfig{CheckName: checkName, URL: "https://www.example.com", MaxSkew: time.Millisecond})
+	assert.EqualError(t, err, "MaxSkew must be at least a second")
+
+	check, err := NewClockSkewCheck(ClockSkewCheckConfig{CheckName: checkName, URL: "https://www.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	assert.Equal(t, 5*time.Second, check.(*clockSkewCheck).config.MaxSkew, "default max skew")
+	assert.Equal(t, http.MethodHead, check.(*clockSkewCheck).config.Method, "default method")
+}
+
+func TestNewClockSkewCheck(t *testing.T) {
+	var offset time.Duration
+	var date *string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodHead, req.Method)
+		if date != nil {
+			rw.Header()["Date"] = []string{*date}
+		} else {
+			rw.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		}
+	}))
+	defer server.Close()
+
+	check, err := NewClockSkewCheck(ClockSkewCheckConfig{CheckName: checkName, URL: server.URL, MaxSkew: 2 * time.Second})
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	skew := details.(ClockSkewDetails).Skew
+	assert.True(t, skew <= time.Second && skew >= -time.Second, "skew %v", skew)
+
+	offset = -time.Hour
+	details, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the maximum of 2s")
+	skew = details.(ClockSkewDetails).Skew
+	assert.True(t, skew > 59*time.Minute && skew < 61*time.Minute, "the local clock is ahead by an hour: %v", skew)
+
+	invalid := "yesterday"
+	date = &invalid
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Date header 'yesterday'")
+
+	date = new(string)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "the response has no Date header")
+}
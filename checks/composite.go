@@ -0,0 +1,137 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// CompositeChildResult is the outcome of a child check of a composite check
+type CompositeChildResult struct {
+	Name    string      `json:"name"`
+	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// CompositeCheck is a check that executes child checks, and aggregates their outcomes into a single result.
+// See All and Any.
+type CompositeCheck struct {
+	name   string
+	checks []gosundheit.Check
+	// minPassing is the minimum number of passing checks, or zero when all of them must pass
+	minPassing int
+	parallel   bool
+}
+
+var _ gosundheit.Check = (*CompositeCheck)(nil)
+
+// All returns a check that passes when all of the given checks pass.
+// The outcome of each child check is reported in the check details.
+func All(name string, checks ...gosundheit.Check) (*CompositeCheck, error) {
+	return newCompositeCheck(name, 0, checks)
+}
+
+// Any returns a check that passes when at least one of the given checks passes.
+// The outcome of each child check is reported in the check details.
+func Any(name string, checks ...gosundheit.Check) (*CompositeCheck, error) {
+	return newCompositeCheck(name, 1, checks)
+}
+
+// AtLeast returns a check that passes when at least n of the given checks pass, where n is between 1 and the number of checks.
+// The outcome of each child check is reported in the check details.
+func AtLeast(name string, n int, checks ...gosundheit.Check) (*CompositeCheck, error) {
+	if n < 1 || n > len(checks) {
+		return nil, errors.Errorf("n must be between 1 and the number of checks (%d)", len(checks))
+	}
+	return newCompositeCheck(name, n, checks)
+}
+
+func newCompositeCheck(name string, minPassing int, checks []gosundheit.Check) (*CompositeCheck, error) {
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+	if len(checks) == 0 {
+		return nil, errors.New("checks must not be empty")
+	}
+	for i, check := range checks {
+		if check == nil {
+			return nil, errors.Errorf("check %d must not be nil", i)
+		}
+	}
+	return &CompositeCheck{name: name, checks: checks, minPassing: minPassing}, nil
+}
+
+// InParallel sets the child checks to be executed concurrently rather than one after the other, and returns the check.
+func (c *CompositeCheck) InParallel() *CompositeCheck {
+	c.parallel = true
+	return c
+}
+
+// Name is the name of the check.
+func (c *CompositeCheck) Name() string {
+	return c.name
+}
+
+// Execute executes all the child checks, and returns their outcomes as the details.
+func (c *CompositeCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	results := make([]CompositeChildResult, len(c.checks))
+	execute := func(i int) {
+		// a panicking child must not take down the process when executed in its own goroutine, so it is reported as a failure instead.
+		// The child name is resolved before executing it, so that the failure is reported without calling the child again
+		defer func() {
+			if r := recover(); r != nil {
+				if results[i].Name == "" {
+					results[i].Name = fmt.Sprintf("check %d", i)
+				}
+				results[i].Details = nil
+				results[i].Error = fmt.Sprintf("check panicked: %v", r)
+			}
+		}()
+		results[i].Name = c.checks[i].Name()
+		childDetails, childErr := c.checks[i].Execute(ctx)
+		results[i].Details = childDetails
+		if childErr != nil {
+			results[i].Error = childErr.Error()
+		}
+	}
+
+	if c.parallel {
+		var wg sync.WaitGroup
+		for i := range c.checks {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				execute(i)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range c.checks {
+			execute(i)
+		}
+	}
+
+	var failed []string
+	for _, result := range results {
+		if result.Error != "" {
+			failed = append(failed, result.Name)
+		}
+	}
+
+	passing := len(c.checks) - len(failed)
+	switch {
+	case c.minPassing == 0 && len(failed) > 0:
+		err = errors.Errorf("%d of %d checks failed: %s", len(failed), len(c.checks), strings.Join(failed, ", "))
+	case c.minPassing == 1 && passing == 0:
+		err = errors.Errorf("all %d checks failed: %s", len(c.checks), strings.Join(failed, ", "))
+	case passing < c.minPassing:
+		err = errors.Errorf("%d of %d checks passed, but at least %d are required, failed: %s",
+			passing, len(c.checks), c.minPassing, strings.Join(failed, ", "))
+	}
+	return results, err
+}
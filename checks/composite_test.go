@@ -0,0 +1,151 @@
+package checks
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+func childCheck(name string, err error) gosundheit.Check {
+	return &CustomCheck{
+		CheckName: name,
+		CheckFunc: func(context.Context) (interface{}, error) {
+			return name + " details", err
+		},
+	}
+}
+
+func TestAll(t *testing.T) {
+	check := Must(All(checkName, childCheck("a", nil), childCheck("b", nil)))
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []CompositeChildResult{
+		{Name: "a", Details: "a details"},
+		{Name: "b", Details: "b details"},
+	}, details)
+
+	check = Must(All(checkName, childCheck("a", nil), childCheck("b", errors.New("b failed")), childCheck("c", errors.New("c failed"))))
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "2 of 3 checks failed: b, c")
+	assert.Equal(t, []CompositeChildResult{
+		{Name: "a", Details: "a details"},
+		{Name: "b", Details: "b details", Error: "b failed"},
+		{Name: "c", Details: "c details", Error: "c failed"},
+	}, details)
+}
+
+func TestAny(t *testing.T) {
+	check := Must(Any(checkName, childCheck("a", errors.New("a failed")), childCheck("b", nil)))
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []CompositeChildResult{
+		{Name: "a", Details: "a details", Error: "a failed"},
+		{Name: "b", Details: "b details"},
+	}, details)
+
+	check = Must(Any(checkName, childCheck("a", errors.New("a failed")), childCheck("b", errors.New("b failed"))))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "all 2 checks failed: a, b")
+}
+
+func TestCompositeCheck_InParallel(t *testing.T) {
+	var running, maxRunning int32
+	slowCheck := func(name string) gosundheit.Check {
+		return &CustomCheck{
+			CheckName: name,
+			CheckFunc: func(context.Context) (interface{}, error) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil, nil
+			},
+		}
+	}
+
+	_, err := Must(All(checkName, slowCheck("a"), slowCheck("b"), slowCheck("c"))).Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxRunning), "sequential by default")
+
+	check, err := All(checkName, slowCheck("a"), slowCheck("b"), slowCheck("c"))
+	assert.NoError(t, err)
+	details, err := check.InParallel().Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&maxRunning), "in parallel")
+	assert.Equal(t, []CompositeChildResult{{Name: "a"}, {Name: "b"}, {Name: "c"}}, details, "results are in the checks order")
+}
+
+func TestCompositeCheck_InParallelPanic(t *testing.T) {
+	panicking := &CustomCheck{
+		CheckName: "b",
+		CheckFunc: func(context.Context) (interface{}, error) {
+			panic("boom")
+		},
+	}
+
+	check, err := All(checkName, childCheck("a", nil), panicking)
+	assert.NoError(t, err)
+	details, err := check.InParallel().Execute(context.Background())
+	assert.EqualError(t, err, "1 of 2 checks failed: b")
+	assert.Equal(t, []CompositeChildResult{{Name: "a", Details: "a details"}, {Name: "b", Error: "check panicked: boom"}}, details)
+}
+
+func TestAtLeast(t *testing.T) {
+	children := []gosundheit.Check{childCheck("a", nil), childCheck("b", errors.New("b failed")), childCheck("c", nil)}
+
+	_, err := Must(AtLeast(checkName, 2, children...)).Execute(context.Background())
+	assert.NoError(t, err)
+
+	details, err := Must(AtLeast(checkName, 3, children...)).Execute(context.Background())
+	assert.EqualError(t, err, "2 of 3 checks passed, but at least 3 are required, failed: b")
+	assert.Len(t, details, 3)
+}
+
+func TestCompositeCheck_invalid(t *testing.T) {
+	_, err := All("", childCheck("a", nil))
+	assert.EqualError(t, err, "check name must not be empty")
+
+	_, err = All(checkName, childCheck("a", nil), nil)
+	assert.EqualError(t, err, "check 1 must not be nil")
+
+	_, err = Any(checkName)
+	assert.EqualError(t, err, "checks must not be empty")
+
+	_, err = AtLeast(checkName, 0, childCheck("a", nil))
+	assert.EqualError(t, err, "n must be between 1 and the number of checks (1)")
+
+	_, err = AtLeast(checkName, 2, childCheck("a", nil))
+	assert.EqualError(t, err, "n must be between 1 and the number of checks (1)")
+}
+
+func TestCompositeCheck_panickingName(t *testing.T) {
+	check, err := All(checkName, childCheck("a", nil), panickingNameCheck{})
+	assert.NoError(t, err)
+
+	details, err := check.InParallel().Execute(context.Background())
+	assert.EqualError(t, err, "1 of 2 checks failed: check 1")
+	assert.Equal(t, []CompositeChildResult{{Name: "a", Details: "a details"}, {Name: "check 1", Error: "check panicked: boom"}}, details)
+}
+
+type panickingNameCheck struct{}
+
+func (panickingNameCheck) Name() string {
+	panic("boom")
+}
+
+func (panickingNameCheck) Execute(context.Context) (interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,160 @@
+package checks
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ConfigValidator validates a configuration value, returning an error when it is invalid
+type ConfigValidator func(value string) error
+
+// ConfigRequirement is a required environment variable or file, see RequiredEnv and RequiredFile.
+type ConfigRequirement struct {
+	// Env is the name of a required environment variable
+	Env string
+	// File is the path of a required file
+	File string
+	// Validators validate the environment variable value or the file content, which must not be empty
+	Validators []ConfigValidator
+}
+
+// RequiredEnv returns a requirement for an environment variable that is set, non-empty and passes the given validators
+func RequiredEnv(name string, validators ...ConfigValidator) ConfigRequirement {
+	return ConfigRequirement{Env: name, Validators: validators}
+}
+
+// RequiredFile returns a requirement for a file that exists, is non-empty and its content passes the given validators
+func RequiredFile(path string, validators ...ConfigValidator) ConfigRequirement {
+	return ConfigRequirement{File: path, Validators: validators}
+}
+
+// ValidURL is a ConfigValidator for absolute URLs
+func ValidURL(value string) error {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return errors.New("not an absolute URL")
+	}
+	return nil
+}
+
+// ValidInt is a ConfigValidator for integers
+func ValidInt(value string) error {
+	_, err := strconv.Atoi(strings.TrimSpace(value))
+	return err
+}
+
+// ValidBool is a ConfigValidator for booleans, as accepted by strconv.ParseBool
+func ValidBool(value string) error {
+	_, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err
+}
+
+// ValidDuration is a ConfigValidator for durations, as accepted by time.ParseDuration
+func ValidDuration(value string) error {
+	_, err := time.ParseDuration(strings.TrimSpace(value))
+	return err
+}
+
+// ValidOneOf returns a ConfigValidator for values that are one of the given values
+func ValidOneOf(values ...string) ConfigValidator {
+	return func(value string) error {
+		for _, v := range values {
+			if strings.TrimSpace(value) == v {
+				return nil
+			}
+		}
+		return errors.Errorf("not one of %v", values)
+	}
+}
+
+type configCheck struct {
+	name         string
+	requirements []ConfigRequirement
+}
+
+// NewRequiredConfigCheck returns a Check that validates the given required environment variables and files at runtime,
+// which catches configuration drift, e.g. after hot reloads. The check fails listing the missing or invalid ones,
+// and their problems are reported as the check details.
+func NewRequiredConfigCheck(name string, requirements ...ConfigRequirement) (gosundheit.Check, error) {
+	if len(requirements) == 0 {
+		return nil, errors.New("requirements must not be empty")
+	}
+	for _, requirement := range requirements {
+		if (requirement.Env == "") == (requirement.File == "") {
+			return nil, errors.New("exactly one of Env and File must be set in each requirement")
+		}
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &configCheck{name: name, requirements: requirements}, nil
+}
+
+func (c *configCheck) Name() string {
+	return c.name
+}
+
+func (c *configCheck) Execute(_ context.Context) (details interface{}, err error) {
+	problems := make(map[string]string)
+	for _, requirement := range c.requirements {
+		if key, problem := requirement.check(); problem != "" {
+			problems[key] = problem
+		}
+	}
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(problems))
+	for key := range problems {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return problems, errors.Errorf("missing or invalid configuration: %s", strings.Join(keys, ", "))
+}
+
+// check returns the requirement key (the environment variable name or file path), and its problem, if any
+func (r ConfigRequirement) check() (key string, problem string) {
+	var value string
+	if r.Env != "" {
+		key = r.Env
+		var ok bool
+		if value, ok = os.LookupEnv(r.Env); !ok {
+			return key, "not set"
+		}
+	} else {
+		key = r.File
+		content, err := ioutil.ReadFile(r.File)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return key, "does not exist"
+			}
+			return key, err.Error()
+		}
+		value = string(content)
+	}
+
+	if strings.TrimSpace(value) == "" {
+		return key, "empty"
+	}
+	for _, validate := range r.Validators {
+		if err := validate(value); err != nil {
+			return key, "invalid: " + err.Error()
+		}
+	}
+	return key, ""
+}
@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequiredConfigCheck_config(t *testing.T) {
+	_, err := NewRequiredConfigCheck(checkName)
+	assert.EqualError(t, err, "requirements must not be empty")
+
+	_, err = NewRequiredConfigCheck(checkName, ConfigRequirement{})
+	assert.EqualError(t, err, "exactly one of Env and File must be set in each requirement")
+
+	_, err = NewRequiredConfigCheck("", RequiredEnv("HOME"))
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewRequiredConfigCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-check")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, ioutil.WriteFile(emptyFile, nil, 0600))
+	missingFile := filepath.Join(dir, "missing")
+
+	for key, value := range map[string]string{
+		"SUNDHEIT_TEST_URL":      "https://api.example.com/v1",
+		"SUNDHEIT_TEST_PORT":     "8080",
+		"SUNDHEIT_TEST_TIMEOUT":  "5s",
+		"SUNDHEIT_TEST_DEBUG":    "true",
+		"SUNDHEIT_TEST_ENV":      "production",
+		"SUNDHEIT_TEST_EMPTY":    " ",
+		"SUNDHEIT_TEST_BAD_PORT": "http",
+	} {
+		require.NoError(t, os.Setenv(key, value))
+		defer func(key string) { _ = os.Unsetenv(key) }(key)
+	}
+
+	check, err := NewRequiredConfigCheck(checkName,
+		RequiredEnv("SUNDHEIT_TEST_URL", ValidURL),
+		RequiredEnv("SUNDHEIT_TEST_PORT", ValidInt),
+		RequiredEnv("SUNDHEIT_TEST_TIMEOUT", ValidDuration),
+		RequiredEnv("SUNDHEIT_TEST_DEBUG", ValidBool),
+		RequiredEnv("SUNDHEIT_TEST_ENV", ValidOneOf("staging", "production")),
+		RequiredFile(tokenFile),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	check, err = NewRequiredConfigCheck(checkName,
+		RequiredEnv("SUNDHEIT_TEST_URL", ValidURL),
+		RequiredEnv("SUNDHEIT_TEST_MISSING"),
+		RequiredEnv("SUNDHEIT_TEST_EMPTY"),
+		RequiredEnv("SUNDHEIT_TEST_BAD_PORT", ValidInt),
+		RequiredEnv("SUNDHEIT_TEST_PORT", ValidURL),
+		RequiredFile(emptyFile),
+		RequiredFile(missingFile),
+	)
+	require.NoError(t, err)
+
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "missing or invalid configuration: "+emptyFile+", "+missingFile+
+		", SUNDHEIT_TEST_BAD_PORT, SUNDHEIT_TEST_EMPTY, SUNDHEIT_TEST_MISSING, SUNDHEIT_TEST_PORT")
+	assert.Equal(t, map[string]string{
+		"SUNDHEIT_TEST_MISSING":  "not set",
+		"SUNDHEIT_TEST_EMPTY":    "empty",
+		"SUNDHEIT_TEST_BAD_PORT": `invalid: strconv.Atoi: parsing "http": invalid syntax`,
+		"SUNDHEIT_TEST_PORT":     "invalid: not an absolute URL",
+		emptyFile:                "empty",
+		missingFile:              "does not exist",
+	}, details)
+}
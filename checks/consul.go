@@ -0,0 +1,126 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ConsulCheckConfig configures a check for the health of a Consul agent, and optionally of a service in the Consul catalog.
+type ConsulCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the base URL of the Consul agent, defaults to "http://127.0.0.1:8500"
+	URL string
+	// Token is an optional ACL token
+	Token string
+	// Service is optional; if defined, the check verifies that the service has at least `MinPassingInstances` passing instances.
+	Service string
+	// MinPassingInstances is the minimum number of passing instances of `Service`, defaults to 1.
+	MinPassingInstances int
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP requests, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP requests with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+// ConsulDetails are the details of a Consul check
+type ConsulDetails struct {
+	// NodeName is the name of the node of the agent
+	NodeName string `json:"nodeName"`
+	// PassingInstances is the number of passing instances of the checked service, if any
+	PassingInstances int `json:"passingInstances,omitempty"`
+}
+
+// consulMemberAlive is the status of a live member in the Consul gossip pool
+const consulMemberAlive = 1
+
+type consulAgentSelf struct {
+	Member struct {
+		Name   string
+		Status int
+	}
+}
+
+type consulCheck struct {
+	config     ConsulCheckConfig
+	selfURL    string
+	serviceURL string
+	options    []RequestOption
+}
+
+// NewConsulCheck returns a Check that verifies that the Consul agent is alive, and optionally, that a service
+// has enough passing instances in the catalog (see ConsulCheckConfig.Service).
+func NewConsulCheck(config ConsulCheckConfig) (gosundheit.Check, error) {
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.URL == "" {
+		config.URL = "http://127.0.0.1:8500"
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if config.MinPassingInstances == 0 {
+		config.MinPassingInstances = 1
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	options := config.Options
+	if config.Token != "" {
+		options = append([]RequestOption{func(r *http.Request) {
+			r.Header.Set("X-Consul-Token", config.Token)
+		}}, options...)
+	}
+
+	baseURL := strings.TrimSuffix(config.URL, "/")
+	return &consulCheck{
+		config:     config,
+		selfURL:    baseURL + "/v1/agent/self",
+		serviceURL: baseURL + "/v1/health/service/" + url.PathEscape(config.Service) + "?passing",
+		options:    options,
+	}, nil
+}
+
+func (c *consulCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *consulCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var self consulAgentSelf
+	if err := fetchJSON(ctx, c.config.Client, c.selfURL, c.options, &self); err != nil {
+		return nil, errors.Wrap(err, "failed to query the agent")
+	}
+	consulDetails := ConsulDetails{NodeName: self.Member.Name}
+	if self.Member.Status != consulMemberAlive {
+		return consulDetails, errors.Errorf("agent member status is %d", self.Member.Status)
+	}
+	if c.config.Service == "" {
+		return consulDetails, nil
+	}
+
+	var instances []interface{}
+	if err := fetchJSON(ctx, c.config.Client, c.serviceURL, c.options, &instances); err != nil {
+		return consulDetails, errors.Wrapf(err, "failed to query service '%s'", c.config.Service)
+	}
+	consulDetails.PassingInstances = len(instances)
+	if len(instances) < c.config.MinPassingInstances {
+		return consulDetails, errors.Errorf("service '%s' has %d passing instances, but requires at least %d",
+			c.config.Service, len(instances), c.config.MinPassingInstances)
+	}
+	return consulDetails, nil
+}
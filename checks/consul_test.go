@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consulServer(memberStatus int, passingInstances int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, passing := r.URL.Query()["passing"]
+		switch {
+		case r.URL.Path == "/v1/agent/self":
+			_, _ = fmt.Fprintf(w, `{"Member":{"Name":"node-1","Status":%d}}`, memberStatus)
+		case r.URL.Path == "/v1/health/service/api" && passing:
+			_, _ = fmt.Fprintf(w, "[%s]", strings.TrimSuffix(strings.Repeat(`{"Service":{"ID":"api"}},`, passingInstances), ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestNewConsulCheck_requiredFields(t *testing.T) {
+	_, err := NewConsulCheck(ConsulCheckConfig{})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewConsulCheck_agent(t *testing.T) {
+	server := consulServer(1, 0)
+	defer server.Close()
+
+	check, err := NewConsulCheck(ConsulCheckConfig{CheckName: checkName, URL: server.URL, Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ConsulDetails{NodeName: "node-1"}, details)
+
+	check, _ = NewConsulCheck(ConsulCheckConfig{CheckName: checkName, URL: server.URL})
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to query the agent: unexpected status code: '403' expected: '[200]'")
+
+	leftServer := consulServer(3, 0)
+	defer leftServer.Close()
+	check, _ = NewConsulCheck(ConsulCheckConfig{CheckName: checkName, URL: leftServer.URL, Token: "secret"})
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "agent member status is 3")
+}
+
+func TestNewConsulCheck_service(t *testing.T) {
+	server := consulServer(1, 2)
+	defer server.Close()
+
+	check, err := NewConsulCheck(ConsulCheckConfig{CheckName: checkName, URL: server.URL, Token: "secret", Service: "api", MinPassingInstances: 2})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ConsulDetails{NodeName: "node-1", PassingInstances: 2}, details)
+
+	check, _ = NewConsulCheck(ConsulCheckConfig{CheckName: checkName, URL: server.URL, Token: "secret", Service: "api", MinPassingInstances: 3})
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "service 'api' has 2 passing instances, but requires at least 3")
+}
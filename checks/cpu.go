@@ -0,0 +1,172 @@
+package checks
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// CPUCheckConfig configures a check for the CPU utilization of the process and/or the system.
+// CPU utilization is only supported on Linux.
+type CPUCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// MaxProcessPercent is the maximum CPU utilization of the process, as a percentage of all the CPUs.
+	// When zero, the process CPU utilization is not sampled.
+	MaxProcessPercent float64
+	// MaxSystemPercent is the maximum CPU utilization of the system, as a percentage of all the CPUs.
+	// When zero, the system CPU utilization is not sampled.
+	MaxSystemPercent float64
+	// Samples is the number of recent samples reported as the check details, defaults to 5.
+	Samples int
+}
+
+// CPUDetails are the recent CPU utilization samples of a CPU check, in percents, from oldest to newest
+type CPUDetails struct {
+	Process []float64 `json:"process,omitempty"`
+	System  []float64 `json:"system,omitempty"`
+}
+
+// cpuTimes are cumulative CPU times, in arbitrary but consistent units
+type cpuTimes struct {
+	busy  float64
+	total float64
+}
+
+// processCPUReader reads the CPU time used by the process, relative to the wall time of all the CPUs since the reader was created
+type processCPUReader struct {
+	// start is a monotonic reading of the clock, so that the wall time deltas are small enough to be multiplied by the number of CPUs
+	start  time.Time
+	now    func() time.Time
+	numCPU int
+	busy   func() (time.Duration, error)
+}
+
+func newProcessCPUReader() *processCPUReader {
+	return &processCPUReader{start: time.Now(), now: time.Now, numCPU: runtime.NumCPU(), busy: readProcessCPUTime}
+}
+
+func (r *processCPUReader) read() (cpuTimes, error) {
+	busy, err := r.busy()
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	wall := float64(r.now().Sub(r.start)) * float64(r.numCPU)
+	return cpuTimes{busy: float64(busy), total: wall}, nil
+}
+
+// cpuSampler computes the CPU utilization between consecutive readings
+type cpuSampler struct {
+	read    func() (cpuTimes, error)
+	prev    cpuTimes
+	sampled bool
+	samples []float64
+}
+
+func (s *cpuSampler) sample(maxSamples int) error {
+	times, err := s.read()
+	if err != nil {
+		return err
+	}
+	if total := times.total - s.prev.total; s.sampled && total > 0 {
+		// clamped, since the busy time granularity may exceed short sampled periods
+		s.samples = append(s.samples, math.Max(0, math.Min(100, 100*(times.busy-s.prev.busy)/total)))
+		if len(s.samples) > maxSamples {
+			s.samples = s.samples[len(s.samples)-maxSamples:]
+		}
+	}
+	s.prev, s.sampled = times, true
+	return nil
+}
+
+func (s *cpuSampler) latest() float64 {
+	if len(s.samples) == 0 {
+		return 0
+	}
+	return s.samples[len(s.samples)-1]
+}
+
+type cpuCheck struct {
+	config CPUCheckConfig
+
+	lock    sync.Mutex
+	process *cpuSampler
+	system  *cpuSampler
+}
+
+// NewCPUCheck returns a Check that samples the CPU utilization of the process and/or the system, and fails when the latest
+// sample exceeds the configured maximum. Each execution samples the utilization since the previous one (or since the check creation),
+// so the sampled period is the check execution period. The recent samples are reported as the check details.
+func NewCPUCheck(config CPUCheckConfig) (gosundheit.Check, error) {
+	if config.MaxProcessPercent <= 0 && config.MaxSystemPercent <= 0 {
+		return nil, errors.New("at least one of MaxProcessPercent and MaxSystemPercent must be set")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Samples <= 0 {
+		config.Samples = 5
+	}
+
+	check := &cpuCheck{config: config}
+	if config.MaxProcessPercent > 0 {
+		check.process = &cpuSampler{read: newProcessCPUReader().read}
+	}
+	if config.MaxSystemPercent > 0 {
+		check.system = &cpuSampler{read: readSystemCPUTimes}
+	}
+	if err := check.init(); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// init takes the initial readings, which the first execution samples are relative to
+func (c *cpuCheck) init() error {
+	for _, sampler := range []*cpuSampler{c.process, c.system} {
+		if sampler != nil {
+			if err := sampler.sample(c.config.Samples); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *cpuCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *cpuCheck) Execute(_ context.Context) (details interface{}, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var cpu CPUDetails
+	if c.process != nil {
+		if err := c.process.sample(c.config.Samples); err != nil {
+			return nil, err
+		}
+		cpu.Process = append([]float64{}, c.process.samples...)
+		if usage := c.process.latest(); usage > c.config.MaxProcessPercent {
+			err = errors.Errorf("process CPU utilization of %.1f%% exceeds the maximum of %.1f%%", usage, c.config.MaxProcessPercent)
+		}
+	}
+	if c.system != nil {
+		if sampleErr := c.system.sample(c.config.Samples); sampleErr != nil {
+			return nil, sampleErr
+		}
+		cpu.System = append([]float64{}, c.system.samples...)
+		if usage := c.system.latest(); err == nil && usage > c.config.MaxSystemPercent {
+			err = errors.Errorf("system CPU utilization of %.1f%% exceeds the maximum of %.1f%%", usage, c.config.MaxSystemPercent)
+		}
+	}
+
+	return cpu, err
+}
@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// readProcessCPUTime reads the CPU time used by the process
+func readProcessCPUTime() (time.Duration, error) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, errors.Errorf("failed to read process CPU usage: %v", err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), nil
+}
+
+// readSystemCPUTimes reads the aggregate CPU times of the system from /proc/stat
+func readSystemCPUTimes() (cpuTimes, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuTimes{}, errors.Errorf("failed to read system CPU usage: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		// user nice system idle iowait irq softirq steal ...
+		var times cpuTimes
+		for i, field := range fields[1:] {
+			// guest times (from the 9th column) are already included in the user times
+			if i >= 8 {
+				break
+			}
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return cpuTimes{}, errors.Errorf("failed to parse system CPU usage: %v", err)
+			}
+			times.total += value
+			if i != 3 && i != 4 {
+				times.busy += value
+			}
+		}
+		return times, nil
+	}
+	return cpuTimes{}, errors.New("failed to read system CPU usage: no cpu line in /proc/stat")
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var errCPUUnsupported = errors.New("CPU utilization is only supported on Linux")
+
+func readProcessCPUTime() (time.Duration, error) {
+	return 0, errCPUUnsupported
+}
+
+func readSystemCPUTimes() (cpuTimes, error) {
+	return cpuTimes{}, errCPUUnsupported
+}
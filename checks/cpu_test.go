@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCPUCheck_config(t *testing.T) {
+	_, err := NewCPUCheck(CPUCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "at least one of MaxProcessPercent and MaxSystemPercent must be set")
+
+	_, err = NewCPUCheck(CPUCheckConfig{MaxProcessPercent: 80})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewCPUCheck(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("CPU utilization is only supported on Linux")
+	}
+
+	check, err := NewCPUCheck(CPUCheckConfig{CheckName: checkName, MaxProcessPercent: 100, MaxSystemPercent: 100})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	cpu := details.(CPUDetails)
+	require.Len(t, cpu.Process, 1)
+	assert.True(t, cpu.Process[0] >= 0 && cpu.Process[0] <= 100, "process utilization %v", cpu.Process[0])
+	for _, sample := range cpu.System {
+		assert.True(t, sample >= 0 && sample <= 100, "system utilization %v", sample)
+	}
+}
+
+func TestNewCPUCheck_samples(t *testing.T) {
+	check := &cpuCheck{
+		config:  CPUCheckConfig{CheckName: checkName, MaxProcessPercent: 50, MaxSystemPercent: 90, Samples: 2},
+		process: &cpuSampler{},
+		system:  &cpuSampler{},
+	}
+	process, system := cpuTimes{total: 100}, cpuTimes{total: 100}
+	check.process.read = func() (cpuTimes, error) { return process, nil }
+	check.system.read = func() (cpuTimes, error) { return system, nil }
+	require.NoError(t, check.init())
+
+	advance := func(processBusy, systemBusy float64) {
+		process = cpuTimes{busy: process.busy + processBusy, total: process.total + 100}
+		system = cpuTimes{busy: system.busy + systemBusy, total: system.total + 100}
+	}
+
+	advance(10, 20)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, CPUDetails{Process: []float64{10}, System: []float64{20}}, details)
+
+	advance(60, 30)
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "process CPU utilization of 60.0% exceeds the maximum of 50.0%")
+	assert.Equal(t, CPUDetails{Process: []float64{10, 60}, System: []float64{20, 30}}, details)
+
+	advance(40, 95)
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "system CPU utilization of 95.0% exceeds the maximum of 90.0%")
+	assert.Equal(t, CPUDetails{Process: []float64{60, 40}, System: []float64{30, 95}}, details, "only the recent samples are kept")
+}
+
+func TestNewCPUCheck_processManyCPUs(t *testing.T) {
+	now := time.Now()
+	var busy time.Duration
+	reader := &processCPUReader{
+		start:  now,
+		now:    func() time.Time { return now },
+		numCPU: 64,
+		busy:   func() (time.Duration, error) { return busy, nil },
+	}
+	check := &cpuCheck{
+		config:  CPUCheckConfig{CheckName: checkName, MaxProcessPercent: 40, Samples: 5},
+		process: &cpuSampler{read: reader.read},
+	}
+	require.NoError(t, check.init())
+
+	// 32 of the 64 CPUs are busy during 1 second
+	now, busy = now.Add(time.Second), busy+32*time.Second
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "process CPU utilization of 50.0% exceeds the maximum of 40.0%")
+	assert.Equal(t, CPUDetails{Process: []float64{50}}, details)
+}
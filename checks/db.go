@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// DBCheckOption configures a database check
+type DBCheckOption func(c *dbCheck)
+
+// WithValidationQuery sets a query to be executed by the database check after a successful ping.
+// The query must return at least one row, whose first column is reported as the check details.
+// When expected is not empty, the check fails unless the first column equals to it (as a string), e.g.
+//
+//	checks.WithValidationQuery("SELECT 1", "1")
+func WithValidationQuery(query string, expected string) DBCheckOption {
+	return func(c *dbCheck) {
+		c.query = query
+		c.expected = expected
+	}
+}
+
+type dbCheck struct {
+	name     string
+	db       *sql.DB
+	query    string
+	expected string
+}
+
+// NewDBCheck returns a Check that pings the given database, and optionally executes a validation query (see WithValidationQuery).
+// Both honor the deadline of the check context, so use the ExecutionTimeout option to bound the check execution.
+func NewDBCheck(name string, db *sql.DB, opts ...DBCheckOption) (gosundheit.Check, error) {
+	if db == nil {
+		return nil, errors.New("DB must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &dbCheck{name: name, db: db}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *dbCheck) Name() string {
+	return c.name
+}
+
+func (c *dbCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	if err := c.db.PingContext(ctx); err != nil {
+		return nil, errors.Wrap(err, "ping failed")
+	}
+	if c.query == "" {
+		return nil, nil
+	}
+
+	var value sql.NullString
+	if err := c.db.QueryRowContext(ctx, c.query).Scan(&value); err != nil {
+		return nil, errors.Wrap(err, "validation query failed")
+	}
+	if c.expected != "" && value.String != c.expected {
+		return value.String, errors.Errorf("validation query returned '%s', expected: '%s'", value.String, c.expected)
+	}
+
+	return value.String, nil
+}
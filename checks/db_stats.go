@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// DBStatser provides the connection pool statistics of a database. *sql.DB implements this interface.
+type DBStatser interface {
+	Stats() sql.DBStats
+}
+
+// DBStatsThresholds are the connection pool saturation thresholds of a DB stats check.
+// Zero values mean no threshold.
+type DBStatsThresholds struct {
+	// MaxOpenConnections is the maximum number of established connections, both in use and idle.
+	MaxOpenConnections int
+	// MaxWaitCount is the maximum number of connections waited for since the previous check execution.
+	MaxWaitCount int64
+	// MaxWaitDuration is the maximum total time blocked waiting for a connection since the previous check execution.
+	MaxWaitDuration time.Duration
+}
+
+type dbStatsCheck struct {
+	name       string
+	db         DBStatser
+	thresholds DBStatsThresholds
+
+	lock sync.Mutex
+	// prevStats are the stats of the previous execution, used for computing the cumulative stats deltas.
+	prevStats sql.DBStats
+}
+
+// NewDBStatsCheck returns a Check that inspects the connection pool statistics of the given database,
+// and fails when any of the given thresholds is exceeded. The full sql.DBStats are reported as the check details.
+// Since the wait count and duration statistics are cumulative, their thresholds apply to their increase since the previous execution.
+func NewDBStatsCheck(name string, db DBStatser, thresholds DBStatsThresholds) (gosundheit.Check, error) {
+	if db == nil {
+		return nil, errors.New("DB must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &dbStatsCheck{
+		name:       name,
+		db:         db,
+		thresholds: thresholds,
+	}, nil
+}
+
+func (c *dbStatsCheck) Name() string {
+	return c.name
+}
+
+func (c *dbStatsCheck) Execute(_ context.Context) (details interface{}, err error) {
+	stats := c.db.Stats()
+
+	c.lock.Lock()
+	waitCount := stats.WaitCount - c.prevStats.WaitCount
+	waitDuration := stats.WaitDuration - c.prevStats.WaitDuration
+	c.prevStats = stats
+	c.lock.Unlock()
+
+	max := c.thresholds
+	switch {
+	case max.MaxOpenConnections > 0 && stats.OpenConnections > max.MaxOpenConnections:
+		err = errors.Errorf("%d open connections exceed the maximum of %d", stats.OpenConnections, max.MaxOpenConnections)
+	case max.MaxWaitCount > 0 && waitCount > max.MaxWaitCount:
+		err = errors.Errorf("waited for %d connections, exceeding the maximum of %d", waitCount, max.MaxWaitCount)
+	case max.MaxWaitDuration > 0 && waitDuration > max.MaxWaitDuration:
+		err = errors.Errorf("waited %v for connections, exceeding the maximum of %v", waitDuration, max.MaxWaitDuration)
+	}
+
+	return stats, err
+}
@@ -0,0 +1,66 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statsFunc func() sql.DBStats
+
+func (f statsFunc) Stats() sql.DBStats {
+	return f()
+}
+
+func TestNewDBStatsCheck_nilDB(t *testing.T) {
+	check, err := NewDBStatsCheck(checkName, nil, DBStatsThresholds{})
+	assert.EqualError(t, err, "DB must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewDBStatsCheck(t *testing.T) {
+	stats := sql.DBStats{OpenConnections: 5, WaitCount: 10, WaitDuration: time.Second}
+	check, err := NewDBStatsCheck(checkName, statsFunc(func() sql.DBStats { return stats }), DBStatsThresholds{
+		MaxOpenConnections: 5,
+		MaxWaitCount:       10,
+		MaxWaitDuration:    time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err, "within thresholds")
+	assert.Equal(t, stats, details, "stats are reported as details")
+
+	stats.WaitCount = 21
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "waited for 11 connections, exceeding the maximum of 10")
+
+	stats.WaitCount = 30
+	stats.WaitDuration = 3 * time.Second
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "waited 2s for connections, exceeding the maximum of 1s")
+
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err, "no waits since the previous execution")
+
+	stats.OpenConnections = 6
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "6 open connections exceed the maximum of 5")
+}
+
+func TestNewDBStatsCheck_sqlDB(t *testing.T) {
+	db := openFakeDB(&fakeDB{})
+	defer db.Close()
+
+	check, err := NewDBStatsCheck(checkName, db, DBStatsThresholds{MaxOpenConnections: 1})
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.IsType(t, sql.DBStats{}, details)
+}
@@ -0,0 +1,182 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDBCheck_nilDB(t *testing.T) {
+	check, err := NewDBCheck(checkName, nil)
+	assert.EqualError(t, err, "DB must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewDBCheck(t *testing.T) {
+	db := openFakeDB(&fakeDB{})
+	defer db.Close()
+
+	check, err := NewDBCheck(checkName, db)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+}
+
+func TestNewDBCheck_pingFailure(t *testing.T) {
+	db := openFakeDB(&fakeDB{pingErr: errors.New("connection refused")})
+	defer db.Close()
+
+	check, err := NewDBCheck(checkName, db)
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "ping failed: connection refused")
+}
+
+func TestNewDBCheck_validationQuery(t *testing.T) {
+	db := openFakeDB(&fakeDB{rows: map[string][][]driver.Value{"SELECT 1": {{int64(1)}}}})
+	defer db.Close()
+
+	check, err := NewDBCheck(checkName, db, WithValidationQuery("SELECT 1", "1"))
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", details)
+
+	check, err = NewDBCheck(checkName, db, WithValidationQuery("SELECT 1", "2"))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "validation query returned '1', expected: '2'")
+
+	check, err = NewDBCheck(checkName, db, WithValidationQuery("SELECT 2", ""))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "validation query failed: sql: no rows in result set")
+}
+
+func TestNewDBCheck_cancelledContext(t *testing.T) {
+	db := openFakeDB(&fakeDB{})
+	defer db.Close()
+
+	check, err := NewDBCheck(checkName, db)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = check.Execute(ctx)
+	assert.Error(t, err)
+}
+
+// fakeDB is a database/sql driver connector, that serves the configured rows by query
+type fakeDB struct {
+	pingErr error
+	rows    map[string][][]driver.Value
+	// columns are the optional column names of the rows by query
+	columns map[string][]string
+	// queryErrs are the optional errors of the queries
+	queryErrs map[string]error
+}
+
+func openFakeDB(fake *fakeDB) *sql.DB {
+	return sql.OpenDB(fake)
+}
+
+func (f *fakeDB) Connect(_ context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+func (f *fakeDB) Driver() driver.Driver {
+	return f
+}
+
+func (f *fakeDB) Open(_ string) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if err := c.db.queryErrs[query]; err != nil {
+		return nil, err
+	}
+	return &fakeStmt{rows: c.db.rows[query], columns: c.db.columns[query]}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.db.pingErr
+}
+
+type fakeStmt struct {
+	rows    [][]driver.Value
+	columns []string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return driver.ResultNoRows, nil
+}
+
+func (s *fakeStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	return &fakeRows{rows: s.rows, columns: s.columns}, nil
+}
+
+type fakeRows struct {
+	rows    [][]driver.Value
+	columns []string
+}
+
+func (r *fakeRows) Columns() []string {
+	if r.columns != nil {
+		return r.columns
+	}
+	if len(r.rows) == 0 {
+		return []string{"value"}
+	}
+	columns := make([]string, len(r.rows[0]))
+	for i := range columns {
+		columns[i] = "value"
+	}
+	return columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+type diskWriteCheck struct {
+	name string
+	dir  string
+}
+
+// NewDiskWriteCheck returns a Check that writes, fsyncs, reads back and deletes a small temporary file in the given directory.
+// Unlike checking the file system stats, this detects read-only remounts, full disks and failing devices.
+func NewDiskWriteCheck(name string, dir string) (gosundheit.Check, error) {
+	if dir == "" {
+		return nil, errors.New("dir must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &diskWriteCheck{name: name, dir: dir}, nil
+}
+
+func (c *diskWriteCheck) Name() string {
+	return c.name
+}
+
+func (c *diskWriteCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	file, err := ioutil.TempFile(c.dir, ".health-check-")
+	if err != nil {
+		return nil, errors.Errorf("failed to create file: %v", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	payload := []byte(c.name + " " + strconv.FormatInt(time.Now().UnixNano(), 10))
+	_, err = file.Write(payload)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, errors.Errorf("failed to write file: %v", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	read, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return nil, errors.Errorf("failed to read file: %v", err)
+	}
+	if !bytes.Equal(read, payload) {
+		return nil, errors.New("file content read back differs from the content written")
+	}
+
+	if err := os.Remove(file.Name()); err != nil {
+		return nil, errors.Errorf("failed to delete file: %v", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,51 @@
+package checks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiskWriteCheck_config(t *testing.T) {
+	_, err := NewDiskWriteCheck(checkName, "")
+	assert.EqualError(t, err, "dir must not be empty")
+
+	_, err = NewDiskWriteCheck("", os.TempDir())
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewDiskWriteCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk-check")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	check, err := NewDiskWriteCheck(checkName, dir)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files, "the file is deleted")
+}
+
+func TestNewDiskWriteCheck_missingDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk-check")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	check, err := NewDiskWriteCheck(checkName, filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create file")
+}
@@ -0,0 +1,159 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// RecordsLookupFunc is a function that is used for looking up DNS records, and returns their values
+type RecordsLookupFunc func(ctx context.Context, lookFor string) (records []string, err error)
+
+// Count adapts the RecordsLookupFunc to a LookupFunc, that returns the resolved records count, e.g. for use with NewResolveCheck
+func (f RecordsLookupFunc) Count() LookupFunc {
+	return func(ctx context.Context, lookFor string) (resolvedCount int, err error) {
+		records, err := f(ctx, lookFor)
+		return len(records), err
+	}
+}
+
+// NewRecordsResolveCheck returns a gosundheit.Check that makes sure the `resolveThis` arg can be resolved using the `lookupFn`
+// to at least one record, and that the resolved records include all the `expected` values (compared case-insensitively,
+// ignoring trailing dots), within the timeout specified by the provided context. The resolved records are reported as the check details.
+func NewRecordsResolveCheck(name string, lookupFn RecordsLookupFunc, resolveThis string, expected ...string) gosundheit.Check {
+	return &CustomCheck{
+		CheckName: name,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			records, err := lookupFn(ctx, resolveThis)
+			if err != nil {
+				return records, err
+			}
+			if len(records) == 0 {
+				return records, errors.Errorf("[%s] lookup returned no records", resolveThis)
+			}
+
+			var missing []string
+			for _, value := range expected {
+				if !containsRecord(records, value) {
+					missing = append(missing, value)
+				}
+			}
+			if len(missing) > 0 {
+				return records, errors.Errorf("[%s] lookup is missing the expected records: %s", resolveThis, strings.Join(missing, ", "))
+			}
+			return records, nil
+		},
+	}
+}
+
+// NewMXResolveCheck returns a gosundheit.Check named "resolve.mx.<domain>", that makes sure the domain has MX records,
+// including the expected mail server hosts.
+func NewMXResolveCheck(domain string, expected ...string) gosundheit.Check {
+	return NewRecordsResolveCheck("resolve.mx."+domain, NewMXLookup(nil), domain, expected...)
+}
+
+// NewSRVResolveCheck returns a gosundheit.Check named "resolve.srv._<service>._<proto>.<domain>", that makes sure the
+// service has SRV records, including the expected "target:port" values.
+func NewSRVResolveCheck(service, proto, domain string, expected ...string) gosundheit.Check {
+	return NewRecordsResolveCheck("resolve.srv._"+service+"._"+proto+"."+domain, NewSRVLookup(nil, service, proto), domain, expected...)
+}
+
+// NewTXTResolveCheck returns a gosundheit.Check named "resolve.txt.<domain>", that makes sure the domain has TXT records,
+// including the expected values.
+func NewTXTResolveCheck(domain string, expected ...string) gosundheit.Check {
+	return NewRecordsResolveCheck("resolve.txt."+domain, NewTXTLookup(nil), domain, expected...)
+}
+
+// NewCNAMEResolveCheck returns a gosundheit.Check named "resolve.cname.<host>", that makes sure the canonical name of
+// the host is the expected one (unless empty).
+func NewCNAMEResolveCheck(host string, expected string) gosundheit.Check {
+	var expectedValues []string
+	if expected != "" {
+		expectedValues = append(expectedValues, expected)
+	}
+	return NewRecordsResolveCheck("resolve.cname."+host, NewCNAMELookup(nil), host, expectedValues...)
+}
+
+// NewNSResolveCheck returns a gosundheit.Check named "resolve.ns.<domain>", that makes sure the domain has NS records,
+// including the expected name server hosts.
+func NewNSResolveCheck(domain string, expected ...string) gosundheit.Check {
+	return NewRecordsResolveCheck("resolve.ns."+domain, NewNSLookup(nil), domain, expected...)
+}
+
+// NewMXLookup creates a RecordsLookupFunc that looks up the mail server hosts of a domain
+func NewMXLookup(resolver *net.Resolver) RecordsLookupFunc {
+	resolver = defaultResolver(resolver)
+	return func(ctx context.Context, domain string) (records []string, err error) {
+		mxs, err := resolver.LookupMX(ctx, domain)
+		for _, mx := range mxs {
+			records = append(records, strings.TrimSuffix(mx.Host, "."))
+		}
+		return
+	}
+}
+
+// NewSRVLookup creates a RecordsLookupFunc that looks up the "target:port" values of a service in a domain
+func NewSRVLookup(resolver *net.Resolver, service, proto string) RecordsLookupFunc {
+	resolver = defaultResolver(resolver)
+	return func(ctx context.Context, domain string) (records []string, err error) {
+		_, srvs, err := resolver.LookupSRV(ctx, service, proto, domain)
+		for _, srv := range srvs {
+			records = append(records, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+		}
+		return
+	}
+}
+
+// NewTXTLookup creates a RecordsLookupFunc that looks up the TXT records of a domain
+func NewTXTLookup(resolver *net.Resolver) RecordsLookupFunc {
+	resolver = defaultResolver(resolver)
+	return func(ctx context.Context, domain string) (records []string, err error) {
+		return resolver.LookupTXT(ctx, domain)
+	}
+}
+
+// NewCNAMELookup creates a RecordsLookupFunc that looks up the canonical name of a host
+func NewCNAMELookup(resolver *net.Resolver) RecordsLookupFunc {
+	resolver = defaultResolver(resolver)
+	return func(ctx context.Context, host string) (records []string, err error) {
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{strings.TrimSuffix(cname, ".")}, nil
+	}
+}
+
+// NewNSLookup creates a RecordsLookupFunc that looks up the name server hosts of a domain
+func NewNSLookup(resolver *net.Resolver) RecordsLookupFunc {
+	resolver = defaultResolver(resolver)
+	return func(ctx context.Context, domain string) (records []string, err error) {
+		nss, err := resolver.LookupNS(ctx, domain)
+		for _, ns := range nss {
+			records = append(records, strings.TrimSuffix(ns.Host, "."))
+		}
+		return
+	}
+}
+
+func defaultResolver(resolver *net.Resolver) *net.Resolver {
+	if resolver == nil {
+		return net.DefaultResolver
+	}
+	return resolver
+}
+
+func containsRecord(records []string, value string) bool {
+	value = strings.TrimSuffix(value, ".")
+	for _, record := range records {
+		if strings.EqualFold(strings.TrimSuffix(record, "."), value) {
+			return true
+		}
+	}
+	return false
+}
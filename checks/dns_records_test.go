@@ -0,0 +1,66 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockRecordsLookupFunc(records []string, err error) RecordsLookupFunc {
+	return func(ctx context.Context, lookFor string) ([]string, error) {
+		return records, err
+	}
+}
+
+func TestNewRecordsResolveCheck(t *testing.T) {
+	records := []string{"mx1.example.com", "MX2.example.com"}
+	check := NewRecordsResolveCheck("resolve.mx.example.com", createMockRecordsLookupFunc(records, nil), "example.com", "mx1.example.com", "mx2.example.com.")
+	assert.Equal(t, "resolve.mx.example.com", check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, records, details)
+
+	check = NewRecordsResolveCheck(checkName, createMockRecordsLookupFunc(records, nil), "example.com", "mx1.example.com", "mx3.example.com", "mx4.example.com")
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "[example.com] lookup is missing the expected records: mx3.example.com, mx4.example.com")
+	assert.Equal(t, records, details)
+
+	check = NewRecordsResolveCheck(checkName, createMockRecordsLookupFunc(nil, nil), "example.com")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "[example.com] lookup returned no records")
+
+	check = NewRecordsResolveCheck(checkName, createMockRecordsLookupFunc(nil, errors.New(ExpectedError)), "example.com")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, ExpectedError)
+}
+
+func TestRecordsLookupFunc_Count(t *testing.T) {
+	check := NewResolveCheck(createMockRecordsLookupFunc([]string{"a", "b"}, nil).Count(), "whatever", 3)
+
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "[whatever] lookup returned 2 results, but requires at least 3")
+	assert.Equal(t, "[2] results were resolved", details)
+}
+
+func TestNewCNAMEResolveCheck(t *testing.T) {
+	check := NewCNAMEResolveCheck("localhost", "localhost")
+	assert.Equal(t, "resolve.cname.localhost", check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost"}, details)
+
+	_, err = NewCNAMEResolveCheck("localhost", "example.com").Execute(context.Background())
+	assert.EqualError(t, err, "[localhost] lookup is missing the expected records: example.com")
+}
+
+func TestNewResolveCheck_names(t *testing.T) {
+	assert.Equal(t, "resolve.mx.example.com", NewMXResolveCheck("example.com").Name())
+	assert.Equal(t, "resolve.srv._ldap._tcp.example.com", NewSRVResolveCheck("ldap", "tcp", "example.com").Name())
+	assert.Equal(t, "resolve.txt.example.com", NewTXTResolveCheck("example.com").Name())
+	assert.Equal(t, "resolve.ns.example.com", NewNSResolveCheck("example.com").Name())
+}
@@ -0,0 +1,118 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ElasticsearchCheckConfig configures a check for the health of an Elasticsearch cluster.
+type ElasticsearchCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the required base URL of the cluster, e.g. "http://localhost:9200"
+	URL string
+	// Yellow determines the outcome of a yellow cluster status (i.e. some replica shards are unassigned), which fails by default.
+	// A green status always passes, while a red status always fails.
+	Yellow ElasticsearchYellowOutcome
+	// AllowYellow indicates when true, that a yellow cluster status passes the check, unless Yellow is set.
+	//
+	// Deprecated: use Yellow with ElasticsearchYellowPasses instead.
+	AllowYellow bool
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add authentication headers, etc.
+	Options []RequestOption
+}
+
+// ElasticsearchYellowOutcome is the outcome of an Elasticsearch check for a yellow cluster status
+type ElasticsearchYellowOutcome int
+
+const (
+	// ElasticsearchYellowFails fails the check on a yellow status, which is the default.
+	ElasticsearchYellowFails ElasticsearchYellowOutcome = iota
+	// ElasticsearchYellowPasses passes the check on a yellow status, e.g. in single node clusters.
+	ElasticsearchYellowPasses
+	// ElasticsearchYellowDegrades fails the check on a yellow status, but only degrades the system (see gosundheit.Degraded).
+	ElasticsearchYellowDegrades
+)
+
+// ElasticsearchClusterHealth is the cluster health, as returned by the Elasticsearch _cluster/health API
+type ElasticsearchClusterHealth struct {
+	ClusterName         string `json:"cluster_name"`
+	Status              string `json:"status"`
+	NumberOfNodes       int    `json:"number_of_nodes"`
+	NumberOfDataNodes   int    `json:"number_of_data_nodes"`
+	ActivePrimaryShards int    `json:"active_primary_shards"`
+	ActiveShards        int    `json:"active_shards"`
+	RelocatingShards    int    `json:"relocating_shards"`
+	InitializingShards  int    `json:"initializing_shards"`
+	UnassignedShards    int    `json:"unassigned_shards"`
+}
+
+type elasticsearchCheck struct {
+	config    ElasticsearchCheckConfig
+	healthURL string
+}
+
+// NewElasticsearchCheck returns a Check that queries the _cluster/health API of an Elasticsearch cluster,
+// and fails unless the cluster status is green, where a yellow status may also pass or only degrade the system
+// (see ElasticsearchCheckConfig.Yellow).
+// The cluster health, including the shard counts, is reported as the check details.
+func NewElasticsearchCheck(config ElasticsearchCheckConfig) (gosundheit.Check, error) {
+	if config.URL == "" {
+		return nil, errors.New("URL must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+	if config.AllowYellow && config.Yellow == ElasticsearchYellowFails {
+		config.Yellow = ElasticsearchYellowPasses
+	}
+
+	return &elasticsearchCheck{
+		config:    config,
+		healthURL: strings.TrimSuffix(config.URL, "/") + "/_cluster/health",
+	}, nil
+}
+
+func (c *elasticsearchCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *elasticsearchCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var health ElasticsearchClusterHealth
+	// the health API responds with 408 along with the cluster health, when it is called with wait conditions
+	// (e.g. a wait_for_status parameter set using Options) which are not met within its timeout
+	if err := fetchJSON(ctx, c.config.Client, c.healthURL, c.config.Options, &health, http.StatusOK, http.StatusRequestTimeout); err != nil {
+		return nil, err
+	}
+
+	err = errors.Errorf("cluster '%s' status is %s", health.ClusterName, health.Status)
+	switch health.Status {
+	case "green":
+		return health, nil
+	case "yellow":
+		switch c.config.Yellow {
+		case ElasticsearchYellowPasses:
+			return health, nil
+		case ElasticsearchYellowDegrades:
+			return health, gosundheit.Degraded(err)
+		}
+	}
+	return health, err
+}
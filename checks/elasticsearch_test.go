@@ -0,0 +1,121 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+func elasticsearchServer(status *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_cluster/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"cluster_name":"logs","status":"%s","number_of_nodes":3,"active_shards":10,"unassigned_shards":2}`, *status)
+	}))
+}
+
+func TestNewElasticsearchCheck_requiredFields(t *testing.T) {
+	_, err := NewElasticsearchCheck(ElasticsearchCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "URL must not be empty")
+
+	_, err = NewElasticsearchCheck(ElasticsearchCheckConfig{URL: "http://localhost:9200"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewElasticsearchCheck(t *testing.T) {
+	status := "green"
+	server := elasticsearchServer(&status)
+	defer server.Close()
+
+	check, err := NewElasticsearchCheck(ElasticsearchCheckConfig{CheckName: checkName, URL: server.URL + "/"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ElasticsearchClusterHealth{
+		ClusterName:      "logs",
+		Status:           "green",
+		NumberOfNodes:    3,
+		ActiveShards:     10,
+		UnassignedShards: 2,
+	}, details)
+
+	status = "yellow"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster 'logs' status is yellow")
+
+	status = "red"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster 'logs' status is red")
+}
+
+func TestNewElasticsearchCheck_yellow(t *testing.T) {
+	status := "yellow"
+	server := elasticsearchServer(&status)
+	defer server.Close()
+
+	check, err := NewElasticsearchCheck(ElasticsearchCheckConfig{CheckName: checkName, URL: server.URL, Yellow: ElasticsearchYellowPasses})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	check, err = NewElasticsearchCheck(ElasticsearchCheckConfig{CheckName: checkName, URL: server.URL, Yellow: ElasticsearchYellowDegrades})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster 'logs' status is yellow")
+	assert.True(t, gosundheit.IsDegraded(err), "yellow degrades")
+
+	status = "red"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster 'logs' status is red")
+	assert.False(t, gosundheit.IsDegraded(err), "red fails")
+}
+
+func TestNewElasticsearchCheck_allowYellow(t *testing.T) {
+	status := "yellow"
+	server := elasticsearchServer(&status)
+	defer server.Close()
+
+	check, err := NewElasticsearchCheck(ElasticsearchCheckConfig{CheckName: checkName, URL: server.URL, AllowYellow: true})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err, "the deprecated AllowYellow passes on yellow")
+
+	status = "red"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster 'logs' status is red")
+}
+
+func TestNewElasticsearchCheck_waitTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("wait_for_status") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusRequestTimeout)
+		_, _ = fmt.Fprint(w, `{"cluster_name":"logs","status":"red","timed_out":true}`)
+	}))
+	defer server.Close()
+
+	check, err := NewElasticsearchCheck(ElasticsearchCheckConfig{
+		CheckName: checkName,
+		URL:       server.URL,
+		Options: []RequestOption{func(r *http.Request) {
+			r.URL.RawQuery = "wait_for_status=green&timeout=1s"
+		}},
+	})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster 'logs' status is red", "the cluster health is read from a timed out wait")
+	assert.Equal(t, "red", details.(ElasticsearchClusterHealth).Status)
+}
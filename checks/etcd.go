@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// EtcdClient is the minimal etcd client API required by the etcd check, which corresponds to
+// the Endpoints(), Status() and AlarmList() methods of the etcd clientv3 client.
+type EtcdClient interface {
+	// Endpoints returns the endpoints of the cluster members
+	Endpoints() []string
+	// Status returns the status of the member at the given endpoint
+	Status(ctx context.Context, endpoint string) (*EtcdStatus, error)
+	// AlarmList returns the raised alarms of the cluster
+	AlarmList(ctx context.Context) ([]EtcdAlarm, error)
+}
+
+// EtcdStatus is the status of an etcd member
+type EtcdStatus struct {
+	// MemberID is the ID of the member
+	MemberID uint64 `json:"memberId"`
+	// Leader is the ID of the leader as seen by the member, or zero when there's no leader
+	Leader uint64 `json:"leader"`
+	// RaftTerm is the current raft term of the member
+	RaftTerm uint64 `json:"raftTerm"`
+	// Version is the etcd version of the member
+	Version string `json:"version,omitempty"`
+}
+
+// EtcdAlarm is an alarm raised by an etcd member
+type EtcdAlarm struct {
+	// MemberID is the ID of the member that raised the alarm
+	MemberID uint64 `json:"memberId"`
+	// Alarm is the alarm type, e.g. "NOSPACE" or "CORRUPT"
+	Alarm string `json:"alarm"`
+}
+
+// EtcdDetails are the details of an etcd check
+type EtcdDetails struct {
+	// Members are the statuses of the reachable members, by endpoint
+	Members map[string]EtcdStatus `json:"members"`
+	// Alarms are the raised alarms, if any
+	Alarms []EtcdAlarm `json:"alarms,omitempty"`
+}
+
+type etcdCheck struct {
+	name   string
+	client EtcdClient
+}
+
+// NewEtcdCheck returns a Check that fetches the status of each of the etcd cluster members, and the cluster alarms.
+// The check fails when a member is unreachable or has no leader, or when any alarm is raised.
+// The members statuses (including the leader and raft term) and alarms are reported as the check details.
+func NewEtcdCheck(name string, client EtcdClient) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &etcdCheck{name: name, client: client}, nil
+}
+
+func (c *etcdCheck) Name() string {
+	return c.name
+}
+
+func (c *etcdCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	endpoints := c.client.Endpoints()
+	etcdDetails := EtcdDetails{Members: make(map[string]EtcdStatus, len(endpoints))}
+
+	var failures []string
+	for _, endpoint := range endpoints {
+		status, err := c.client.Status(ctx, endpoint)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("member '%s' is unreachable: %v", endpoint, err))
+			continue
+		}
+		etcdDetails.Members[endpoint] = *status
+		if status.Leader == 0 {
+			failures = append(failures, fmt.Sprintf("member '%s' has no leader", endpoint))
+		}
+	}
+
+	alarms, err := c.client.AlarmList(ctx)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("failed to list alarms: %v", err))
+	}
+	etcdDetails.Alarms = alarms
+	for _, alarm := range alarms {
+		failures = append(failures, fmt.Sprintf("alarm %s is raised by member %x", alarm.Alarm, alarm.MemberID))
+	}
+
+	if len(endpoints) == 0 {
+		failures = append(failures, "no endpoints are configured")
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return etcdDetails, errors.New(strings.Join(failures, "; "))
+	}
+	return etcdDetails, nil
+}
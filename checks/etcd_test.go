@@ -0,0 +1,66 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEtcd struct {
+	statuses map[string]*EtcdStatus
+	alarms   []EtcdAlarm
+}
+
+func (e *fakeEtcd) Endpoints() []string {
+	return []string{"10.0.0.1:2379", "10.0.0.2:2379"}
+}
+
+func (e *fakeEtcd) Status(_ context.Context, endpoint string) (*EtcdStatus, error) {
+	status, ok := e.statuses[endpoint]
+	if !ok {
+		return nil, errors.New("context deadline exceeded")
+	}
+	return status, nil
+}
+
+func (e *fakeEtcd) AlarmList(_ context.Context) ([]EtcdAlarm, error) {
+	return e.alarms, nil
+}
+
+func TestNewEtcdCheck_nilClient(t *testing.T) {
+	check, err := NewEtcdCheck(checkName, nil)
+	assert.EqualError(t, err, "client must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewEtcdCheck(t *testing.T) {
+	etcd := &fakeEtcd{statuses: map[string]*EtcdStatus{
+		"10.0.0.1:2379": {MemberID: 1, Leader: 1, RaftTerm: 7},
+		"10.0.0.2:2379": {MemberID: 2, Leader: 1, RaftTerm: 7},
+	}}
+	check, err := NewEtcdCheck(checkName, etcd)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, EtcdDetails{Members: map[string]EtcdStatus{
+		"10.0.0.1:2379": {MemberID: 1, Leader: 1, RaftTerm: 7},
+		"10.0.0.2:2379": {MemberID: 2, Leader: 1, RaftTerm: 7},
+	}}, details)
+
+	etcd.alarms = []EtcdAlarm{{MemberID: 0xa, Alarm: "NOSPACE"}}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "alarm NOSPACE is raised by member a")
+
+	etcd.alarms = nil
+	etcd.statuses["10.0.0.1:2379"] = &EtcdStatus{MemberID: 1}
+	delete(etcd.statuses, "10.0.0.2:2379")
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err,
+		"member '10.0.0.1:2379' has no leader; member '10.0.0.2:2379' is unreachable: context deadline exceeded")
+	assert.Len(t, details.(EtcdDetails).Members, 1, "reachable members")
+}
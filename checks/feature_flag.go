@@ -0,0 +1,103 @@
+package checks
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// FeatureFlagProviderReady is the status of a feature flag provider which is ready to evaluate flags, as defined by OpenFeature
+const FeatureFlagProviderReady = "READY"
+
+// featureFlagReasonError is the evaluation reason of a flag which failed to evaluate, as defined by OpenFeature
+const featureFlagReasonError = "ERROR"
+
+// FeatureFlagEvaluation is the result of a flag evaluation
+type FeatureFlagEvaluation struct {
+	Value   interface{} `json:"value"`
+	Variant string      `json:"variant,omitempty"`
+	// Reason is the evaluation reason, e.g. "STATIC", "TARGETING_MATCH", "DEFAULT", "CACHED" or "ERROR"
+	Reason string `json:"reason,omitempty"`
+}
+
+// FeatureFlagClient is the minimal feature flag API required by the feature flag check, so that any provider can be used,
+// e.g. by wrapping an OpenFeature client, whose state is the provider status, and whose BooleanValueDetails (or any other type)
+// evaluation details hold the value, variant and reason of the evaluation.
+type FeatureFlagClient interface {
+	// ProviderStatus returns the status of the provider, e.g. "READY", "NOT_READY", "STALE" or "ERROR"
+	ProviderStatus() string
+	// EvaluateFlag evaluates the given flag
+	EvaluateFlag(ctx context.Context, flag string) (FeatureFlagEvaluation, error)
+}
+
+// FeatureFlagCheckOption configures a feature flag check
+type FeatureFlagCheckOption func(c *featureFlagCheck)
+
+// WithExpectedFlagValue sets the feature flag check to fail unless the canary flag evaluates to the given value,
+// which should differ from the default value of the evaluation, for detecting providers which silently fall back to defaults.
+func WithExpectedFlagValue(value interface{}) FeatureFlagCheckOption {
+	return func(c *featureFlagCheck) {
+		c.expectedValue = value
+	}
+}
+
+// FeatureFlagDetails are the details of a feature flag check
+type FeatureFlagDetails struct {
+	ProviderStatus string `json:"providerStatus"`
+	Flag           string `json:"flag"`
+	FeatureFlagEvaluation
+}
+
+type featureFlagCheck struct {
+	name          string
+	client        FeatureFlagClient
+	flag          string
+	expectedValue interface{}
+}
+
+// NewFeatureFlagCheck returns a Check that evaluates a canary flag using the given feature flag client (e.g. an OpenFeature client),
+// and fails when the provider is not ready, or when the evaluation fails, since stale flag providers silently change behavior.
+// Optionally, the check also verifies the evaluated value (see WithExpectedFlagValue). The evaluation is reported as the check details.
+func NewFeatureFlagCheck(name string, client FeatureFlagClient, flag string, opts ...FeatureFlagCheckOption) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if flag == "" {
+		return nil, errors.New("flag must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &featureFlagCheck{name: name, client: client, flag: flag}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *featureFlagCheck) Name() string {
+	return c.name
+}
+
+func (c *featureFlagCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	flagDetails := FeatureFlagDetails{ProviderStatus: c.client.ProviderStatus(), Flag: c.flag}
+	if flagDetails.ProviderStatus != FeatureFlagProviderReady {
+		return flagDetails, errors.Errorf("provider is %s", flagDetails.ProviderStatus)
+	}
+
+	flagDetails.FeatureFlagEvaluation, err = c.client.EvaluateFlag(ctx, c.flag)
+	if err != nil {
+		return flagDetails, errors.Errorf("failed to evaluate flag '%s': %v", c.flag, err)
+	}
+	if flagDetails.Reason == featureFlagReasonError {
+		return flagDetails, errors.Errorf("failed to evaluate flag '%s'", c.flag)
+	}
+	if c.expectedValue != nil && !reflect.DeepEqual(flagDetails.Value, c.expectedValue) {
+		return flagDetails, errors.Errorf("flag '%s' evaluated to '%v', expected: '%v'", c.flag, flagDetails.Value, c.expectedValue)
+	}
+	return flagDetails, nil
+}
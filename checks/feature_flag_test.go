@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeatureFlagClient struct {
+	status     string
+	evaluation FeatureFlagEvaluation
+	err        error
+}
+
+func (c *fakeFeatureFlagClient) ProviderStatus() string {
+	return c.status
+}
+
+func (c *fakeFeatureFlagClient) EvaluateFlag(_ context.Context, flag string) (FeatureFlagEvaluation, error) {
+	return c.evaluation, c.err
+}
+
+func TestNewFeatureFlagCheck_config(t *testing.T) {
+	_, err := NewFeatureFlagCheck(checkName, nil, "canary")
+	assert.EqualError(t, err, "client must not be nil")
+
+	_, err = NewFeatureFlagCheck(checkName, &fakeFeatureFlagClient{}, "")
+	assert.EqualError(t, err, "flag must not be empty")
+
+	_, err = NewFeatureFlagCheck("", &fakeFeatureFlagClient{}, "canary")
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewFeatureFlagCheck(t *testing.T) {
+	client := &fakeFeatureFlagClient{
+		status:     FeatureFlagProviderReady,
+		evaluation: FeatureFlagEvaluation{Value: true, Variant: "on", Reason: "STATIC"},
+	}
+	check, err := NewFeatureFlagCheck(checkName, client, "canary", WithExpectedFlagValue(true))
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, FeatureFlagDetails{
+		ProviderStatus:        FeatureFlagProviderReady,
+		Flag:                  "canary",
+		FeatureFlagEvaluation: FeatureFlagEvaluation{Value: true, Variant: "on", Reason: "STATIC"},
+	}, details)
+
+	client.evaluation = FeatureFlagEvaluation{Value: false, Reason: "DEFAULT"}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "flag 'canary' evaluated to 'false', expected: 'true'")
+
+	client.evaluation = FeatureFlagEvaluation{Value: false, Reason: "ERROR"}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to evaluate flag 'canary'")
+
+	client.err = errors.New("FLAG_NOT_FOUND: flag not found")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to evaluate flag 'canary': FLAG_NOT_FOUND: flag not found")
+
+	client.status = "STALE"
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "provider is STALE")
+	assert.Equal(t, FeatureFlagDetails{ProviderStatus: "STALE", Flag: "canary"}, details)
+}
@@ -0,0 +1,252 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// FederationCheckConfig configures a check for the health of another service, as reported by its go-sundheit JSON endpoint.
+type FederationCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the required URL of the go-sundheit JSON endpoint of the other service (see the http package HandleHealthJSON),
+	// which must report the results in the long format, either keyed by check name or ordered.
+	URL string
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+// FederationCheckOption configures a federation check
+type FederationCheckOption func(c *federationCheck)
+
+// WithFederatedResults sets the federation check to also re-expose the results of the other service in the given Health instance,
+// as async checks namespaced by the check name, e.g. "orders.health.db" for the "db" check of the other service,
+// which are registered using the given options. Results which are no longer reported by the other service are deregistered,
+// and all the results fail when the other service cannot be reached.
+// The re-exposed results are valid for the given TTL (see gosundheit.ResultTTL), which should be a few multiples of the execution
+// period of the federation check, so once the federation check is deregistered or stalls, they fail with gosundheit.ErrResultExpired
+// rather than reporting their last results forever.
+func WithFederatedResults(h gosundheit.Health, ttl time.Duration, opts ...gosundheit.CheckOption) FederationCheckOption {
+	return func(c *federationCheck) {
+		c.health = h
+		c.healthOpts = append(append([]gosundheit.CheckOption{}, opts...), gosundheit.ResultTTL(ttl))
+		c.resultTTL = ttl
+	}
+}
+
+// FederatedResult is the result of a check of another service
+type FederatedResult struct {
+	Details            interface{} `json:"message,omitempty"`
+	Error              string      `json:"error,omitempty"`
+	Timestamp          time.Time   `json:"timestamp"`
+	ContiguousFailures int64       `json:"contiguousFailures"`
+}
+
+// IsHealthy returns true iff the check of the other service was a success
+func (r FederatedResult) IsHealthy() bool {
+	return r.Error == ""
+}
+
+// UnmarshalJSON implements json.Unmarshaler, flattening the marshaled error of the result into its message
+func (r *FederatedResult) UnmarshalJSON(data []byte) error {
+	var result struct {
+		Details            interface{}     `json:"message"`
+		Error              json.RawMessage `json:"error"`
+		Timestamp          time.Time       `json:"timestamp"`
+		ContiguousFailures int64           `json:"contiguousFailures"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*r = FederatedResult{Details: result.Details, Timestamp: result.Timestamp, ContiguousFailures: result.ContiguousFailures}
+
+	var marshaledError struct {
+		Message string `json:"message"`
+	}
+	switch {
+	case len(result.Error) == 0 || string(result.Error) == "null":
+	case json.Unmarshal(result.Error, &marshaledError) == nil && marshaledError.Message != "":
+		r.Error = marshaledError.Message
+	case json.Unmarshal(result.Error, &r.Error) == nil && r.Error != "":
+	default:
+		r.Error = string(result.Error)
+	}
+	return nil
+}
+
+type federationCheck struct {
+	config     FederationCheckConfig
+	health     gosundheit.Health
+	healthOpts []gosundheit.CheckOption
+	resultTTL  time.Duration
+
+	lock sync.Mutex
+	// federated are the names of the checks registered in health for re-exposing the results of the other service
+	federated map[string]bool
+}
+
+// NewFederationCheck returns a Check that calls the go-sundheit JSON endpoint of another service, and collapses its results into
+// a single result, which fails when the other service is unhealthy. The results of the other service are reported as the check details,
+// and can also be re-exposed individually (see WithFederatedResults), enabling health aggregation across a service mesh.
+func NewFederationCheck(config FederationCheckConfig, opts ...FederationCheckOption) (gosundheit.Check, error) {
+	if config.URL == "" {
+		return nil, errors.New("URL must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	check := &federationCheck{config: config, federated: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(check)
+	}
+	if check.health != nil && check.resultTTL <= 0 {
+		return nil, errors.New("federated results TTL must be positive")
+	}
+	return check, nil
+}
+
+func (c *federationCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *federationCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	results, healthy, err := c.fetchResults(ctx)
+	if c.health != nil {
+		c.federate(results, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !healthy {
+		var failing []string
+		for name, result := range results {
+			if !result.IsHealthy() {
+				failing = append(failing, name)
+			}
+		}
+		sort.Strings(failing)
+		if len(failing) == 0 {
+			return results, errors.New("the service is unhealthy")
+		}
+		err = errors.Errorf("failing checks: [%s]", strings.Join(failing, ", "))
+	}
+	return results, err
+}
+
+// fetchResults returns the results reported by the other service, and whether it is healthy according to the response status
+func (c *federationCheck) fetchResults(ctx context.Context) (map[string]FederatedResult, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL, nil)
+	if err != nil {
+		return nil, false, errors.Errorf("unable to create check HTTP request: %v", err)
+	}
+	configureHTTPOptions(req, c.config.Options)
+
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return nil, false, errors.Errorf("fail to execute 'GET' request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, false, errors.Errorf("unexpected status code: '%v' expected: '%v'",
+			resp.StatusCode, []int{http.StatusOK, http.StatusServiceUnavailable})
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, errors.Errorf("failed to read response body: %v", err)
+	}
+
+	results, err := decodeFederatedResults(body)
+	if err != nil {
+		return nil, false, errors.Errorf("failed to decode response body: %v", err)
+	}
+	return results, resp.StatusCode == http.StatusOK, nil
+}
+
+// decodeFederatedResults decodes results keyed by check name, or ordered results which hold their check name
+func decodeFederatedResults(body []byte) (map[string]FederatedResult, error) {
+	results := make(map[string]FederatedResult)
+	if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+		return results, json.Unmarshal(body, &results)
+	}
+
+	var ordered []json.RawMessage
+	if err := json.Unmarshal(body, &ordered); err != nil {
+		return nil, err
+	}
+	for _, raw := range ordered {
+		var named struct {
+			Name string `json:"name"`
+		}
+		var result FederatedResult
+		if err := json.Unmarshal(raw, &named); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, err
+		}
+		results[named.Name] = result
+	}
+	return results, nil
+}
+
+// federate re-exposes the given results in the Health instance, or fails all the re-exposed results with the given error
+func (c *federationCheck) federate(results map[string]FederatedResult, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err != nil {
+		for name := range c.federated {
+			_ = c.health.ReportResult(name, nil, errors.Errorf("failed to fetch the results: %v", err))
+		}
+		return
+	}
+
+	reported := make(map[string]bool, len(results))
+	for remoteName, result := range results {
+		name := c.config.CheckName + "." + remoteName
+		reported[name] = true
+		if !c.federated[name] {
+			if err := c.health.RegisterAsyncCheck(name, c.healthOpts...); err != nil {
+				continue
+			}
+			c.federated[name] = true
+		}
+
+		var resultErr error
+		if !result.IsHealthy() {
+			resultErr = errors.New(result.Error)
+		}
+		_ = c.health.ReportResult(name, result.Details, resultErr)
+	}
+
+	for name := range c.federated {
+		if !reported[name] {
+			c.health.Deregister(name)
+			delete(c.federated, name)
+		}
+	}
+}
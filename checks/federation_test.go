@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+	healthhttp "github.com/AppsFlyer/go-sundheit/http"
+)
+
+func TestNewFederationCheck_config(t *testing.T) {
+	_, err := NewFederationCheck(FederationCheckConfig{CheckName: "orders.health"})
+	assert.EqualError(t, err, "URL must not be empty")
+
+	_, err = NewFederationCheck(FederationCheckConfig{URL: "http://orders/health"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewFederationCheck(FederationCheckConfig{CheckName: "orders.health", URL: "http://orders/health"},
+		WithFederatedResults(gosundheit.New(), 0))
+	assert.EqualError(t, err, "federated results TTL must be positive")
+
+	check, err := NewFederationCheck(FederationCheckConfig{CheckName: "orders.health", URL: "http://orders/health"})
+	require.NoError(t, err)
+	assert.Equal(t, "orders.health", check.Name(), "check name")
+}
+
+func TestNewFederationCheck(t *testing.T) {
+	remote := gosundheit.New()
+	defer remote.DeregisterAll()
+	require.NoError(t, remote.RegisterAsyncCheck("db"))
+	require.NoError(t, remote.RegisterAsyncCheck("cache"))
+	require.NoError(t, remote.ReportResult("db", "connected", nil))
+	require.NoError(t, remote.ReportResult("cache", nil, nil))
+
+	for _, opts := range [][]healthhttp.HandlerOption{nil, {healthhttp.WithOrderedResults()}} {
+		server := httptest.NewServer(healthhttp.HandleHealthJSON(remote, opts...))
+		check, err := NewFederationCheck(FederationCheckConfig{CheckName: "orders.health", URL: server.URL})
+		require.NoError(t, err)
+
+		require.NoError(t, remote.ReportResult("cache", nil, nil))
+		details, err := check.Execute(context.Background())
+		assert.NoError(t, err)
+		results := details.(map[string]FederatedResult)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "connected", results["db"].Details)
+		assert.True(t, results["cache"].IsHealthy())
+
+		require.NoError(t, remote.ReportResult("cache", nil, errors.New("evicting")))
+		details, err = check.Execute(context.Background())
+		assert.EqualError(t, err, "failing checks: [cache]")
+		results = details.(map[string]FederatedResult)
+		assert.Equal(t, "evicting", results["cache"].Error)
+		assert.Equal(t, int64(1), results["cache"].ContiguousFailures)
+		assert.True(t, results["db"].IsHealthy())
+
+		server.Close()
+	}
+}
+
+func TestNewFederationCheck_federatedResults(t *testing.T) {
+	remote := gosundheit.New()
+	defer remote.DeregisterAll()
+	require.NoError(t, remote.RegisterAsyncCheck("db"))
+	require.NoError(t, remote.ReportResult("db", "connected", nil))
+	require.NoError(t, remote.RegisterAsyncCheck("cache"))
+	require.NoError(t, remote.ReportResult("cache", nil, errors.New("evicting")))
+	server := httptest.NewServer(healthhttp.HandleHealthJSON(remote))
+	defer server.Close()
+
+	local := gosundheit.New()
+	defer local.DeregisterAll()
+	check, err := NewFederationCheck(FederationCheckConfig{CheckName: "orders", URL: server.URL},
+		WithFederatedResults(local, time.Minute, gosundheit.Severity(gosundheit.SeverityWarning)))
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failing checks: [cache]")
+	results, healthy := local.Results()
+	assert.True(t, healthy, "the federated results are registered as warnings")
+	assert.Len(t, results, 2)
+	assert.Equal(t, "connected", results["orders.db"].Details)
+	require.Error(t, results["orders.cache"].Error)
+	assert.Equal(t, "evicting", results["orders.cache"].Error.Error())
+
+	remote.Deregister("cache")
+	assert.Eventually(t, func() bool {
+		_, err = check.Execute(context.Background())
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		results, _ = local.Results()
+		return len(results) == 1
+	}, time.Second, 10*time.Millisecond, "the removed result is deregistered")
+
+	server.Close()
+	_, err = check.Execute(context.Background())
+	assert.Error(t, err)
+	results, _ = local.Results()
+	require.Error(t, results["orders.db"].Error)
+	assert.Contains(t, results["orders.db"].Error.Error(), "failed to fetch the results")
+}
+
+func TestNewFederationCheck_federatedResultsExpire(t *testing.T) {
+	remote := gosundheit.New()
+	defer remote.DeregisterAll()
+	require.NoError(t, remote.RegisterAsyncCheck("db"))
+	require.NoError(t, remote.ReportResult("db", "connected", nil))
+	server := httptest.NewServer(healthhttp.HandleHealthJSON(remote))
+	defer server.Close()
+
+	local := gosundheit.New()
+	defer local.DeregisterAll()
+	check, err := NewFederationCheck(FederationCheckConfig{CheckName: "orders", URL: server.URL},
+		WithFederatedResults(local, 50*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	require.NoError(t, err)
+	results, healthy := local.Results()
+	assert.True(t, healthy)
+	assert.NoError(t, results["orders.db"].Error)
+
+	// the federation check is no longer executed, e.g. after it was deregistered
+	assert.Eventually(t, func() bool {
+		results, healthy = local.Results()
+		return !healthy
+	}, time.Second, 10*time.Millisecond, "the federated results expire")
+	assert.Equal(t, gosundheit.ErrResultExpired, errors.Cause(results["orders.db"].Error))
+}
@@ -0,0 +1,148 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// DefaultGatewayCheckConfig configures a check for the reachability of the default gateway.
+type DefaultGatewayCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// TCPPort is optional; if defined, the gateway is probed by connecting to this TCP port, where a refused connection also
+	// counts as a reply. Otherwise, the gateway is probed using ICMP echo requests (or unprivileged UDP probes, see NewICMPPingCheck).
+	TCPPort int
+	// Count is the number of probes, defaults to 3. The check fails when none of them is replied.
+	Count int
+}
+
+// DefaultGatewayDetails are the details of a default gateway check
+type DefaultGatewayDetails struct {
+	// Interface is the network interface of the default route
+	Interface string `json:"interface"`
+	ICMPPingDetails
+}
+
+type defaultGatewayCheck struct {
+	config DefaultGatewayCheckConfig
+	// readDefaultRoutes and newProber are replaceable for testing
+	readDefaultRoutes func() ([]DefaultRoute, error)
+	newProber         func(ip net.IP) (prober, error)
+}
+
+// NewDefaultGatewayCheck returns a Check that discovers the default gateway from the default routes of the host, and verifies
+// it is reachable, which distinguishes a broken local (e.g. pod) network from a dependency which is down.
+// The default route and the probes results are reported as the check details. Default routes are only supported on Linux.
+func NewDefaultGatewayCheck(config DefaultGatewayCheckConfig) (gosundheit.Check, error) {
+	if config.TCPPort < 0 || config.TCPPort > 65535 {
+		return nil, errors.New("TCPPort must be between 0 and 65535")
+	}
+	if config.Count < 0 {
+		return nil, errors.New("Count must not be negative")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Count == 0 {
+		config.Count = 3
+	}
+
+	check := &defaultGatewayCheck{config: config, readDefaultRoutes: readDefaultRoutes, newProber: newProber}
+	if config.TCPPort != 0 {
+		check.newProber = func(ip net.IP) (prober, error) {
+			return &tcpProber{ip: ip, port: config.TCPPort}, nil
+		}
+	}
+	return check, nil
+}
+
+func (c *defaultGatewayCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *defaultGatewayCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	routes, err := c.readDefaultRoutes()
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("no default route")
+	}
+	// the IPv4 routes are listed first
+	var route *DefaultRoute
+	for i := range routes {
+		if routes[i].Gateway != nil {
+			route = &routes[i]
+			break
+		}
+	}
+	if route == nil {
+		return routes, errors.New("the default routes have no gateway")
+	}
+
+	p, err := c.newProber(route.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	defer p.close()
+
+	gatewayDetails := DefaultGatewayDetails{Interface: route.Interface}
+	ping := &gatewayDetails.ICMPPingDetails
+	ping.Address, ping.Method = route.Gateway.String(), p.method()
+	var totalRTT time.Duration
+	for seq := 0; seq < c.config.Count && ctx.Err() == nil; seq++ {
+		ping.Sent++
+		rtt, err := p.probe(ctx, seq)
+		if err != nil {
+			continue
+		}
+		ping.Received++
+		totalRTT += rtt
+		if ping.MinRTT == 0 || rtt < ping.MinRTT {
+			ping.MinRTT = rtt
+		}
+		if rtt > ping.MaxRTT {
+			ping.MaxRTT = rtt
+		}
+	}
+	ping.Loss = float64(c.config.Count-ping.Received) / float64(c.config.Count)
+	if ping.Received == 0 {
+		return gatewayDetails, errors.Errorf("default gateway %s via '%s' is unreachable", ping.Address, route.Interface)
+	}
+	ping.AvgRTT = totalRTT / time.Duration(ping.Received)
+	return gatewayDetails, nil
+}
+
+// tcpProber connects to a TCP port, and treats both an established and a refused connection as a reply
+type tcpProber struct {
+	ip   net.IP
+	port int
+}
+
+func (p *tcpProber) method() string {
+	return "tcp"
+}
+
+func (p *tcpProber) probe(ctx context.Context, _ int) (time.Duration, error) {
+	dialer := net.Dialer{Deadline: probeDeadline(ctx)}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(p.ip.String(), strconv.Itoa(p.port)))
+	if err == nil {
+		rtt := time.Since(start)
+		_ = conn.Close()
+		return rtt, nil
+	}
+	if isConnRefused(err) {
+		return time.Since(start), nil
+	}
+	return 0, err
+}
+
+func (p *tcpProber) close() {}
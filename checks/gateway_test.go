@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDefaultGatewayCheck_config(t *testing.T) {
+	_, err := NewDefaultGatewayCheck(DefaultGatewayCheckConfig{})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewDefaultGatewayCheck(DefaultGatewayCheckConfig{CheckName: checkName, TCPPort: 70000})
+	assert.EqualError(t, err, "TCPPort must be between 0 and 65535")
+
+	_, err = NewDefaultGatewayCheck(DefaultGatewayCheckConfig{CheckName: checkName, Count: -1})
+	assert.EqualError(t, err, "Count must not be negative")
+}
+
+func newTestDefaultGatewayCheck(t *testing.T, routes []DefaultRoute, rtts ...time.Duration) *defaultGatewayCheck {
+	check, err := NewDefaultGatewayCheck(DefaultGatewayCheckConfig{CheckName: checkName, Count: len(rtts)})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	gatewayCheck := check.(*defaultGatewayCheck)
+	gatewayCheck.readDefaultRoutes = func() ([]DefaultRoute, error) { return routes, nil }
+	gatewayCheck.newProber = func(net.IP) (prober, error) { return &fakeProber{rtts: rtts}, nil }
+	return gatewayCheck
+}
+
+func TestNewDefaultGatewayCheck(t *testing.T) {
+	routes := []DefaultRoute{
+		{Interface: "tun0"},
+		{Interface: "eth0", Gateway: net.IPv4(10, 0, 0, 1)},
+	}
+	details, err := newTestDefaultGatewayCheck(t, routes, time.Millisecond, 0, 3*time.Millisecond).Execute(context.Background())
+	require.NoError(t, err, "partial loss")
+	gateway := details.(DefaultGatewayDetails)
+	assert.Equal(t, "eth0", gateway.Interface)
+	assert.Equal(t, "10.0.0.1", gateway.Address)
+	assert.Equal(t, 3, gateway.Sent)
+	assert.Equal(t, 2, gateway.Received)
+	assert.InDelta(t, 1.0/3, gateway.Loss, 0.001)
+	assert.Equal(t, time.Millisecond, gateway.MinRTT)
+	assert.Equal(t, 2*time.Millisecond, gateway.AvgRTT)
+	assert.Equal(t, 3*time.Millisecond, gateway.MaxRTT)
+
+	details, err = newTestDefaultGatewayCheck(t, routes, 0, 0).Execute(context.Background())
+	assert.EqualError(t, err, "default gateway 10.0.0.1 via 'eth0' is unreachable")
+	assert.Equal(t, 0, details.(DefaultGatewayDetails).Received)
+	assert.Equal(t, float64(1), details.(DefaultGatewayDetails).Loss)
+
+	_, err = newTestDefaultGatewayCheck(t, nil, time.Millisecond).Execute(context.Background())
+	assert.EqualError(t, err, "no default route")
+
+	_, err = newTestDefaultGatewayCheck(t, routes[:1], time.Millisecond).Execute(context.Background())
+	assert.EqualError(t, err, "the default routes have no gateway")
+
+	check := newTestDefaultGatewayCheck(t, routes, time.Millisecond)
+	check.readDefaultRoutes = func() ([]DefaultRoute, error) { return nil, errors.New("permission denied") }
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "permission denied")
+}
+
+func TestNewDefaultGatewayCheck_tcpProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	p := &tcpProber{ip: net.IPv4(127, 0, 0, 1), port: port}
+	assert.Equal(t, "tcp", p.method())
+	_, err = p.probe(context.Background(), 0)
+	assert.NoError(t, err, "established connection")
+
+	require.NoError(t, listener.Close())
+	_, err = p.probe(context.Background(), 0)
+	assert.NoError(t, err, "refused connection is a reply")
+
+	check, err := NewDefaultGatewayCheck(DefaultGatewayCheckConfig{CheckName: checkName, TCPPort: port})
+	require.NoError(t, err)
+	tcp, err := check.(*defaultGatewayCheck).newProber(net.IPv4(127, 0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, &tcpProber{ip: net.IPv4(127, 0, 0, 1), port: port}, tcp, "prober of port "+strconv.Itoa(port))
+}
@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// GCThresholds are the garbage collection pressure thresholds of a GC check.
+// Zero values mean no threshold.
+type GCThresholds struct {
+	// MaxLastPause is the maximum duration of the most recent GC pause.
+	MaxLastPause time.Duration
+	// MaxPauseP99 is the maximum 99th percentile of the recent GC pauses.
+	MaxPauseP99 time.Duration
+	// MaxCPUFraction is the maximum fraction (between 0 and 1) of the available CPU time used by the GC since the previous execution
+	// of the check (or since the program started, on the first execution).
+	MaxCPUFraction float64
+}
+
+// GCDetails are the garbage collection statistics reported by a GC check,
+// where CPUFraction is the fraction of the available CPU time used by the GC since the previous execution of the check
+type GCDetails struct {
+	NumGC       int64         `json:"numGC"`
+	LastGC      time.Time     `json:"lastGC"`
+	LastPause   time.Duration `json:"lastPause"`
+	PauseP50    time.Duration `json:"pauseP50"`
+	PauseP90    time.Duration `json:"pauseP90"`
+	PauseP99    time.Duration `json:"pauseP99"`
+	PauseMax    time.Duration `json:"pauseMax"`
+	CPUFraction float64       `json:"cpuFraction"`
+}
+
+// gcCPUTimes are the cumulative CPU time used by the GC, and the total CPU time available to the program, in seconds
+type gcCPUTimes struct {
+	gc    float64
+	total float64
+}
+
+type gcCheck struct {
+	name       string
+	thresholds GCThresholds
+	// readGCStats and readCPUTimes read the runtime statistics, and are replaceable for testing
+	readGCStats  func(stats *debug.GCStats)
+	readCPUTimes func() gcCPUTimes
+
+	lock sync.Mutex
+	// prevCPUTimes are the CPU times read by the previous execution, or zero before the first execution
+	prevCPUTimes gcCPUTimes
+}
+
+// NewGCCheck returns a Check that inspects the garbage collection statistics of the program, and fails when the GC pauses
+// or the GC CPU usage exceed the given thresholds. Pause percentiles are computed over the recent pauses retained by the runtime,
+// and the GC CPU usage is computed between consecutive executions, so that it reflects the recent GC pressure.
+// The statistics are reported as the check details.
+func NewGCCheck(name string, thresholds GCThresholds) (gosundheit.Check, error) {
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &gcCheck{
+		name:         name,
+		thresholds:   thresholds,
+		readGCStats:  debug.ReadGCStats,
+		readCPUTimes: readGCCPUTimes,
+	}, nil
+}
+
+func (c *gcCheck) Name() string {
+	return c.name
+}
+
+func (c *gcCheck) Execute(_ context.Context) (details interface{}, err error) {
+	// percentiles 0 through 100
+	stats := debug.GCStats{PauseQuantiles: make([]time.Duration, 101)}
+	c.readGCStats(&stats)
+
+	gc := GCDetails{
+		NumGC:    stats.NumGC,
+		LastGC:   stats.LastGC,
+		PauseP50: stats.PauseQuantiles[50],
+		PauseP90: stats.PauseQuantiles[90],
+		PauseP99: stats.PauseQuantiles[99],
+		PauseMax: stats.PauseQuantiles[100],
+	}
+	if len(stats.Pause) > 0 {
+		gc.LastPause = stats.Pause[0]
+	}
+
+	times := c.readCPUTimes()
+	c.lock.Lock()
+	prev := c.prevCPUTimes
+	c.prevCPUTimes = times
+	c.lock.Unlock()
+	if total := times.total - prev.total; total > 0 {
+		gc.CPUFraction = (times.gc - prev.gc) / total
+	}
+
+	max := c.thresholds
+	switch {
+	case max.MaxLastPause > 0 && gc.LastPause > max.MaxLastPause:
+		err = errors.Errorf("last GC pause of %v exceeds the maximum of %v", gc.LastPause, max.MaxLastPause)
+	case max.MaxPauseP99 > 0 && gc.PauseP99 > max.MaxPauseP99:
+		err = errors.Errorf("GC pause 99th percentile of %v exceeds the maximum of %v", gc.PauseP99, max.MaxPauseP99)
+	case max.MaxCPUFraction > 0 && gc.CPUFraction > max.MaxCPUFraction:
+		err = errors.Errorf("GC CPU fraction of %.4f exceeds the maximum of %.4f", gc.CPUFraction, max.MaxCPUFraction)
+	}
+
+	return gc, err
+}
@@ -0,0 +1,21 @@
+//go:build !go1.20
+// +build !go1.20
+
+package checks
+
+import (
+	"runtime"
+	"time"
+)
+
+// programStart approximates the time the program started, since which the runtime computes the GC CPU fraction
+var programStart = time.Now()
+
+// readGCCPUTimes derives the CPU times from the GC CPU fraction since the program started.
+// Note that reading it using runtime.ReadMemStats stops the world, which runtime/metrics avoids since Go 1.20.
+func readGCCPUTimes() gcCPUTimes {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	total := time.Since(programStart).Seconds() * float64(runtime.GOMAXPROCS(0))
+	return gcCPUTimes{gc: memStats.GCCPUFraction * total, total: total}
+}
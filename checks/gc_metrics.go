@@ -0,0 +1,19 @@
+//go:build go1.20
+// +build go1.20
+
+package checks
+
+import "runtime/metrics"
+
+// readGCCPUTimes reads the CPU times from the runtime metrics, which unlike runtime.ReadMemStats does not stop the world
+func readGCCPUTimes() gcCPUTimes {
+	samples := []metrics.Sample{
+		{Name: "/cpu/classes/gc/total:cpu-seconds"},
+		{Name: "/cpu/classes/total:cpu-seconds"},
+	}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindFloat64 || samples[1].Value.Kind() != metrics.KindFloat64 {
+		return gcCPUTimes{}
+	}
+	return gcCPUTimes{gc: samples[0].Value.Float64(), total: samples[1].Value.Float64()}
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGCCheck(t *testing.T) {
+	_, err := NewGCCheck("", GCThresholds{})
+	assert.EqualError(t, err, "check name must not be empty")
+
+	runtime.GC()
+	check, err := NewGCCheck(checkName, GCThresholds{})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	gc := details.(GCDetails)
+	assert.True(t, gc.NumGC > 0, "NumGC")
+	assert.False(t, gc.LastGC.IsZero(), "LastGC")
+	assert.True(t, gc.PauseMax >= gc.PauseP99 && gc.PauseP99 >= gc.PauseP50, "pause percentiles")
+}
+
+func TestNewGCCheck_thresholds(t *testing.T) {
+	newCheck := func(thresholds GCThresholds) *gcCheck {
+		check, err := NewGCCheck(checkName, thresholds)
+		require.NoError(t, err)
+		gc := check.(*gcCheck)
+		gc.readGCStats = func(stats *debug.GCStats) {
+			stats.NumGC = 3
+			stats.Pause = []time.Duration{30 * time.Millisecond, 2 * time.Millisecond, time.Millisecond}
+			for i := range stats.PauseQuantiles {
+				stats.PauseQuantiles[i] = time.Duration(i) * 100 * time.Microsecond
+			}
+		}
+		gc.readCPUTimes = func() gcCPUTimes { return gcCPUTimes{gc: 1, total: 10} }
+		return gc
+	}
+
+	details, err := newCheck(GCThresholds{MaxLastPause: 50 * time.Millisecond, MaxPauseP99: 10 * time.Millisecond, MaxCPUFraction: 0.2}).Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, GCDetails{
+		NumGC:       3,
+		LastPause:   30 * time.Millisecond,
+		PauseP50:    5 * time.Millisecond,
+		PauseP90:    9 * time.Millisecond,
+		PauseP99:    9900 * time.Microsecond,
+		PauseMax:    10 * time.Millisecond,
+		CPUFraction: 0.1,
+	}, details)
+
+	_, err = newCheck(GCThresholds{MaxLastPause: 20 * time.Millisecond}).Execute(context.Background())
+	assert.EqualError(t, err, "last GC pause of 30ms exceeds the maximum of 20ms")
+
+	_, err = newCheck(GCThresholds{MaxPauseP99: 5 * time.Millisecond}).Execute(context.Background())
+	assert.EqualError(t, err, "GC pause 99th percentile of 9.9ms exceeds the maximum of 5ms")
+
+	_, err = newCheck(GCThresholds{MaxCPUFraction: 0.05}).Execute(context.Background())
+	assert.EqualError(t, err, "GC CPU fraction of 0.1000 exceeds the maximum of 0.0500")
+}
+
+func TestNewGCCheck_recentCPUFraction(t *testing.T) {
+	check, err := NewGCCheck(checkName, GCThresholds{MaxCPUFraction: 0.05})
+	require.NoError(t, err)
+	gc := check.(*gcCheck)
+	gc.readGCStats = func(stats *debug.GCStats) {}
+	times := []gcCPUTimes{{gc: 1, total: 100}, {gc: 3, total: 110}, {gc: 3.1, total: 120}}
+	gc.readCPUTimes = func() gcCPUTimes {
+		next := times[0]
+		times = times[1:]
+		return next
+	}
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.01, details.(GCDetails).CPUFraction, 1e-9, "the fraction since the program started")
+
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "GC CPU fraction of 0.2000 exceeds the maximum of 0.0500",
+		"the recent GC pressure is detected, even though the fraction since the program started is lower")
+	assert.InDelta(t, 0.2, details.(GCDetails).CPUFraction, 1e-9)
+
+	details, err = check.Execute(context.Background())
+	assert.NoError(t, err, "the GC pressure has passed")
+	assert.InDelta(t, 0.01, details.(GCDetails).CPUFraction, 1e-9)
+}
@@ -1,17 +1,26 @@
 package checks
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	gosundheit "github.com/AppsFlyer/go-sundheit"
 	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
 )
 
 // HTTPCheckConfig configures a check for the response from a given URL.
@@ -27,43 +36,137 @@ type HTTPCheckConfig struct {
 	Method string
 	// Body is an optional request body to be posted to the target URL.
 	Body BodyProvider
-	// ExpectedStatus is the expected response status code, defaults to `200`.
+	// ExpectedStatus is the expected response status code, defaults to `200` unless ExpectedStatuses or ExpectedStatusRange are defined.
 	ExpectedStatus int
+	// ExpectedStatuses are optional additional expected response status codes, e.g. []int{200, 204}.
+	ExpectedStatuses []int
+	// ExpectedStatusRange is an optional inclusive range of expected response status codes,
+	// e.g. StatusRange{Min: 200, Max: 299}, or StatusRange{Min: 100, Max: 499} for any non-5xx status.
+	ExpectedStatusRange StatusRange
 	// ExpectedBody is optional; if defined, operates as a basic "body should contain <string>".
 	ExpectedBody string
-	// Client is optional; if undefined, a new client will be created using "Timeout".
+	// ExpectedJSON is optional; if defined, the body must be a JSON document with the expected values at the given paths.
+	// Paths are dot separated object keys or array indices, e.g. {"status": "UP", "components.db.status": "UP", "nodes.0.ready": true}.
+	ExpectedJSON map[string]interface{}
+	// ExpectedBodyRegex is optional; if defined, the body must match the regular expression, which is compiled when the check is created.
+	ExpectedBodyRegex string
+	// ExpectedHeaders are optional response headers, which must have the expected values. A value enclosed in slashes,
+	// e.g. "/^backend-\\d+$/", is a regular expression the header value must match, which is compiled when the check is created.
+	ExpectedHeaders map[string]string
+	// MaxLatency is optional; if defined, the check fails when the response headers take longer to arrive,
+	// and the check details are HTTPCheckDetails, which include the measured latency.
+	MaxLatency time.Duration
+	// BasicAuth is optional; if defined, the request is authenticated using HTTP basic authentication.
+	BasicAuth *BasicAuth
+	// BearerToken is optional; if defined, the request is authenticated using the static bearer token.
+	BearerToken string
+	// BearerTokenProvider is optional; if defined, it is called on every execution to obtain the bearer token the request
+	// is authenticated with, which allows rotating tokens. It takes precedence over BearerToken.
+	BearerTokenProvider func(ctx context.Context) (string, error)
+	// OAuth2 is optional; if defined, the request is authenticated using access tokens obtained with the OAuth2 client
+	// credentials grant, which are refreshed transparently (see NewOAuth2TokenProvider). It is ignored when BearerTokenProvider is defined.
+	OAuth2 *OAuth2ClientCredentials
+	// Client is optional; if undefined, a new client will be created using "Timeout" and the TLS settings.
 	Client *http.Client
+	// TLSConfig is optional, and configures the TLS connections of the client created when Client is undefined.
+	TLSConfig *tls.Config
+	// RootCAs are optional certificate authorities (e.g. a private CA) used to verify servers,
+	// by the client created when Client is undefined.
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify indicates when true, that servers certificates are not verified
+	// by the client created when Client is undefined. It should only be used for testing.
+	InsecureSkipVerify bool
+	// ForceHTTP2 indicates when true, that the client created when Client is undefined only speaks HTTP/2:
+	// over TLS for https URLs, and using h2c (cleartext HTTP/2 with prior knowledge) for http URLs, e.g. of gRPC-gateway style backends.
+	// The check details are then HTTPCheckDetails, which include the negotiated protocol.
+	ForceHTTP2 bool
 	// Timeout is the timeout used for the HTTP request, defaults to "1s".
 	Timeout time.Duration
 	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
 	Options []RequestOption
 }
 
+// BasicAuth are HTTP basic authentication credentials
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// StatusRange is an inclusive range of HTTP status codes
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+func (r StatusRange) contains(status int) bool {
+	return r != StatusRange{} && status >= r.Min && status <= r.Max
+}
+
+// HTTPCheckDetails are the details of an HTTP check with a MaxLatency or ForceHTTP2, and of each URL of a multi URL check
+type HTTPCheckDetails struct {
+	URL string `json:"url"`
+	// Latency is the duration until the response headers were received
+	Latency time.Duration `json:"latency"`
+	// Protocol is the negotiated protocol of the response, e.g. "HTTP/2.0", or empty when no response was received
+	Protocol string `json:"protocol,omitempty"`
+	// Error is the error the check failed with, if any
+	Error string `json:"error,omitempty"`
+}
+
 // RequestOption configures the request with arbitrary settings, e.g. add request headers, etc.
 type RequestOption func(r *http.Request)
 
 type httpCheck struct {
 	config         *HTTPCheckConfig
 	successDetails string
+	bodyRegex      *regexp.Regexp
+	headerRegexes  map[string]*regexp.Regexp
+	// detailed is set when the details are always HTTPCheckDetails, regardless of the config
+	detailed bool
 }
 
 // BodyProvider allows the users to provide a body to the HTTP checks. For example for posting a payload as a check.
 type BodyProvider func() io.Reader
 
 // NewHTTPCheck creates a new http check defined by the given config
-func NewHTTPCheck(config HTTPCheckConfig) (check gosundheit.Check, err error) {
+func NewHTTPCheck(config HTTPCheckConfig) (gosundheit.Check, error) {
+	check, err := newHTTPCheck(config)
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func newHTTPCheck(config HTTPCheckConfig) (*httpCheck, error) {
 	if config.URL == "" {
 		return nil, errors.Errorf("URL must not be empty")
 	}
-	_, err = url.Parse(config.URL)
+	parsedURL, err := url.Parse(config.URL)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	if config.CheckName == "" {
 		return nil, errors.Errorf("CheckName must not be empty")
 	}
+	var bodyRegex *regexp.Regexp
+	if config.ExpectedBodyRegex != "" {
+		if bodyRegex, err = regexp.Compile(config.ExpectedBodyRegex); err != nil {
+			return nil, errors.Errorf("invalid ExpectedBodyRegex: %v", err)
+		}
+	}
+	headerRegexes := make(map[string]*regexp.Regexp)
+	for header, value := range config.ExpectedHeaders {
+		if len(value) > 1 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+			if headerRegexes[header], err = regexp.Compile(value[1 : len(value)-1]); err != nil {
+				return nil, errors.Errorf("invalid ExpectedHeaders regex of '%s': %v", header, err)
+			}
+		}
+	}
 
-	if config.ExpectedStatus == 0 {
+	if config.ExpectedStatusRange.Min > config.ExpectedStatusRange.Max {
+		return nil, errors.Errorf("ExpectedStatusRange min must not be greater than max")
+	}
+	if config.ExpectedStatus == 0 && len(config.ExpectedStatuses) == 0 && config.ExpectedStatusRange == (StatusRange{}) {
 		config.ExpectedStatus = http.StatusOK
 	}
 	if config.Method == "" {
@@ -72,17 +175,38 @@ func NewHTTPCheck(config HTTPCheckConfig) (check gosundheit.Check, err error) {
 	if config.Body == nil {
 		config.Body = func() io.Reader { return http.NoBody }
 	}
+	if config.OAuth2 != nil && config.BearerTokenProvider == nil {
+		if config.BearerTokenProvider, err = NewOAuth2TokenProvider(*config.OAuth2); err != nil {
+			return nil, errors.Errorf("invalid OAuth2 configuration: %v", err)
+		}
+	}
 	if config.Timeout == 0 {
 		config.Timeout = time.Second
 	}
+	hasTLSSettings := config.TLSConfig != nil || config.RootCAs != nil || config.InsecureSkipVerify
+	if config.Client != nil && hasTLSSettings {
+		return nil, errors.Errorf("TLS settings must not be defined together with Client")
+	}
+	if config.Client != nil && config.ForceHTTP2 {
+		return nil, errors.Errorf("ForceHTTP2 must not be defined together with Client")
+	}
 	if config.Client == nil {
 		config.Client = &http.Client{}
+		switch {
+		case config.ForceHTTP2:
+			tlsConfig := newTLSConfig(config.TLSConfig, config.RootCAs, config.InsecureSkipVerify)
+			config.Client.Transport = newHTTP2Transport(tlsConfig, parsedURL.Scheme == "http")
+		case hasTLSSettings:
+			config.Client.Transport = newTLSTransport(config.TLSConfig, config.RootCAs, config.InsecureSkipVerify)
+		}
 	}
 	config.Client.Timeout = config.Timeout
 
-	check = &httpCheck{
+	check := &httpCheck{
 		config:         &config,
 		successDetails: fmt.Sprintf("URL [%s] is accessible", config.URL),
+		bodyRegex:      bodyRegex,
+		headerRegexes:  headerRegexes,
 	}
 	return check, nil
 }
@@ -92,19 +216,35 @@ func (check *httpCheck) Name() string {
 }
 
 func (check *httpCheck) Execute(ctx context.Context) (details interface{}, err error) {
-	details = check.config.URL
+	details, err = check.execute(ctx)
+	if httpDetails, ok := details.(HTTPCheckDetails); ok && err != nil {
+		httpDetails.Error = err.Error()
+		details = httpDetails
+	}
+	return details, err
+}
+
+func (check *httpCheck) execute(ctx context.Context) (details interface{}, err error) {
+	start := time.Now()
 	resp, err := check.fetchURL(ctx)
+	latency := time.Since(start)
+	details = check.details(check.config.URL, latency, nil)
 	if err != nil {
 		return details, err
 	}
 	defer func() { _ = resp.Body.Close() }()
+	details = check.details(check.config.URL, latency, resp)
 
-	if resp.StatusCode != check.config.ExpectedStatus {
+	if !check.expectedStatus(resp.StatusCode) {
 		return details, errors.Errorf("unexpected status code: '%v' expected: '%v'",
-			resp.StatusCode, check.config.ExpectedStatus)
+			resp.StatusCode, check.expectedStatusDescription())
 	}
 
-	if check.config.ExpectedBody != "" {
+	if err := check.assertHeaders(resp.Header); err != nil {
+		return details, err
+	}
+
+	if check.config.ExpectedBody != "" || len(check.config.ExpectedJSON) > 0 || check.bodyRegex != nil {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
 			return details, errors.Errorf("failed to read response body: %v", err)
@@ -113,10 +253,139 @@ func (check *httpCheck) Execute(ctx context.Context) (details interface{}, err e
 		if !strings.Contains(string(body), check.config.ExpectedBody) {
 			return details, errors.Errorf("body does not contain expected content '%v'", check.config.ExpectedBody)
 		}
+		if check.bodyRegex != nil && !check.bodyRegex.Match(body) {
+			return details, errors.Errorf("body does not match expected regex '%v'", check.config.ExpectedBodyRegex)
+		}
+		if err := assertJSON(body, check.config.ExpectedJSON); err != nil {
+			return details, err
+		}
+	}
+
+	if check.config.MaxLatency > 0 && latency > check.config.MaxLatency {
+		return details, errors.Errorf("response latency of %v exceeds the maximum of %v", latency, check.config.MaxLatency)
+	}
+
+	return check.details(check.successDetails, latency, resp), nil
+}
+
+// assertHeaders verifies the response headers have the expected values
+func (check *httpCheck) assertHeaders(header http.Header) error {
+	names := make([]string, 0, len(check.config.ExpectedHeaders))
+	for name := range check.config.ExpectedHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			return errors.Errorf("header '%s' is missing", name)
+		}
+		expected, regex := check.config.ExpectedHeaders[name], check.headerRegexes[name]
+		matched := false
+		for _, value := range values {
+			matched = matched || (regex != nil && regex.MatchString(value)) || (regex == nil && value == expected)
+		}
+		if !matched {
+			return errors.Errorf("header '%s' is '%s', expected '%s'", name, strings.Join(values, ", "), expected)
+		}
+	}
+	return nil
+}
+
+// authenticate sets the Authorization header of the request according to the configured credentials, if any
+func (check *httpCheck) authenticate(ctx context.Context, req *http.Request) error {
+	if auth := check.config.BasicAuth; auth != nil {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+
+	token := check.config.BearerToken
+	if check.config.BearerTokenProvider != nil {
+		var err error
+		if token, err = check.config.BearerTokenProvider(ctx); err != nil {
+			return errors.Errorf("failed to obtain bearer token: %v", err)
+		}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func (check *httpCheck) expectedStatus(status int) bool {
+	if status == check.config.ExpectedStatus || check.config.ExpectedStatusRange.contains(status) {
+		return true
+	}
+	for _, expected := range check.config.ExpectedStatuses {
+		if status == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func (check *httpCheck) expectedStatusDescription() string {
+	var expected []string
+	if check.config.ExpectedStatus != 0 {
+		expected = append(expected, strconv.Itoa(check.config.ExpectedStatus))
+	}
+	for _, status := range check.config.ExpectedStatuses {
+		expected = append(expected, strconv.Itoa(status))
 	}
+	if r := check.config.ExpectedStatusRange; r != (StatusRange{}) {
+		expected = append(expected, fmt.Sprintf("%d-%d", r.Min, r.Max))
+	}
+	return strings.Join(expected, ", ")
+}
+
+// details returns the given details, or HTTPCheckDetails with the measured latency and the negotiated protocol
+// when MaxLatency or ForceHTTP2 are defined, or the check is detailed. The response is nil when none was received.
+func (check *httpCheck) details(details string, latency time.Duration, resp *http.Response) interface{} {
+	if check.config.MaxLatency == 0 && !check.config.ForceHTTP2 && !check.detailed {
+		return details
+	}
+	httpDetails := HTTPCheckDetails{URL: check.config.URL, Latency: latency}
+	if resp != nil {
+		httpDetails.Protocol = resp.Proto
+	}
+	return httpDetails
+}
 
-	return check.successDetails, nil
+// newTLSTransport returns a clone of the default transport, with the given TLS settings
+func newTLSTransport(tlsConfig *tls.Config, rootCAs *x509.CertPool, insecureSkipVerify bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = newTLSConfig(tlsConfig, rootCAs, insecureSkipVerify)
+	return transport
+}
 
+// newHTTP2Transport returns an HTTP/2 only transport with the given TLS config.
+// When cleartext is true, the transport uses h2c, i.e. HTTP/2 over plain TCP connections with prior knowledge.
+func newHTTP2Transport(tlsConfig *tls.Config, cleartext bool) *http2.Transport {
+	transport := &http2.Transport{TLSClientConfig: tlsConfig}
+	if cleartext {
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	return transport
+}
+
+// newTLSConfig returns a clone of the given TLS config, or a new one if nil, with the given root CAs and verification settings
+func newTLSConfig(tlsConfig *tls.Config, rootCAs *x509.CertPool, insecureSkipVerify bool) *tls.Config {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if rootCAs != nil {
+		tlsConfig.RootCAs = rootCAs
+	}
+	if insecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true // #nosec G402 -- explicitly configured
+	}
+	return tlsConfig
 }
 
 // fetchURL executes the HTTP request to the target URL, and returns a `http.Response`, error.
@@ -127,6 +396,9 @@ func (check *httpCheck) fetchURL(ctx context.Context) (*http.Response, error) {
 		return nil, errors.Errorf("unable to create check HTTP request: %v", err)
 	}
 
+	if err := check.authenticate(ctx, req); err != nil {
+		return nil, err
+	}
 	configureHTTPOptions(req, check.config.Options)
 
 	resp, err := check.config.Client.Do(req)
@@ -137,6 +409,93 @@ func (check *httpCheck) fetchURL(ctx context.Context) (*http.Response, error) {
 	return resp, nil
 }
 
+// fetchJSON executes a GET request to the given URL using the given client, and decodes the JSON response body into `out`.
+// The check fails unless the response status is one of the given expected statuses, or 200 if none are given.
+func fetchJSON(ctx context.Context, client *http.Client, url string, options []RequestOption, out interface{}, expectedStatuses ...int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Errorf("unable to create check HTTP request: %v", err)
+	}
+	configureHTTPOptions(req, options)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Errorf("fail to execute 'GET' request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if len(expectedStatuses) == 0 {
+		expectedStatuses = []int{http.StatusOK}
+	}
+	expected := false
+	for _, status := range expectedStatuses {
+		expected = expected || resp.StatusCode == status
+	}
+	if !expected {
+		return errors.Errorf("unexpected status code: '%v' expected: '%v'", resp.StatusCode, expectedStatuses)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Errorf("failed to decode response body: %v", err)
+	}
+	return nil
+}
+
+// assertJSON verifies the JSON document has the expected values at the given paths
+func assertJSON(body []byte, expected map[string]interface{}) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return errors.Errorf("failed to decode response body: %v", err)
+	}
+
+	paths := make([]string, 0, len(expected))
+	for path := range expected {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		value, ok := jsonPathValue(document, path)
+		if !ok {
+			return errors.Errorf("JSON path '%s' not found", path)
+		}
+		// compared in their JSON representation, so that e.g. an expected int matches a decoded float64
+		actualJSON, _ := json.Marshal(value)
+		expectedJSON, err := json.Marshal(expected[path])
+		if err != nil {
+			return errors.Errorf("invalid expected value of JSON path '%s': %v", path, err)
+		}
+		if !bytes.Equal(actualJSON, expectedJSON) {
+			return errors.Errorf("JSON path '%s' is %s, expected %s", path, actualJSON, expectedJSON)
+		}
+	}
+	return nil
+}
+
+func jsonPathValue(document interface{}, path string) (interface{}, bool) {
+	value := document
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = node[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
 func configureHTTPOptions(req *http.Request, options []RequestOption) {
 	for _, opt := range options {
 		opt(req)
@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// NewMultiURLHTTPCheck returns a check that concurrently probes each of the given URLs (e.g. all the replicas behind a DNS name)
+// using the given HTTP check config, whose URL is ignored. The check passes when at least minPassing of the URLs respond as expected,
+// or all of them when minPassing is zero. The outcome of each URL is reported in the check details (see CompositeChildResult),
+// whose details are always HTTPCheckDetails.
+func NewMultiURLHTTPCheck(config HTTPCheckConfig, urls []string, minPassing int) (gosundheit.Check, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("URLs must not be empty")
+	}
+	if minPassing < 0 || minPassing > len(urls) {
+		return nil, errors.Errorf("minPassing must be between 0 and the number of URLs (%d)", len(urls))
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	// a single token provider, so that the tokens are shared by all the URLs
+	if config.OAuth2 != nil && config.BearerTokenProvider == nil {
+		var err error
+		if config.BearerTokenProvider, err = NewOAuth2TokenProvider(*config.OAuth2); err != nil {
+			return nil, errors.Errorf("invalid OAuth2 configuration: %v", err)
+		}
+	}
+
+	urlChecks := make([]gosundheit.Check, len(urls))
+	for i, url := range urls {
+		urlConfig := config
+		urlConfig.CheckName = url
+		urlConfig.URL = url
+		check, err := newHTTPCheck(urlConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid URL check '%s'", url)
+		}
+		check.detailed = true
+		urlChecks[i] = check
+	}
+
+	var check *CompositeCheck
+	var err error
+	if minPassing == 0 {
+		check, err = All(config.CheckName, urlChecks...)
+	} else {
+		check, err = AtLeast(config.CheckName, minPassing, urlChecks...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return check.InParallel(), nil
+}
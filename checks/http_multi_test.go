@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiURLHTTPCheck_config(t *testing.T) {
+	config := HTTPCheckConfig{CheckName: "replicas.check"}
+
+	_, err := NewMultiURLHTTPCheck(config, nil, 0)
+	assert.EqualError(t, err, "URLs must not be empty")
+
+	_, err = NewMultiURLHTTPCheck(config, []string{"http://10.0.0.1"}, 2)
+	assert.EqualError(t, err, "minPassing must be between 0 and the number of URLs (1)")
+
+	_, err = NewMultiURLHTTPCheck(HTTPCheckConfig{}, []string{"http://10.0.0.1"}, 0)
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewMultiURLHTTPCheck(config, []string{""}, 0)
+	assert.EqualError(t, err, "invalid URL check '': URL must not be empty")
+}
+
+func TestNewMultiURLHTTPCheck(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+	urls := []string{healthy.URL + "/a", unhealthy.URL, healthy.URL + "/b"}
+
+	check, err := NewMultiURLHTTPCheck(HTTPCheckConfig{CheckName: "replicas.check"}, urls, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "replicas.check", check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	results := details.([]CompositeChildResult)
+	for i := range results {
+		httpDetails := results[i].Details.(HTTPCheckDetails)
+		assert.True(t, httpDetails.Latency > 0, "latency of %s", urls[i])
+		httpDetails.Latency = 0
+		results[i].Details = httpDetails
+	}
+	assert.Equal(t, []CompositeChildResult{
+		{Name: urls[0], Details: HTTPCheckDetails{URL: urls[0], Protocol: "HTTP/1.1"}},
+		{Name: urls[1], Details: HTTPCheckDetails{URL: urls[1], Protocol: "HTTP/1.1", Error: "unexpected status code: '503' expected: '200'"},
+			Error: "unexpected status code: '503' expected: '200'"},
+		{Name: urls[2], Details: HTTPCheckDetails{URL: urls[2], Protocol: "HTTP/1.1"}},
+	}, results)
+
+	check, err = NewMultiURLHTTPCheck(HTTPCheckConfig{CheckName: "replicas.check"}, urls, 0)
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, fmt.Sprintf("1 of 3 checks failed: %s", unhealthy.URL))
+}
+
+func TestNewMultiURLHTTPCheck_unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	unreachableURL := server.URL
+	server.Close()
+
+	check, err := NewMultiURLHTTPCheck(HTTPCheckConfig{CheckName: "replicas.check"}, []string{unreachableURL}, 0)
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	assert.Error(t, err)
+	results := details.([]CompositeChildResult)
+	httpDetails, ok := results[0].Details.(HTTPCheckDetails)
+	require.True(t, ok, "details of a URL without a response should be HTTPCheckDetails")
+	assert.Equal(t, unreachableURL, httpDetails.URL)
+	assert.Empty(t, httpDetails.Protocol, "no response was received")
+	assert.Equal(t, results[0].Error, httpDetails.Error)
+	assert.NotEmpty(t, httpDetails.Error)
+}
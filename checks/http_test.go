@@ -2,17 +2,26 @@ package checks
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
 )
 
 const (
@@ -274,3 +283,287 @@ func testHTTPCheckFailTimeout(url string, client *http.Client) func(t *testing.T
 		assert.Equal(t, waitURL, details, "check details when fail are the URL")
 	}
 }
+
+func TestNewHttpCheck_expectedJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`{"status":"UP","components":{"db":{"status":"DOWN","connections":3}},"nodes":[{"ready":true}]}`))
+	}))
+	defer server.Close()
+
+	newCheck := func(expectedJSON map[string]interface{}) gosundheit.Check {
+		check, err := NewHTTPCheck(HTTPCheckConfig{
+			CheckName:    "url.check",
+			URL:          server.URL,
+			Client:       server.Client(),
+			ExpectedJSON: expectedJSON,
+		})
+		require.NoError(t, err)
+		return check
+	}
+
+	details, err := newCheck(map[string]interface{}{
+		"status":                    "UP",
+		"components.db.connections": 3,
+		"nodes.0.ready":             true,
+	}).Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("URL [%s] is accessible", server.URL), details)
+
+	details, err = newCheck(map[string]interface{}{"status": "UP", "components.db.status": "UP"}).Execute(context.Background())
+	assert.EqualError(t, err, `JSON path 'components.db.status' is "DOWN", expected "UP"`)
+	assert.Equal(t, server.URL, details, "check details when fail are the URL")
+
+	_, err = newCheck(map[string]interface{}{"components.cache.status": "UP"}).Execute(context.Background())
+	assert.EqualError(t, err, "JSON path 'components.cache.status' not found")
+
+	_, err = newCheck(map[string]interface{}{"nodes.1.ready": true}).Execute(context.Background())
+	assert.EqualError(t, err, "JSON path 'nodes.1.ready' not found")
+}
+
+func TestNewHttpCheck_expectedBodyRegex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprintf(rw, "All systems operational as of %s", time.Now().Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: server.URL, ExpectedBodyRegex: "(unclosed"})
+	assert.EqualError(t, err, "invalid ExpectedBodyRegex: error parsing regexp: missing closing ): `(unclosed`")
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{
+		CheckName:         "url.check",
+		URL:               server.URL,
+		Client:            server.Client(),
+		ExpectedBodyRegex: `^All systems operational as of \d{4}-`,
+	})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	check, err = NewHTTPCheck(HTTPCheckConfig{
+		CheckName:         "url.check",
+		URL:               server.URL,
+		Client:            server.Client(),
+		ExpectedBodyRegex: `(?i)degraded|outage`,
+	})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "body does not match expected regex '(?i)degraded|outage'")
+	assert.Equal(t, server.URL, details, "check details when fail are the URL")
+}
+
+func TestNewHttpCheck_maxLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if wait, err := time.ParseDuration(req.URL.Query().Get("wait")); err == nil {
+			time.Sleep(wait)
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{
+		CheckName:  "url.check",
+		URL:        server.URL,
+		Client:     server.Client(),
+		MaxLatency: time.Second,
+	})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	httpDetails := details.(HTTPCheckDetails)
+	assert.Equal(t, server.URL, httpDetails.URL)
+	assert.True(t, httpDetails.Latency > 0 && httpDetails.Latency < time.Second, "latency %v", httpDetails.Latency)
+
+	slowURL := server.URL + "?wait=60ms"
+	check, err = NewHTTPCheck(HTTPCheckConfig{
+		CheckName:  "url.check",
+		URL:        slowURL,
+		Client:     server.Client(),
+		MaxLatency: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	details, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the maximum of 50ms")
+	httpDetails = details.(HTTPCheckDetails)
+	assert.Equal(t, slowURL, httpDetails.URL)
+	assert.True(t, httpDetails.Latency >= 60*time.Millisecond, "latency %v", httpDetails.Latency)
+}
+
+func TestNewHttpCheck_expectedStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		status, _ := strconv.Atoi(req.URL.Query().Get("status"))
+		rw.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: server.URL, ExpectedStatusRange: StatusRange{Min: 299, Max: 200}})
+	assert.EqualError(t, err, "ExpectedStatusRange min must not be greater than max")
+
+	execute := func(config HTTPCheckConfig, status int) error {
+		config.CheckName = "url.check"
+		config.URL = fmt.Sprintf("%s?status=%d", server.URL, status)
+		config.Client = server.Client()
+		check, err := NewHTTPCheck(config)
+		require.NoError(t, err)
+		_, err = check.Execute(context.Background())
+		return err
+	}
+
+	statuses := HTTPCheckConfig{ExpectedStatuses: []int{200, 204}}
+	assert.NoError(t, execute(statuses, 200))
+	assert.NoError(t, execute(statuses, 204))
+	assert.EqualError(t, execute(statuses, 302), "unexpected status code: '302' expected: '200, 204'")
+
+	nonServerError := HTTPCheckConfig{ExpectedStatusRange: StatusRange{Min: 100, Max: 499}}
+	assert.NoError(t, execute(nonServerError, 200))
+	assert.NoError(t, execute(nonServerError, 404))
+	assert.EqualError(t, execute(nonServerError, 503), "unexpected status code: '503' expected: '100-499'")
+
+	combined := HTTPCheckConfig{ExpectedStatus: 301, ExpectedStatusRange: StatusRange{Min: 200, Max: 299}}
+	assert.NoError(t, execute(combined, 301))
+	assert.NoError(t, execute(combined, 202))
+	assert.EqualError(t, execute(combined, 302), "unexpected status code: '302' expected: '301, 200-299'")
+}
+
+func TestNewHttpCheck_auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if user, password, ok := req.BasicAuth(); ok && user == "health" && password == "secret" {
+			return
+		}
+		if req.Header.Get("Authorization") == "Bearer token-2" {
+			return
+		}
+		rw.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	execute := func(config HTTPCheckConfig) error {
+		config.CheckName = "url.check"
+		config.URL = server.URL
+		config.Client = server.Client()
+		check, err := NewHTTPCheck(config)
+		require.NoError(t, err)
+		_, err = check.Execute(context.Background())
+		return err
+	}
+
+	assert.EqualError(t, execute(HTTPCheckConfig{}), "unexpected status code: '401' expected: '200'")
+	assert.NoError(t, execute(HTTPCheckConfig{BasicAuth: &BasicAuth{Username: "health", Password: "secret"}}))
+	assert.EqualError(t, execute(HTTPCheckConfig{BasicAuth: &BasicAuth{Username: "health", Password: "wrong"}}), "unexpected status code: '401' expected: '200'")
+	assert.NoError(t, execute(HTTPCheckConfig{BearerToken: "token-2"}))
+
+	tokens := 0
+	rotating := HTTPCheckConfig{
+		BearerToken: "ignored",
+		BearerTokenProvider: func(context.Context) (string, error) {
+			tokens++
+			return fmt.Sprintf("token-%d", tokens), nil
+		},
+	}
+	rotating.CheckName = "url.check"
+	rotating.URL = server.URL
+	rotating.Client = server.Client()
+	check, err := NewHTTPCheck(rotating)
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "unexpected status code: '401' expected: '200'", "token-1")
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err, "token-2")
+
+	assert.EqualError(t, execute(HTTPCheckConfig{
+		BearerTokenProvider: func(context.Context) (string, error) { return "", errors.New("vault is sealed") },
+	}), "failed to obtain bearer token: vault is sealed")
+}
+
+func TestNewHttpCheck_tls(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+
+	execute := func(config HTTPCheckConfig) error {
+		config.CheckName = "url.check"
+		config.URL = server.URL
+		check, err := NewHTTPCheck(config)
+		require.NoError(t, err)
+		_, err = check.Execute(context.Background())
+		return err
+	}
+
+	err := execute(HTTPCheckConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate", "the server certificate is not trusted by default")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	assert.NoError(t, execute(HTTPCheckConfig{RootCAs: roots}))
+	assert.NoError(t, execute(HTTPCheckConfig{TLSConfig: &tls.Config{RootCAs: roots}}))
+	assert.NoError(t, execute(HTTPCheckConfig{InsecureSkipVerify: true}))
+
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: server.URL, Client: server.Client(), RootCAs: roots})
+	assert.EqualError(t, err, "TLS settings must not be defined together with Client")
+}
+
+func TestNewHttpCheck_expectedHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Backend", "backend-17")
+		rw.Header().Add("X-Cache", "MISS")
+		rw.Header().Add("X-Cache", "HIT")
+		rw.Header().Set("Strict-Transport-Security", "max-age=31536000")
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: server.URL, ExpectedHeaders: map[string]string{"X-Backend": "/(unclosed/"}})
+	assert.EqualError(t, err, "invalid ExpectedHeaders regex of 'X-Backend': error parsing regexp: missing closing ): `(unclosed`")
+
+	execute := func(expectedHeaders map[string]string) error {
+		check, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: server.URL, Client: server.Client(), ExpectedHeaders: expectedHeaders})
+		require.NoError(t, err)
+		_, err = check.Execute(context.Background())
+		return err
+	}
+
+	assert.NoError(t, execute(map[string]string{
+		"x-backend":                 `/^backend-\d+$/`,
+		"X-Cache":                   "HIT",
+		"Strict-Transport-Security": "/max-age=\\d+/",
+	}))
+	assert.EqualError(t, execute(map[string]string{"X-Backend": "backend-1"}), "header 'X-Backend' is 'backend-17', expected 'backend-1'")
+	assert.EqualError(t, execute(map[string]string{"X-Cache": "/^STALE$/"}), "header 'X-Cache' is 'MISS, HIT', expected '/^STALE$/'")
+	assert.EqualError(t, execute(map[string]string{"Content-Security-Policy": "/.+/"}), "header 'Content-Security-Policy' is missing")
+}
+
+func TestNewHttpCheck_forceHTTP2(t *testing.T) {
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Proto))
+	})
+	execute := func(config HTTPCheckConfig) (interface{}, error) {
+		config.CheckName = "url.check"
+		config.ForceHTTP2 = true
+		config.ExpectedBody = "HTTP/2.0"
+		check, err := NewHTTPCheck(config)
+		require.NoError(t, err)
+		return check.Execute(context.Background())
+	}
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	details, err := execute(HTTPCheckConfig{URL: tlsServer.URL, InsecureSkipVerify: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", details.(HTTPCheckDetails).Protocol, "negotiated protocol")
+
+	h2cServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer h2cServer.Close()
+	details, err = execute(HTTPCheckConfig{URL: h2cServer.URL})
+	assert.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", details.(HTTPCheckDetails).Protocol, "negotiated protocol")
+
+	http1Server := httptest.NewTLSServer(handler)
+	defer http1Server.Close()
+	details, err = execute(HTTPCheckConfig{URL: http1Server.URL, InsecureSkipVerify: true})
+	assert.Error(t, err, "the server does not support HTTP/2")
+	assert.Equal(t, HTTPCheckDetails{URL: http1Server.URL, Latency: details.(HTTPCheckDetails).Latency, Error: err.Error()}, details)
+
+	_, err = NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: tlsServer.URL, Client: tlsServer.Client(), ForceHTTP2: true})
+	assert.EqualError(t, err, "ForceHTTP2 must not be defined together with Client")
+}
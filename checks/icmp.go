@@ -0,0 +1,260 @@
+package checks
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	// icmpReplyTimeout is the maximum wait for each echo reply, when the check context has no earlier deadline
+	icmpReplyTimeout = time.Second
+	// udpProbePort is the traceroute base port, which is unlikely to be listened on
+	udpProbePort = 33434
+)
+
+// ICMPPingDetails are the details of an ICMP ping check
+type ICMPPingDetails struct {
+	// Address is the resolved address of the pinged host
+	Address string `json:"address"`
+	// Method is "icmp" for ICMP echo requests, "udp" for the unprivileged UDP fallback, or "tcp" for TCP connections (see DefaultGatewayCheckConfig)
+	Method   string        `json:"method"`
+	Sent     int           `json:"sent"`
+	Received int           `json:"received"`
+	Loss     float64       `json:"loss"`
+	MinRTT   time.Duration `json:"minRTT,omitempty"`
+	AvgRTT   time.Duration `json:"avgRTT,omitempty"`
+	MaxRTT   time.Duration `json:"maxRTT,omitempty"`
+}
+
+// prober sends a single probe and waits for its reply, returning the round trip time
+type prober interface {
+	method() string
+	probe(ctx context.Context, seq int) (time.Duration, error)
+	close()
+}
+
+type icmpPingCheck struct {
+	host    string
+	count   int
+	maxLoss float64
+	maxRTT  time.Duration
+	// newProber is replaceable for testing
+	newProber func(ip net.IP) (prober, error)
+}
+
+// NewICMPPingCheck returns a Check named "ping.<host>" that sends count ICMP echo requests to the given host,
+// and fails when the fraction of lost packets exceeds maxLoss (between 0 and 1), or the average round trip time exceeds maxRTT (unless zero).
+// Sending ICMP echo requests requires privileges (e.g. CAP_NET_RAW), so without them the check falls back to sending UDP probes
+// to a closed port, and counts the ICMP port unreachable errors as replies.
+// The packet loss and round trip times are reported as the check details.
+func NewICMPPingCheck(host string, count int, maxLoss float64, maxRTT time.Duration) (gosundheit.Check, error) {
+	if host == "" {
+		return nil, errors.New("host must not be empty")
+	}
+	if count <= 0 {
+		return nil, errors.New("count must be positive")
+	}
+	if maxLoss < 0 || maxLoss > 1 {
+		return nil, errors.New("maxLoss must be between 0 and 1")
+	}
+
+	return &icmpPingCheck{
+		host:      host,
+		count:     count,
+		maxLoss:   maxLoss,
+		maxRTT:    maxRTT,
+		newProber: newProber,
+	}, nil
+}
+
+func (c *icmpPingCheck) Name() string {
+	return "ping." + c.host
+}
+
+func (c *icmpPingCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", c.host)
+	if err != nil {
+		return nil, errors.Errorf("failed to resolve host: %v", err)
+	}
+	ip := ips[0]
+	for _, candidate := range ips {
+		if candidate.To4() != nil {
+			ip = candidate
+			break
+		}
+	}
+
+	p, err := c.newProber(ip)
+	if err != nil {
+		return nil, err
+	}
+	defer p.close()
+
+	ping := ICMPPingDetails{Address: ip.String(), Method: p.method()}
+	var totalRTT time.Duration
+	for seq := 0; seq < c.count && ctx.Err() == nil; seq++ {
+		ping.Sent++
+		rtt, err := p.probe(ctx, seq)
+		if err != nil {
+			continue
+		}
+		ping.Received++
+		totalRTT += rtt
+		if ping.MinRTT == 0 || rtt < ping.MinRTT {
+			ping.MinRTT = rtt
+		}
+		if rtt > ping.MaxRTT {
+			ping.MaxRTT = rtt
+		}
+	}
+	// packets that were not sent due to the context being done are lost
+	ping.Loss = float64(c.count-ping.Received) / float64(c.count)
+	if ping.Received > 0 {
+		ping.AvgRTT = totalRTT / time.Duration(ping.Received)
+	}
+
+	switch {
+	case ping.Loss > c.maxLoss:
+		err = errors.Errorf("packet loss of %.0f%% exceeds the maximum of %.0f%%", 100*ping.Loss, 100*c.maxLoss)
+	case c.maxRTT > 0 && ping.AvgRTT > c.maxRTT:
+		err = errors.Errorf("average round trip time of %v exceeds the maximum of %v", ping.AvgRTT, c.maxRTT)
+	}
+	return ping, err
+}
+
+// newProber returns an ICMP echo prober when permitted, and a UDP prober otherwise
+func newProber(ip net.IP) (prober, error) {
+	network, address := "ip6:ipv6-icmp", "::"
+	if ip.To4() != nil {
+		network, address = "ip4:icmp", "0.0.0.0"
+	}
+	conn, err := net.ListenPacket(network, address)
+	if err == nil {
+		// a distinct echo identifier per prober, since every raw ICMP socket receives all the echo replies
+		id := (os.Getpid() + int(atomic.AddUint32(&icmpProbersCount, 1))) & 0xffff
+		return &icmpProber{conn: conn, ip: ip, id: id}, nil
+	}
+	if !os.IsPermission(err) && !strings.Contains(err.Error(), "operation not permitted") {
+		return nil, errors.Errorf("failed to open ICMP socket: %v", err)
+	}
+	return &udpProber{ip: ip}, nil
+}
+
+func probeDeadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(icmpReplyTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+var icmpProbersCount uint32
+
+type icmpProber struct {
+	conn net.PacketConn
+	ip   net.IP
+	id   int
+}
+
+func (p *icmpProber) method() string {
+	return "icmp"
+}
+
+func (p *icmpProber) probe(ctx context.Context, seq int) (time.Duration, error) {
+	// echo request/reply types: 8/0 for ICMPv4, 128/129 for ICMPv6 (whose checksum is computed by the kernel)
+	requestType, replyType := byte(128), byte(129)
+	if p.ip.To4() != nil {
+		requestType, replyType = 8, 0
+	}
+	request := make([]byte, 16)
+	request[0] = requestType
+	binary.BigEndian.PutUint16(request[4:], uint16(p.id))
+	binary.BigEndian.PutUint16(request[6:], uint16(seq))
+	copy(request[8:], "sundheit")
+	if requestType == 8 {
+		binary.BigEndian.PutUint16(request[2:], icmpChecksum(request))
+	}
+
+	_ = p.conn.SetReadDeadline(probeDeadline(ctx))
+	start := time.Now()
+	if _, err := p.conn.WriteTo(request, &net.IPAddr{IP: p.ip}); err != nil {
+		return 0, err
+	}
+
+	reply := make([]byte, 1500)
+	for {
+		n, from, err := p.conn.ReadFrom(reply)
+		if err != nil {
+			return 0, err
+		}
+		if n < 8 || reply[0] != replyType || !from.(*net.IPAddr).IP.Equal(p.ip) {
+			continue
+		}
+		if int(binary.BigEndian.Uint16(reply[4:])) == p.id && int(binary.BigEndian.Uint16(reply[6:])) == seq {
+			return time.Since(start), nil
+		}
+	}
+}
+
+func (p *icmpProber) close() {
+	_ = p.conn.Close()
+}
+
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	sum = (sum >> 16) + (sum & 0xffff)
+	sum += sum >> 16
+	return ^uint16(sum)
+}
+
+// udpProber sends UDP datagrams to a closed port, and treats the resulting ICMP port unreachable
+// error (reported as connection refused on the connected socket), or any response, as a reply.
+type udpProber struct {
+	ip net.IP
+}
+
+func (p *udpProber) method() string {
+	return "udp"
+}
+
+func (p *udpProber) probe(ctx context.Context, seq int) (time.Duration, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", net.JoinHostPort(p.ip.String(), strconv.Itoa(udpProbePort+seq)))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetReadDeadline(probeDeadline(ctx))
+	start := time.Now()
+	if _, err := conn.Write([]byte("sundheit")); err != nil {
+		return 0, err
+	}
+	_, err = conn.Read(make([]byte, 64))
+	if err == nil || isConnRefused(err) {
+		return time.Since(start), nil
+	}
+	return 0, err
+}
+
+func (p *udpProber) close() {}
+
+func isConnRefused(err error) bool {
+	return strings.Contains(err.Error(), "connection refused")
+}
@@ -0,0 +1,97 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProber struct {
+	rtts []time.Duration
+}
+
+func (p *fakeProber) method() string {
+	return "fake"
+}
+
+func (p *fakeProber) probe(_ context.Context, seq int) (time.Duration, error) {
+	if p.rtts[seq] == 0 {
+		return 0, errors.New("i/o timeout")
+	}
+	return p.rtts[seq], nil
+}
+
+func (p *fakeProber) close() {}
+
+func TestNewICMPPingCheck_config(t *testing.T) {
+	_, err := NewICMPPingCheck("", 3, 0, 0)
+	assert.EqualError(t, err, "host must not be empty")
+
+	_, err = NewICMPPingCheck("localhost", 0, 0, 0)
+	assert.EqualError(t, err, "count must be positive")
+
+	_, err = NewICMPPingCheck("localhost", 3, 1.5, 0)
+	assert.EqualError(t, err, "maxLoss must be between 0 and 1")
+}
+
+func TestNewICMPPingCheck(t *testing.T) {
+	check, err := NewICMPPingCheck("127.0.0.1", 2, 0, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "ping.127.0.0.1", check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	ping := details.(ICMPPingDetails)
+	assert.Equal(t, "127.0.0.1", ping.Address)
+	assert.Contains(t, []string{"icmp", "udp"}, ping.Method)
+	assert.Equal(t, 2, ping.Sent)
+	assert.Equal(t, 2, ping.Received)
+	assert.Equal(t, float64(0), ping.Loss)
+}
+
+func TestNewICMPPingCheck_udpProber(t *testing.T) {
+	p := &udpProber{ip: net.IPv4(127, 0, 0, 1)}
+	rtt, err := p.probe(context.Background(), 0)
+	assert.NoError(t, err, "port unreachable is a reply")
+	assert.True(t, rtt > 0, "rtt")
+}
+
+func TestNewICMPPingCheck_thresholds(t *testing.T) {
+	newCheck := func(maxLoss float64, maxRTT time.Duration, rtts ...time.Duration) *icmpPingCheck {
+		check, err := NewICMPPingCheck("127.0.0.1", len(rtts), maxLoss, maxRTT)
+		require.NoError(t, err)
+		ping := check.(*icmpPingCheck)
+		ping.newProber = func(net.IP) (prober, error) { return &fakeProber{rtts: rtts}, nil }
+		return ping
+	}
+
+	details, err := newCheck(0.25, 20*time.Millisecond, 10*time.Millisecond, 0, 20*time.Millisecond, 30*time.Millisecond).Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ICMPPingDetails{
+		Address:  "127.0.0.1",
+		Method:   "fake",
+		Sent:     4,
+		Received: 3,
+		Loss:     0.25,
+		MinRTT:   10 * time.Millisecond,
+		AvgRTT:   20 * time.Millisecond,
+		MaxRTT:   30 * time.Millisecond,
+	}, details)
+
+	_, err = newCheck(0.25, 0, 10*time.Millisecond, 0, 0, 10*time.Millisecond).Execute(context.Background())
+	assert.EqualError(t, err, "packet loss of 50% exceeds the maximum of 25%")
+
+	_, err = newCheck(0, 15*time.Millisecond, 10*time.Millisecond, 30*time.Millisecond).Execute(context.Background())
+	assert.EqualError(t, err, "average round trip time of 20ms exceeds the maximum of 15ms")
+}
+
+func TestICMPChecksum(t *testing.T) {
+	// an echo request with id 1 and sequence 1, and an empty payload
+	request := []byte{8, 0, 0, 0, 0, 1, 0, 1}
+	assert.Equal(t, uint16(0xf7fd), icmpChecksum(request))
+}
@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// InodeThresholds are the free inodes thresholds of an inode usage check.
+// Zero values mean no threshold.
+type InodeThresholds struct {
+	// MinFree is the minimal number of free inodes.
+	MinFree uint64
+	// MinFreePercent is the minimal percentage (0-100) of free inodes out of the total inodes.
+	MinFreePercent float64
+}
+
+// InodeDetails are the details of an inode usage check
+type InodeDetails struct {
+	Path  string `json:"path"`
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"`
+	// FreePercent is the percentage of free inodes, which is 100 for file systems which allocate inodes dynamically
+	FreePercent float64 `json:"freePercent"`
+}
+
+type inodeCheck struct {
+	name       string
+	path       string
+	thresholds InodeThresholds
+}
+
+// NewInodeUsageCheck returns a Check that monitors the inode usage of the file system of the given path, and fails when
+// the free inodes fall below any of the given thresholds, or are exhausted. A file system may run out of inodes (e.g. due to
+// many small files) while it still has free space, and then fails to create files. The inode usage is reported as the check details.
+// File systems which allocate inodes dynamically (e.g. btrfs) report no inodes, and always pass. Inodes are only supported on Linux.
+func NewInodeUsageCheck(name, path string, thresholds InodeThresholds) (gosundheit.Check, error) {
+	if path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+	if thresholds.MinFreePercent < 0 || thresholds.MinFreePercent > 100 {
+		return nil, errors.New("MinFreePercent must be between 0 and 100")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &inodeCheck{name: name, path: path, thresholds: thresholds}, nil
+}
+
+func (c *inodeCheck) Name() string {
+	return c.name
+}
+
+func (c *inodeCheck) Execute(_ context.Context) (details interface{}, err error) {
+	total, free, err := inodeUsage(c.path)
+	if err != nil {
+		return nil, err
+	}
+	return c.evaluate(total, free)
+}
+
+func (c *inodeCheck) evaluate(total, free uint64) (InodeDetails, error) {
+	inodeDetails := InodeDetails{Path: c.path, Total: total, Free: free, FreePercent: 100}
+	if total == 0 {
+		return inodeDetails, nil
+	}
+	inodeDetails.FreePercent = float64(free) * 100 / float64(total)
+
+	min := c.thresholds
+	switch {
+	case free == 0:
+		return inodeDetails, errors.Errorf("the inodes of '%s' are exhausted", c.path)
+	case min.MinFree > 0 && free < min.MinFree:
+		return inodeDetails, errors.Errorf("%d free inodes of '%s' are below the minimum of %d", free, c.path, min.MinFree)
+	case min.MinFreePercent > 0 && inodeDetails.FreePercent < min.MinFreePercent:
+		return inodeDetails, errors.Errorf("%.2f%% free inodes of '%s' are below the minimum of %.2f%%", inodeDetails.FreePercent, c.path, min.MinFreePercent)
+	}
+	return inodeDetails, nil
+}
@@ -0,0 +1,16 @@
+package checks
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// inodeUsage returns the total and free inodes of the file system of the given path
+func inodeUsage(path string) (total, free uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, errors.Errorf("failed to stat file system of '%s': %v", path, err)
+	}
+	return uint64(stat.Files), uint64(stat.Ffree), nil
+}
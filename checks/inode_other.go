@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import "github.com/pkg/errors"
+
+func inodeUsage(_ string) (total, free uint64, err error) {
+	return 0, 0, errors.New("inodes are only supported on Linux")
+}
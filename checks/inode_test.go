@@ -0,0 +1,61 @@
+package checks
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInodeUsageCheck_config(t *testing.T) {
+	_, err := NewInodeUsageCheck(checkName, "", InodeThresholds{})
+	assert.EqualError(t, err, "path must not be empty")
+
+	_, err = NewInodeUsageCheck(checkName, "/data", InodeThresholds{MinFreePercent: 101})
+	assert.EqualError(t, err, "MinFreePercent must be between 0 and 100")
+
+	_, err = NewInodeUsageCheck("", "/data", InodeThresholds{})
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewInodeUsageCheck(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inodes are only supported on Linux")
+	}
+
+	check, err := NewInodeUsageCheck(checkName, t.TempDir(), InodeThresholds{})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	inodeDetails := details.(InodeDetails)
+	assert.True(t, inodeDetails.Free <= inodeDetails.Total, "free inodes")
+
+	check, err = NewInodeUsageCheck(checkName, "/does/not/exist", InodeThresholds{})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to stat file system of '/does/not/exist': no such file or directory")
+}
+
+func TestInodeCheck_evaluate(t *testing.T) {
+	check := &inodeCheck{name: checkName, path: "/data", thresholds: InodeThresholds{MinFree: 1000, MinFreePercent: 10}}
+
+	details, err := check.evaluate(100000, 50000)
+	assert.NoError(t, err)
+	assert.Equal(t, InodeDetails{Path: "/data", Total: 100000, Free: 50000, FreePercent: 50}, details)
+
+	_, err = check.evaluate(100000, 9000)
+	assert.EqualError(t, err, "9.00% free inodes of '/data' are below the minimum of 10.00%")
+
+	_, err = check.evaluate(5000, 999)
+	assert.EqualError(t, err, "999 free inodes of '/data' are below the minimum of 1000")
+
+	_, err = (&inodeCheck{path: "/data"}).evaluate(5000, 0)
+	assert.EqualError(t, err, "the inodes of '/data' are exhausted")
+
+	details, err = check.evaluate(0, 0)
+	assert.NoError(t, err, "dynamic inodes")
+	assert.Equal(t, float64(100), details.FreePercent)
+}
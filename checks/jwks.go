@@ -0,0 +1,304 @@
+package checks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers the SHA-256 hash functions used for verifying tokens
+	_ "crypto/sha512" // registers the SHA-384 and SHA-512 hash functions used for verifying tokens
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// JWKSCheckConfig configures a check for a JSON Web Key Set (JWKS) endpoint, e.g. of an OpenID Connect provider.
+type JWKSCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the required URL of the JWKS, e.g. "https://auth.example.com/.well-known/jwks.json"
+	URL string
+	// ExpectedKeyIDs are optional key IDs ("kid") which must be present in the key set.
+	ExpectedKeyIDs []string
+	// ExpectedAlgorithms are optional algorithms (e.g. "RS256"), each of which must be declared ("alg") by at least one key in the key set.
+	ExpectedAlgorithms []string
+	// CanaryToken is an optional JWT, which must be signed by a key in the key set (matched by its "kid"), and must not be expired.
+	// The supported algorithms are RS256/384/512, PS256/384/512, ES256/384/512 and EdDSA.
+	CanaryToken string
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+// JWKDetails are the details of a key in the key set
+type JWKDetails struct {
+	KeyID     string `json:"kid,omitempty"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+}
+
+// jwk is a JSON Web Key, as defined by RFC 7517, with the public key parameters of RFC 7518 and RFC 8037
+type jwk struct {
+	JWKDetails
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+type jwksCheck struct {
+	config JWKSCheckConfig
+}
+
+// NewJWKSCheck returns a Check that fetches a JSON Web Key Set, and fails unless it parses, contains the expected key IDs
+// and algorithms, and optionally verifies a canary token - detecting a broken key rotation before requests start failing.
+// The published keys are reported as the check details.
+func NewJWKSCheck(config JWKSCheckConfig) (gosundheit.Check, error) {
+	if config.URL == "" {
+		return nil, errors.New("URL must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &jwksCheck{config: config}, nil
+}
+
+func (c *jwksCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *jwksCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := fetchJSON(ctx, c.config.Client, c.config.URL, c.config.Options, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	keyDetails := make([]JWKDetails, len(jwks.Keys))
+	algorithms := make(map[string]bool)
+	for i, key := range jwks.Keys {
+		keyDetails[i] = key.JWKDetails
+		publicKey, err := key.publicKey()
+		if err != nil {
+			return keyDetails, errors.Errorf("invalid key '%s': %v", key.KeyID, err)
+		}
+		keys[key.KeyID] = publicKey
+		algorithms[key.Algorithm] = true
+	}
+	if len(keys) == 0 {
+		return keyDetails, errors.New("the key set is empty")
+	}
+
+	for _, kid := range c.config.ExpectedKeyIDs {
+		if _, ok := keys[kid]; !ok {
+			return keyDetails, errors.Errorf("key '%s' is missing", kid)
+		}
+	}
+	for _, alg := range c.config.ExpectedAlgorithms {
+		if !algorithms[alg] {
+			return keyDetails, errors.Errorf("no key for algorithm '%s'", alg)
+		}
+	}
+
+	if c.config.CanaryToken != "" {
+		if err := verifyJWT(c.config.CanaryToken, jwks.Keys, keys, time.Now()); err != nil {
+			return keyDetails, errors.Errorf("invalid canary token: %v", err)
+		}
+	}
+	return keyDetails, nil
+}
+
+// publicKey returns the public key of an RSA, EC or OKP (Ed25519) key, or nil for other key types, which are not used for verifying tokens
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, errors.Errorf("invalid modulus: %v", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve '%s'", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, errors.Errorf("invalid x coordinate: %v", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, errors.Errorf("invalid y coordinate: %v", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, errors.Errorf("unsupported curve '%s'", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key")
+		}
+		return ed25519.PublicKey(x), nil
+	case "":
+		return nil, errors.New("missing key type")
+	default:
+		return nil, nil
+	}
+}
+
+// verifyJWT verifies the signature of a JWS compact serialized token using the key matching its "kid", and that it is not expired
+func verifyJWT(token string, jwks []jwk, keys map[string]crypto.PublicKey, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed token")
+	}
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return errors.Errorf("malformed header: %v", err)
+	}
+	key, ok := keys[header.KeyID]
+	if !ok {
+		return errors.Errorf("key '%s' is missing", header.KeyID)
+	}
+	if key == nil {
+		return errors.Errorf("key '%s' is of an unsupported type", header.KeyID)
+	}
+	for _, k := range jwks {
+		if k.KeyID == header.KeyID && k.Algorithm != "" && k.Algorithm != header.Algorithm {
+			return errors.Errorf("key '%s' is for algorithm '%s', not '%s'", header.KeyID, k.Algorithm, header.Algorithm)
+		}
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.Errorf("malformed signature: %v", err)
+	}
+	if err := verifyJWS(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return err
+	}
+
+	var claims struct {
+		ExpiresAt float64 `json:"exp"`
+		NotBefore float64 `json:"nbf"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return errors.Errorf("malformed claims: %v", err)
+	}
+	if claims.ExpiresAt != 0 && now.After(time.Unix(int64(claims.ExpiresAt), 0)) {
+		return errors.Errorf("expired at %s", time.Unix(int64(claims.ExpiresAt), 0).UTC().Format(time.RFC3339))
+	}
+	if claims.NotBefore != 0 && now.Before(time.Unix(int64(claims.NotBefore), 0)) {
+		return errors.Errorf("not valid before %s", time.Unix(int64(claims.NotBefore), 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// verifyJWS verifies the signature of the signing input using the given algorithm, as defined by RFC 7518 and RFC 8037
+func verifyJWS(alg string, key crypto.PublicKey, signingInput, signature []byte) error {
+	if alg == "EdDSA" {
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(edKey, signingInput, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+
+	var hash crypto.Hash
+	switch {
+	case len(alg) == 5 && strings.HasSuffix(alg, "256"):
+		hash = crypto.SHA256
+	case len(alg) == 5 && strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+	case len(alg) == 5 && strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+	default:
+		return errors.Errorf("unsupported algorithm '%s'", alg)
+	}
+	digest := hash.New()
+	_, _ = digest.Write(signingInput)
+	hashed := digest.Sum(nil)
+
+	var valid bool
+	rsaKey, _ := key.(*rsa.PublicKey)
+	switch alg[:2] {
+	case "RS":
+		valid = rsaKey != nil && rsa.VerifyPKCS1v15(rsaKey, hash, hashed, signature) == nil
+	case "PS":
+		valid = rsaKey != nil && rsa.VerifyPSS(rsaKey, hash, hashed, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case "ES":
+		ecKey, _ := key.(*ecdsa.PublicKey)
+		if ecKey != nil && len(signature) == 2*((ecKey.Curve.Params().BitSize+7)/8) {
+			r := new(big.Int).SetBytes(signature[:len(signature)/2])
+			s := new(big.Int).SetBytes(signature[len(signature)/2:])
+			valid = ecdsa.Verify(ecKey, hashed, r, s)
+		}
+	default:
+		return errors.Errorf("unsupported algorithm '%s'", alg)
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
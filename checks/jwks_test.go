@@ -0,0 +1,144 @@
+package checks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signJWT returns a JWT with the given claims, signed using the given algorithm and key
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := b64(header) + "." + b64(payload)
+
+	var signature []byte
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signingInput))
+	case *ecdsa.PrivateKey:
+		hashed := crypto.SHA256.New()
+		_, _ = hashed.Write([]byte(signingInput))
+		r, s, err := ecdsa.Sign(rand.Reader, k, hashed.Sum(nil))
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case *rsa.PrivateKey:
+		hashed := crypto.SHA256.New()
+		_, _ = hashed.Write([]byte(signingInput))
+		opts := crypto.SignerOpts(crypto.SHA256)
+		if alg == "PS256" {
+			opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+		}
+		signature, err = k.Sign(rand.Reader, hashed.Sum(nil), opts)
+		require.NoError(t, err)
+	}
+	return signingInput + "." + b64(signature)
+}
+
+func TestNewJWKSCheck_config(t *testing.T) {
+	_, err := NewJWKSCheck(JWKSCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "URL must not be empty")
+
+	_, err = NewJWKSCheck(JWKSCheckConfig{URL: "http://localhost/jwks.json"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	check, err := NewJWKSCheck(JWKSCheckConfig{CheckName: checkName, URL: "http://localhost/jwks.json"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+}
+
+func TestNewJWKSCheck(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := []map[string]string{
+		{"kid": "rsa-1", "kty": "RSA", "alg": "RS256", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kid": "rsa-pss", "kty": "RSA", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kid": "ec-1", "kty": "EC", "alg": "ES256", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kid": "ed-1", "kty": "OKP", "alg": "EdDSA", "crv": "Ed25519", "x": b64(edPublicKey)},
+		{"kid": "hmac", "kty": "oct", "k": "c2VjcmV0"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	execute := func(config JWKSCheckConfig) (interface{}, error) {
+		config.CheckName = checkName
+		config.URL = server.URL
+		check, err := NewJWKSCheck(config)
+		require.NoError(t, err)
+		return check.Execute(context.Background())
+	}
+
+	details, err := execute(JWKSCheckConfig{ExpectedKeyIDs: []string{"rsa-1", "ec-1"}, ExpectedAlgorithms: []string{"RS256", "EdDSA"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []JWKDetails{
+		{KeyID: "rsa-1", KeyType: "RSA", Algorithm: "RS256", Use: "sig"},
+		{KeyID: "rsa-pss", KeyType: "RSA"},
+		{KeyID: "ec-1", KeyType: "EC", Algorithm: "ES256"},
+		{KeyID: "ed-1", KeyType: "OKP", Algorithm: "EdDSA"},
+		{KeyID: "hmac", KeyType: "oct"},
+	}, details)
+
+	_, err = execute(JWKSCheckConfig{ExpectedKeyIDs: []string{"rsa-2"}})
+	assert.EqualError(t, err, "key 'rsa-2' is missing")
+	_, err = execute(JWKSCheckConfig{ExpectedAlgorithms: []string{"ES384"}})
+	assert.EqualError(t, err, "no key for algorithm 'ES384'")
+
+	validClaims := map[string]interface{}{"sub": "canary", "exp": time.Now().Add(time.Hour).Unix()}
+	for _, token := range []string{
+		signJWT(t, "RS256", "rsa-1", rsaKey, validClaims),
+		signJWT(t, "PS256", "rsa-pss", rsaKey, validClaims),
+		signJWT(t, "ES256", "ec-1", ecKey, validClaims),
+		signJWT(t, "EdDSA", "ed-1", edKey, validClaims),
+	} {
+		_, err = execute(JWKSCheckConfig{CanaryToken: token})
+		assert.NoError(t, err)
+	}
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = execute(JWKSCheckConfig{CanaryToken: signJWT(t, "ES256", "ec-1", otherKey, validClaims)})
+	assert.EqualError(t, err, "invalid canary token: invalid signature")
+	_, err = execute(JWKSCheckConfig{CanaryToken: signJWT(t, "ES256", "ec-2", ecKey, validClaims)})
+	assert.EqualError(t, err, "invalid canary token: key 'ec-2' is missing")
+	_, err = execute(JWKSCheckConfig{CanaryToken: signJWT(t, "PS256", "rsa-1", rsaKey, validClaims)})
+	assert.EqualError(t, err, "invalid canary token: key 'rsa-1' is for algorithm 'RS256', not 'PS256'")
+	_, err = execute(JWKSCheckConfig{CanaryToken: signJWT(t, "RS256", "rsa-1", rsaKey, map[string]interface{}{"exp": 1600000000})})
+	assert.EqualError(t, err, "invalid canary token: expired at 2020-09-13T12:26:40Z")
+	_, err = execute(JWKSCheckConfig{CanaryToken: "not-a-token"})
+	assert.EqualError(t, err, "invalid canary token: malformed token")
+
+	keys = append(keys, map[string]string{"kid": "ec-2", "kty": "EC", "crv": "P-256", "x": b64([]byte{1}), "y": b64([]byte{2})})
+	_, err = execute(JWKSCheckConfig{})
+	assert.EqualError(t, err, "invalid key 'ec-2': the point is not on the curve")
+
+	keys = nil
+	_, err = execute(JWKSCheckConfig{})
+	assert.EqualError(t, err, "the key set is empty")
+}
@@ -0,0 +1,120 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// KafkaMetadata is the Kafka cluster metadata, as required by the Kafka check
+type KafkaMetadata struct {
+	// Brokers are the addresses of the available brokers
+	Brokers []string `json:"brokers"`
+	// Topics are the partitions of the requested topics, by topic name. Missing topics are absent.
+	Topics map[string][]KafkaPartitionMetadata `json:"topics,omitempty"`
+}
+
+// KafkaPartitionMetadata is the metadata of a topic partition
+type KafkaPartitionMetadata struct {
+	// ID is the partition ID
+	ID int32 `json:"id"`
+	// Leader is the ID of the leader broker, or -1 when there's no leader
+	Leader int32 `json:"leader"`
+	// Replicas are the IDs of the brokers replicating the partition
+	Replicas []int32 `json:"replicas"`
+	// ISR are the IDs of the in-sync replicas
+	ISR []int32 `json:"isr"`
+}
+
+// KafkaMetadataFetcher connects to the Kafka brokers and fetches the cluster metadata, including the given topics metadata.
+// It is the minimal API required by the Kafka check, so that any client library can be used.
+type KafkaMetadataFetcher interface {
+	FetchMetadata(ctx context.Context, topics ...string) (*KafkaMetadata, error)
+}
+
+// KafkaMetadataFunc type is an adapter to allow the use of ordinary functions as KafkaMetadataFetchers.
+type KafkaMetadataFunc func(ctx context.Context, topics ...string) (*KafkaMetadata, error)
+
+// FetchMetadata calls f(ctx, topics...).
+func (f KafkaMetadataFunc) FetchMetadata(ctx context.Context, topics ...string) (*KafkaMetadata, error) {
+	return f(ctx, topics...)
+}
+
+// KafkaCheckOption configures a Kafka check
+type KafkaCheckOption func(c *kafkaCheck)
+
+// WithKafkaTopic sets the Kafka check to verify that the given topic exists, and that each of its partitions
+// has a leader and at least minInSyncReplicas in-sync replicas.
+func WithKafkaTopic(topic string, minInSyncReplicas int) KafkaCheckOption {
+	return func(c *kafkaCheck) {
+		c.topic = topic
+		c.minInSyncReplicas = minInSyncReplicas
+	}
+}
+
+type kafkaCheck struct {
+	name              string
+	fetcher           KafkaMetadataFetcher
+	topic             string
+	minInSyncReplicas int
+}
+
+// NewKafkaCheck returns a Check that fetches the Kafka cluster metadata using the given fetcher, and fails when no broker is available.
+// It optionally verifies the health of a topic (see WithKafkaTopic). The fetched metadata is reported as the check details.
+func NewKafkaCheck(name string, fetcher KafkaMetadataFetcher, opts ...KafkaCheckOption) (gosundheit.Check, error) {
+	if fetcher == nil {
+		return nil, errors.New("metadata fetcher must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &kafkaCheck{name: name, fetcher: fetcher}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *kafkaCheck) Name() string {
+	return c.name
+}
+
+func (c *kafkaCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var topics []string
+	if c.topic != "" {
+		topics = append(topics, c.topic)
+	}
+
+	metadata, err := c.fetcher.FetchMetadata(ctx, topics...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch metadata")
+	}
+	if metadata == nil {
+		return nil, errors.New("no metadata returned")
+	}
+	if len(metadata.Brokers) == 0 {
+		return metadata, errors.New("no brokers are available")
+	}
+	if c.topic == "" {
+		return metadata, nil
+	}
+
+	partitions, ok := metadata.Topics[c.topic]
+	if !ok {
+		return metadata, errors.Errorf("topic '%s' does not exist", c.topic)
+	}
+	for _, partition := range partitions {
+		if partition.Leader < 0 {
+			return metadata, errors.Errorf("partition %d of topic '%s' has no leader", partition.ID, c.topic)
+		}
+		if len(partition.ISR) < c.minInSyncReplicas {
+			return metadata, errors.Errorf("partition %d of topic '%s' has %d in-sync replicas, but requires at least %d",
+				partition.ID, c.topic, len(partition.ISR), c.minInSyncReplicas)
+		}
+	}
+
+	return metadata, nil
+}
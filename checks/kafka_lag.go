@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// KafkaOffsetsFetcher fetches topic partition offsets. It is the minimal API required by the Kafka lag check,
+// so that any client library can be used.
+type KafkaOffsetsFetcher interface {
+	// CommittedOffsets returns the offsets committed by the given consumer group for the topic partitions, by partition ID.
+	// Partitions with no committed offset may be absent.
+	CommittedOffsets(ctx context.Context, group, topic string) (map[int32]int64, error)
+	// LatestOffsets returns the latest offsets (i.e. high watermarks) of the topic partitions, by partition ID.
+	LatestOffsets(ctx context.Context, topic string) (map[int32]int64, error)
+}
+
+// KafkaLagDetails are the details of a Kafka lag check
+type KafkaLagDetails struct {
+	// TotalLag is the sum of the partitions lag
+	TotalLag int64 `json:"totalLag"`
+	// Partitions is the lag of each partition, by partition ID
+	Partitions map[int32]int64 `json:"partitions"`
+}
+
+type kafkaLagCheck struct {
+	name    string
+	fetcher KafkaOffsetsFetcher
+	group   string
+	topic   string
+	maxLag  int64
+}
+
+// NewKafkaLagCheck returns a Check that compares the committed offsets of the given consumer group against the latest offsets
+// of the given topic, and fails when the total lag exceeds maxLag. The lag of each partition is reported as the check details.
+// Partitions without a committed offset are considered lagging by their latest offset.
+func NewKafkaLagCheck(name string, fetcher KafkaOffsetsFetcher, group, topic string, maxLag int64) (gosundheit.Check, error) {
+	if fetcher == nil {
+		return nil, errors.New("offsets fetcher must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+	if group == "" || topic == "" {
+		return nil, errors.New("group and topic must not be empty")
+	}
+
+	return &kafkaLagCheck{
+		name:    name,
+		fetcher: fetcher,
+		group:   group,
+		topic:   topic,
+		maxLag:  maxLag,
+	}, nil
+}
+
+func (c *kafkaLagCheck) Name() string {
+	return c.name
+}
+
+func (c *kafkaLagCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	latest, err := c.fetcher.LatestOffsets(ctx, c.topic)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch latest offsets")
+	}
+	committed, err := c.fetcher.CommittedOffsets(ctx, c.group, c.topic)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch committed offsets")
+	}
+
+	lagDetails := KafkaLagDetails{Partitions: make(map[int32]int64, len(latest))}
+	for partition, latestOffset := range latest {
+		lag := latestOffset - committed[partition]
+		if lag < 0 {
+			// the latest offsets were fetched first, so the committed offset may be ahead
+			lag = 0
+		}
+		lagDetails.Partitions[partition] = lag
+		lagDetails.TotalLag += lag
+	}
+
+	if lagDetails.TotalLag > c.maxLag {
+		return lagDetails, errors.Errorf("consumer group '%s' lag on topic '%s' is %d, exceeding the maximum of %d",
+			c.group, c.topic, lagDetails.TotalLag, c.maxLag)
+	}
+	return lagDetails, nil
+}
@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKafkaOffsets struct {
+	committed map[int32]int64
+	latest    map[int32]int64
+	err       error
+}
+
+func (f *fakeKafkaOffsets) CommittedOffsets(_ context.Context, _, _ string) (map[int32]int64, error) {
+	return f.committed, f.err
+}
+
+func (f *fakeKafkaOffsets) LatestOffsets(_ context.Context, _ string) (map[int32]int64, error) {
+	return f.latest, nil
+}
+
+func TestNewKafkaLagCheck_invalidArgs(t *testing.T) {
+	_, err := NewKafkaLagCheck(checkName, nil, "group", "topic", 10)
+	assert.EqualError(t, err, "offsets fetcher must not be nil")
+
+	_, err = NewKafkaLagCheck(checkName, &fakeKafkaOffsets{}, "", "topic", 10)
+	assert.EqualError(t, err, "group and topic must not be empty")
+}
+
+func TestNewKafkaLagCheck(t *testing.T) {
+	offsets := &fakeKafkaOffsets{
+		committed: map[int32]int64{0: 100, 1: 195},
+		latest:    map[int32]int64{0: 104, 1: 200, 2: 3},
+	}
+	check, err := NewKafkaLagCheck(checkName, offsets, "consumers", "events", 12)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, KafkaLagDetails{TotalLag: 12, Partitions: map[int32]int64{0: 4, 1: 5, 2: 3}}, details)
+
+	offsets.latest[0] = 105
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "consumer group 'consumers' lag on topic 'events' is 13, exceeding the maximum of 12")
+	assert.Equal(t, int64(5), details.(KafkaLagDetails).Partitions[0], "per partition lag is reported on failure")
+
+	offsets.err = errors.New("coordinator not available")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to fetch committed offsets: coordinator not available")
+}
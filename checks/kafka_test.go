@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeKafkaMetadata(metadata *KafkaMetadata, err error) KafkaMetadataFunc {
+	return func(_ context.Context, _ ...string) (*KafkaMetadata, error) {
+		return metadata, err
+	}
+}
+
+func TestNewKafkaCheck_nilFetcher(t *testing.T) {
+	check, err := NewKafkaCheck(checkName, nil)
+	assert.EqualError(t, err, "metadata fetcher must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewKafkaCheck(t *testing.T) {
+	metadata := &KafkaMetadata{Brokers: []string{"broker-1:9092"}}
+	check, err := NewKafkaCheck(checkName, fakeKafkaMetadata(metadata, nil))
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, details, "metadata is reported as details")
+
+	check, _ = NewKafkaCheck(checkName, fakeKafkaMetadata(&KafkaMetadata{}, nil))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "no brokers are available")
+
+	check, _ = NewKafkaCheck(checkName, fakeKafkaMetadata(nil, errors.New("dial tcp: connection refused")))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to fetch metadata: dial tcp: connection refused")
+
+	check, _ = NewKafkaCheck(checkName, fakeKafkaMetadata(nil, nil))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "no metadata returned")
+}
+
+func TestNewKafkaCheck_topic(t *testing.T) {
+	var requestedTopics []string
+	metadata := &KafkaMetadata{
+		Brokers: []string{"broker-1:9092", "broker-2:9092"},
+		Topics: map[string][]KafkaPartitionMetadata{
+			"events": {
+				{ID: 0, Leader: 1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+				{ID: 1, Leader: 2, Replicas: []int32{1, 2}, ISR: []int32{2}},
+			},
+			"orphans": {
+				{ID: 0, Leader: -1, Replicas: []int32{3}},
+			},
+		},
+	}
+	fetcher := KafkaMetadataFunc(func(_ context.Context, topics ...string) (*KafkaMetadata, error) {
+		requestedTopics = topics
+		return metadata, nil
+	})
+
+	check, _ := NewKafkaCheck(checkName, fetcher, WithKafkaTopic("events", 1))
+	_, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"events"}, requestedTopics, "only the checked topic is fetched")
+
+	check, _ = NewKafkaCheck(checkName, fetcher, WithKafkaTopic("events", 2))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "partition 1 of topic 'events' has 1 in-sync replicas, but requires at least 2")
+
+	check, _ = NewKafkaCheck(checkName, fetcher, WithKafkaTopic("orphans", 0))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "partition 0 of topic 'orphans' has no leader")
+
+	check, _ = NewKafkaCheck(checkName, fetcher, WithKafkaTopic("missing", 1))
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "topic 'missing' does not exist")
+}
@@ -0,0 +1,94 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// LeaseChecker returns the identity of the current holder of a named lock or lease, or an empty string when it is not held.
+// It can be implemented using e.g. a Kubernetes Lease, an etcd or Consul session, or a database advisory lock.
+type LeaseChecker interface {
+	LeaseHolder(ctx context.Context, lease string) (holder string, err error)
+}
+
+// LeaseCheckerFunc type is an adapter to allow the use of ordinary functions as LeaseCheckers.
+type LeaseCheckerFunc func(ctx context.Context, lease string) (holder string, err error)
+
+// LeaseHolder calls f(ctx, lease).
+func (f LeaseCheckerFunc) LeaseHolder(ctx context.Context, lease string) (holder string, err error) {
+	return f(ctx, lease)
+}
+
+// LeaseCheckOption configures a lease check
+type LeaseCheckOption func(c *leaseCheck)
+
+// WithLeaseHeld sets the lease check to fail unless this instance holds the lease, e.g. for the singleton worker itself.
+func WithLeaseHeld() LeaseCheckOption {
+	return func(c *leaseCheck) {
+		c.mustHold = true
+	}
+}
+
+// LeaseDetails are the details of a lease check
+type LeaseDetails struct {
+	Lease string `json:"lease"`
+	// Holder is the identity of the current holder of the lease, or empty when it is not held
+	Holder string `json:"holder,omitempty"`
+	// Held indicates whether this instance holds the lease
+	Held bool `json:"held"`
+}
+
+type leaseCheck struct {
+	name     string
+	checker  LeaseChecker
+	lease    string
+	identity string
+	mustHold bool
+}
+
+// NewLeaseCheck returns a Check that reports whether the instance with the given identity currently holds the named lock or lease,
+// along with the identity of its holder, as the check details. The check fails when the lease is not held by anyone,
+// and optionally when it is held by another instance (see WithLeaseHeld).
+func NewLeaseCheck(name string, checker LeaseChecker, lease, identity string, opts ...LeaseCheckOption) (gosundheit.Check, error) {
+	if checker == nil {
+		return nil, errors.New("LeaseChecker must not be nil")
+	}
+	if lease == "" {
+		return nil, errors.New("lease must not be empty")
+	}
+	if identity == "" {
+		return nil, errors.New("identity must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &leaseCheck{name: name, checker: checker, lease: lease, identity: identity}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *leaseCheck) Name() string {
+	return c.name
+}
+
+func (c *leaseCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	holder, err := c.checker.LeaseHolder(ctx, c.lease)
+	if err != nil {
+		return LeaseDetails{Lease: c.lease}, errors.Errorf("failed to get the holder of lease '%s': %v", c.lease, err)
+	}
+
+	leaseDetails := LeaseDetails{Lease: c.lease, Holder: holder, Held: holder == c.identity}
+	switch {
+	case holder == "":
+		return leaseDetails, errors.Errorf("lease '%s' is not held", c.lease)
+	case c.mustHold && !leaseDetails.Held:
+		return leaseDetails, errors.Errorf("lease '%s' is held by '%s'", c.lease, holder)
+	}
+	return leaseDetails, nil
+}
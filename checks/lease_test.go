@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLeaseCheck_config(t *testing.T) {
+	checker := LeaseCheckerFunc(func(ctx context.Context, lease string) (string, error) { return "", nil })
+
+	_, err := NewLeaseCheck(checkName, nil, "scheduler", "pod-1")
+	assert.EqualError(t, err, "LeaseChecker must not be nil")
+	_, err = NewLeaseCheck(checkName, checker, "", "pod-1")
+	assert.EqualError(t, err, "lease must not be empty")
+	_, err = NewLeaseCheck(checkName, checker, "scheduler", "")
+	assert.EqualError(t, err, "identity must not be empty")
+	_, err = NewLeaseCheck("", checker, "scheduler", "pod-1")
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewLeaseCheck(t *testing.T) {
+	var holder string
+	var lookupErr error
+	checker := LeaseCheckerFunc(func(ctx context.Context, lease string) (string, error) {
+		assert.Equal(t, "scheduler", lease)
+		return holder, lookupErr
+	})
+
+	check, err := NewLeaseCheck(checkName, checker, "scheduler", "pod-1")
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	mustHoldCheck, err := NewLeaseCheck(checkName, checker, "scheduler", "pod-1", WithLeaseHeld())
+	require.NoError(t, err)
+
+	holder = "pod-1"
+	for _, c := range []*leaseCheck{check.(*leaseCheck), mustHoldCheck.(*leaseCheck)} {
+		details, err := c.Execute(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, LeaseDetails{Lease: "scheduler", Holder: "pod-1", Held: true}, details)
+	}
+
+	holder = "pod-2"
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err, "another instance holds the lease")
+	assert.Equal(t, LeaseDetails{Lease: "scheduler", Holder: "pod-2", Held: false}, details)
+	details, err = mustHoldCheck.Execute(context.Background())
+	assert.EqualError(t, err, "lease 'scheduler' is held by 'pod-2'")
+	assert.Equal(t, LeaseDetails{Lease: "scheduler", Holder: "pod-2", Held: false}, details)
+
+	holder = ""
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "lease 'scheduler' is not held")
+
+	lookupErr = errors.New("connection refused")
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to get the holder of lease 'scheduler': connection refused")
+	assert.Equal(t, LeaseDetails{Lease: "scheduler"}, details)
+}
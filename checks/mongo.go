@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// MongoClient is the minimal MongoDB client API required by the MongoDB check, so that the check does not depend
+// on a specific driver version. With the official driver, Ping is implemented by converting the read preference mode
+// using readpref.ModeFromString(), and RunCommand by decoding the result of client.Database(database).RunCommand().
+type MongoClient interface {
+	// Ping pings a server selected using the given read preference mode (e.g. "primary" or "nearest").
+	Ping(ctx context.Context, readPreference string) error
+	// RunCommand runs the given command against the given database, and returns the command result document.
+	RunCommand(ctx context.Context, database string, command map[string]interface{}) (map[string]interface{}, error)
+}
+
+// MongoCheckOption configures a MongoDB check
+type MongoCheckOption func(c *mongoCheck)
+
+// WithMongoReadPreference sets the read preference mode used for selecting the pinged server. Defaults to "primary".
+func WithMongoReadPreference(mode string) MongoCheckOption {
+	return func(c *mongoCheck) {
+		c.readPreference = mode
+	}
+}
+
+// WithMongoCommand sets a command to be executed against the given database after a successful ping,
+// e.g. map[string]interface{}{"serverStatus": 1}. The command result is reported as the check details,
+// reduced to the given fields if any are specified.
+func WithMongoCommand(database string, command map[string]interface{}, fields ...string) MongoCheckOption {
+	return func(c *mongoCheck) {
+		c.database = database
+		c.command = command
+		c.fields = fields
+	}
+}
+
+// WithMongoTimeout sets a timeout for the MongoDB check operations, in addition to the check context deadline if any.
+func WithMongoTimeout(timeout time.Duration) MongoCheckOption {
+	return func(c *mongoCheck) {
+		c.timeout = timeout
+	}
+}
+
+type mongoCheck struct {
+	name           string
+	client         MongoClient
+	readPreference string
+	database       string
+	command        map[string]interface{}
+	fields         []string
+	timeout        time.Duration
+}
+
+// NewMongoCheck returns a Check that pings MongoDB using the given client, and optionally runs a lightweight command (see WithMongoCommand).
+func NewMongoCheck(name string, client MongoClient, opts ...MongoCheckOption) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &mongoCheck{name: name, client: client, readPreference: "primary"}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *mongoCheck) Name() string {
+	return c.name
+}
+
+func (c *mongoCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	if err := c.client.Ping(ctx, c.readPreference); err != nil {
+		return nil, errors.Wrap(err, "ping failed")
+	}
+	if c.command == nil {
+		return nil, nil
+	}
+
+	result, err := c.client.RunCommand(ctx, c.database, c.command)
+	if err != nil {
+		return nil, errors.Wrap(err, "command failed")
+	}
+	if len(c.fields) == 0 {
+		return result, nil
+	}
+
+	subset := make(map[string]interface{}, len(c.fields))
+	for _, field := range c.fields {
+		if value, ok := result[field]; ok {
+			subset[field] = value
+		}
+	}
+	return subset, nil
+}
@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMongo struct {
+	pingErr        error
+	readPreference string
+	database       string
+	command        map[string]interface{}
+	deadline       time.Time
+}
+
+func (m *fakeMongo) Ping(ctx context.Context, readPreference string) error {
+	m.readPreference = readPreference
+	m.deadline, _ = ctx.Deadline()
+	return m.pingErr
+}
+
+func (m *fakeMongo) RunCommand(_ context.Context, database string, command map[string]interface{}) (map[string]interface{}, error) {
+	m.database = database
+	m.command = command
+	return map[string]interface{}{"ok": 1.0, "uptime": 3600.0, "version": "4.4.1"}, nil
+}
+
+func TestNewMongoCheck_nilClient(t *testing.T) {
+	check, err := NewMongoCheck(checkName, nil)
+	assert.EqualError(t, err, "client must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewMongoCheck(t *testing.T) {
+	mongo := &fakeMongo{}
+	check, err := NewMongoCheck(checkName, mongo)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+	assert.Equal(t, "primary", mongo.readPreference, "default read preference")
+	assert.True(t, mongo.deadline.IsZero(), "no timeout")
+	assert.Nil(t, mongo.command, "no command")
+
+	mongo.pingErr = errors.New("server selection timeout")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "ping failed: server selection timeout")
+}
+
+func TestNewMongoCheck_options(t *testing.T) {
+	mongo := &fakeMongo{}
+	serverStatus := map[string]interface{}{"serverStatus": 1}
+	check, err := NewMongoCheck(checkName, mongo,
+		WithMongoReadPreference("nearest"),
+		WithMongoCommand("admin", serverStatus, "uptime", "version"),
+		WithMongoTimeout(time.Second),
+	)
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"uptime": 3600.0, "version": "4.4.1"}, details, "result subset")
+	assert.Equal(t, "nearest", mongo.readPreference)
+	assert.Equal(t, "admin", mongo.database)
+	assert.Equal(t, serverStatus, mongo.command)
+	assert.WithinDuration(t, time.Now().Add(time.Second), mongo.deadline, time.Second, "timeout")
+}
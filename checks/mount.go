@@ -0,0 +1,144 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// MountPointCheckOption configures a mount point check
+type MountPointCheckOption func(c *mountPointCheck)
+
+// WithWriteProbe sets the mount point check to also write a temporary file to the mount point (see NewDiskWriteCheck),
+// which detects full disks and failing devices in addition to read-only remounts.
+func WithWriteProbe() MountPointCheckOption {
+	return func(c *mountPointCheck) {
+		c.writeProbe = true
+	}
+}
+
+// MountPointDetails are the details of a mounted file system
+type MountPointDetails struct {
+	Path     string `json:"path"`
+	Source   string `json:"source"`
+	FSType   string `json:"fsType"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+type mountPointCheck struct {
+	name       string
+	path       string
+	writeProbe bool
+	// mountInfo is the path of the mountinfo file listing the mounted file systems
+	mountInfo string
+}
+
+// NewMountPointCheck returns a Check that verifies a file system is mounted at the given path, and is not read-only
+// according to its statfs flags. Optionally, it also writes to the mount point (see WithWriteProbe).
+// This detects containers which lost their volumes, or got them remounted read-only. The mounted file system is reported as the check details.
+// Mount points are only supported on Linux.
+func NewMountPointCheck(name, path string, opts ...MountPointCheckOption) (gosundheit.Check, error) {
+	if path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &mountPointCheck{name: name, path: filepath.Clean(path), mountInfo: "/proc/self/mountinfo"}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *mountPointCheck) Name() string {
+	return c.name
+}
+
+func (c *mountPointCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	path, err := filepath.EvalSymlinks(c.path)
+	if err != nil {
+		return nil, errors.Errorf("mount point '%s' does not exist: %v", c.path, err)
+	}
+	mountDetails, err := readMountPoint(c.mountInfo, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if mountDetails.ReadOnly, err = isReadOnlyFS(path); err != nil {
+		return mountDetails, err
+	}
+	if mountDetails.ReadOnly {
+		return mountDetails, errors.Errorf("'%s' is mounted read-only", c.path)
+	}
+
+	if c.writeProbe {
+		if _, err := (&diskWriteCheck{name: c.name, dir: path}).Execute(ctx); err != nil {
+			return mountDetails, err
+		}
+	}
+	return mountDetails, nil
+}
+
+// readMountPoint returns the file system mounted at the given path, according to the given mountinfo file,
+// whose format is described in proc(5). When file systems are stacked on the same mount point, the last one is returned.
+func readMountPoint(mountInfo, path string) (*MountPointDetails, error) {
+	file, err := os.Open(mountInfo)
+	if err != nil {
+		return nil, errors.Errorf("failed to read mount points: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var mountDetails *MountPointDetails
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		separator := 6
+		for separator < len(fields) && fields[separator] != "-" {
+			separator++
+		}
+		if separator+2 >= len(fields) || unescapeMountInfo(fields[4]) != path {
+			continue
+		}
+		mountDetails = &MountPointDetails{
+			Path:   path,
+			FSType: fields[separator+1],
+			Source: unescapeMountInfo(fields[separator+2]),
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Errorf("failed to read mount points: %v", err)
+	}
+	if mountDetails == nil {
+		return nil, errors.Errorf("'%s' is not a mount point", path)
+	}
+	return mountDetails, nil
+}
+
+// unescapeMountInfo replaces the octal escapes of spaces, tabs, newlines and backslashes in a mountinfo field
+func unescapeMountInfo(field string) string {
+	if !strings.Contains(field, "\\") {
+		return field
+	}
+	var unescaped strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if char, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				unescaped.WriteByte(byte(char))
+				i += 3
+				continue
+			}
+		}
+		unescaped.WriteByte(field[i])
+	}
+	return unescaped.String()
+}
@@ -0,0 +1,19 @@
+package checks
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// stReadOnly is the ST_RDONLY statfs flag
+const stReadOnly = 0x1
+
+// isReadOnlyFS returns whether the file system of the given path is mounted read-only
+func isReadOnlyFS(path string) (bool, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false, errors.Errorf("failed to stat file system of '%s': %v", path, err)
+	}
+	return stat.Flags&stReadOnly != 0, nil
+}
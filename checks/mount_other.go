@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import "github.com/pkg/errors"
+
+func isReadOnlyFS(_ string) (bool, error) {
+	return false, errors.New("mount points are only supported on Linux")
+}
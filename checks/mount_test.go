@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMountPointCheck_config(t *testing.T) {
+	_, err := NewMountPointCheck(checkName, "")
+	assert.EqualError(t, err, "path must not be empty")
+
+	_, err = NewMountPointCheck("", "/data")
+	assert.EqualError(t, err, "check name must not be empty")
+
+	check, err := NewMountPointCheck(checkName, "/data/", WithWriteProbe())
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	assert.Equal(t, "/data", check.(*mountPointCheck).path, "cleaned path")
+	assert.True(t, check.(*mountPointCheck).writeProbe, "write probe")
+}
+
+func TestNewMountPointCheck(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("mount points are only supported on Linux")
+	}
+
+	check, err := NewMountPointCheck(checkName, "/")
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	if err != nil && strings.Contains(err.Error(), "read-only") {
+		t.Skip("the root file system is mounted read-only")
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, "/", details.(*MountPointDetails).Path)
+	assert.NotEmpty(t, details.(*MountPointDetails).FSType)
+
+	check, err = NewMountPointCheck(checkName, t.TempDir())
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a mount point")
+
+	check, err = NewMountPointCheck(checkName, "/there/is/no/such/dir")
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mount point '/there/is/no/such/dir' does not exist")
+}
+
+func TestNewMountPointCheck_writeProbe(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("mount points are only supported on Linux")
+	}
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	mountPoint := filepath.Join(dir, "data volume")
+	require.NoError(t, os.Mkdir(mountPoint, 0700))
+	mountInfo := filepath.Join(dir, "mountinfo")
+	require.NoError(t, ioutil.WriteFile(mountInfo, []byte(fmt.Sprintf(
+		"22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"+
+			"97 22 0:52 / %s rw,nosuid,nodev - tmpfs tmp\\040fs rw,size=1024k\n",
+		strings.Replace(mountPoint, " ", "\\040", -1))), 0600))
+
+	check, err := NewMountPointCheck(checkName, mountPoint, WithWriteProbe())
+	require.NoError(t, err)
+	check.(*mountPointCheck).mountInfo = mountInfo
+
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &MountPointDetails{Path: mountPoint, Source: "tmp fs", FSType: "tmpfs"}, details)
+
+	require.NoError(t, os.Chmod(mountPoint, 0500))
+	defer func() { _ = os.Chmod(mountPoint, 0700) }()
+	if os.Geteuid() != 0 {
+		_, err = check.Execute(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create file")
+	}
+}
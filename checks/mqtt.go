@@ -0,0 +1,302 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// MQTT 3.1.1 control packet types
+const (
+	mqttConnect    = 1
+	mqttConnAck    = 2
+	mqttPublish    = 3
+	mqttSubscribe  = 8
+	mqttSubAck     = 9
+	mqttDisconnect = 14
+
+	// mqttMaxPacketSize limits the size of the packets read by the check
+	mqttMaxPacketSize = 1 << 20
+	// mqttClientIDPrefix is the prefix of the default client identifiers, which are followed by 12 random hex digits,
+	// keeping them within the 23 bytes servers are allowed to limit client identifiers to
+	mqttClientIDPrefix = "gosundheit-"
+)
+
+// mqttConnectErrors are the messages of the CONNACK return codes
+var mqttConnectErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// MQTTCheckConfig configures a check for an MQTT broker.
+type MQTTCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is the required host:port address of the broker.
+	Address string
+	// ClientID is the client identifier of the check, defaults to a random identifier per connection (e.g. "gosundheit-3f2a9c1e04b7"),
+	// so that the checks of multiple replicas do not take over each other's sessions.
+	// A fixed identifier must be unique per instance, since the broker disconnects an existing connection with the same identifier.
+	ClientID string
+	// Username and Password are optional credentials.
+	Username string
+	Password string
+	// TLSConfig is optional; if defined, the broker is connected using TLS.
+	TLSConfig *tls.Config
+	// HealthTopic is optional; if defined, the check subscribes to the topic, publishes a message to it, and awaits the message.
+	HealthTopic string
+	// Timeout is the timeout of the whole MQTT conversation, defaults to "1s".
+	Timeout time.Duration
+}
+
+// MQTTDetails are the details of an MQTT check
+type MQTTDetails struct {
+	// ConnectLatency is the duration until the connection was acknowledged by the broker
+	ConnectLatency time.Duration `json:"connectLatency"`
+	// RoundTripLatency is the duration of the publish/subscribe round trip, or zero when there's no HealthTopic
+	RoundTripLatency time.Duration `json:"roundTripLatency,omitempty"`
+}
+
+type mqttCheck struct {
+	config MQTTCheckConfig
+}
+
+// NewMQTTCheck returns a Check that connects to an MQTT broker using the MQTT 3.1.1 protocol, and optionally performs
+// a publish/subscribe round trip on a health topic. The broker latencies are reported as the check details.
+func NewMQTTCheck(config MQTTCheckConfig) (gosundheit.Check, error) {
+	if config.Address == "" {
+		return nil, errors.New("Address must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &mqttCheck{config: config}, nil
+}
+
+func (c *mqttCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *mqttCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var mqttDetails MQTTDetails
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := c.connect(ctx)
+	mqttDetails.ConnectLatency = time.Since(start)
+	if err != nil {
+		return mqttDetails, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if c.config.HealthTopic != "" {
+		start = time.Now()
+		err = c.roundTrip(conn)
+		mqttDetails.RoundTripLatency = time.Since(start)
+		if err != nil {
+			return mqttDetails, err
+		}
+	}
+
+	if err := mqttWritePacket(conn, mqttDisconnect<<4, nil); err != nil {
+		return mqttDetails, errors.Errorf("failed to disconnect: %v", err)
+	}
+	return mqttDetails, nil
+}
+
+// mqttRandomClientID returns a random client identifier of 23 bytes
+func mqttRandomClientID() string {
+	nonce := make([]byte, 6)
+	_, _ = rand.Read(nonce)
+	return mqttClientIDPrefix + hex.EncodeToString(nonce)
+}
+
+// connect dials the broker, and sends a CONNECT packet with a clean session, awaiting its acknowledgement
+func (c *mqttCheck) connect(ctx context.Context) (*mqttConn, error) {
+	var dialer interface {
+		DialContext(ctx context.Context, network, address string) (net.Conn, error)
+	} = &net.Dialer{}
+	if c.config.TLSConfig != nil {
+		dialer = &tls.Dialer{Config: c.config.TLSConfig}
+	}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.config.Address)
+	if err != nil {
+		return nil, errors.Errorf("failed to connect to '%s': %v", c.config.Address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = netConn.SetDeadline(deadline)
+	}
+	conn := &mqttConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	var packet bytes.Buffer
+	mqttWriteString(&packet, "MQTT")
+	flags := byte(0x02) // clean session
+	if c.config.Username != "" {
+		flags |= 0x80
+	}
+	if c.config.Password != "" {
+		flags |= 0x40
+	}
+	packet.Write([]byte{4, flags, 0, 0}) // protocol level 4, no keep alive
+	clientID := c.config.ClientID
+	if clientID == "" {
+		clientID = mqttRandomClientID()
+	}
+	mqttWriteString(&packet, clientID)
+	if c.config.Username != "" {
+		mqttWriteString(&packet, c.config.Username)
+	}
+	if c.config.Password != "" {
+		mqttWriteString(&packet, c.config.Password)
+	}
+	if err := mqttWritePacket(conn, mqttConnect<<4, packet.Bytes()); err != nil {
+		_ = conn.Close()
+		return nil, errors.Errorf("failed to send CONNECT: %v", err)
+	}
+
+	packetType, payload, err := conn.readPacket()
+	if err == nil && (packetType != mqttConnAck || len(payload) != 2) {
+		err = errors.Errorf("unexpected packet type %d", packetType)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Errorf("failed to receive CONNACK: %v", err)
+	}
+	if code := payload[1]; code != 0 {
+		_ = conn.Close()
+		message, ok := mqttConnectErrors[code]
+		if !ok {
+			message = "return code " + strconv.Itoa(int(code))
+		}
+		return nil, errors.Errorf("connection refused: %s", message)
+	}
+	return conn, nil
+}
+
+// roundTrip subscribes to the health topic, publishes a message to it, and awaits the message
+func (c *mqttCheck) roundTrip(conn *mqttConn) error {
+	var subscribe bytes.Buffer
+	subscribe.Write([]byte{0, 1}) // packet identifier
+	mqttWriteString(&subscribe, c.config.HealthTopic)
+	subscribe.WriteByte(0) // QoS 0
+	if err := mqttWritePacket(conn, mqttSubscribe<<4|0x2, subscribe.Bytes()); err != nil {
+		return errors.Errorf("failed to send SUBSCRIBE: %v", err)
+	}
+	packetType, payload, err := conn.readPacket()
+	if err == nil && (packetType != mqttSubAck || len(payload) != 3) {
+		err = errors.Errorf("unexpected packet type %d", packetType)
+	}
+	if err != nil {
+		return errors.Errorf("failed to receive SUBACK: %v", err)
+	}
+	if payload[2] == 0x80 {
+		return errors.Errorf("subscription to '%s' was rejected", c.config.HealthTopic)
+	}
+
+	message := []byte(c.config.ClientID + " " + strconv.FormatInt(time.Now().UnixNano(), 10))
+	var publish bytes.Buffer
+	mqttWriteString(&publish, c.config.HealthTopic)
+	publish.Write(message)
+	if err := mqttWritePacket(conn, mqttPublish<<4, publish.Bytes()); err != nil {
+		return errors.Errorf("failed to send PUBLISH: %v", err)
+	}
+
+	// other messages may be retained or published to the topic concurrently
+	for {
+		packetType, payload, err := conn.readPacket()
+		if err != nil {
+			return errors.Errorf("failed to receive the published message: %v", err)
+		}
+		if packetType == mqttPublish && bytes.HasSuffix(payload, message) {
+			return nil
+		}
+	}
+}
+
+type mqttConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// readPacket reads an MQTT control packet, returning its type and the rest of the packet following the fixed header
+func (c *mqttConn) readPacket() (packetType byte, payload []byte, err error) {
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, err := mqttReadVarInt(c.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length > mqttMaxPacketSize {
+		return 0, nil, errors.Errorf("packet of %d bytes exceeds the maximum of %d", length, mqttMaxPacketSize)
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, payload, nil
+}
+
+// mqttWritePacket writes an MQTT control packet with the given fixed header byte, and the rest of the packet
+func mqttWritePacket(w io.Writer, header byte, payload []byte) error {
+	packet := []byte{header}
+	length := len(payload)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	_, err := w.Write(append(packet, payload...))
+	return err
+}
+
+// mqttReadVarInt reads a remaining length, encoded as a variable byte integer
+func mqttReadVarInt(r io.ByteReader) (int, error) {
+	value, multiplier := 0, 1
+	for i := 0; i < 4; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			return value, nil
+		}
+		multiplier *= 128
+	}
+	return 0, errors.New("malformed remaining length")
+}
+
+// mqttWriteString writes a length prefixed UTF-8 string
+func mqttWriteString(buf *bytes.Buffer, s string) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(s)))
+	buf.WriteString(s)
+}
@@ -0,0 +1,147 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startMQTTBroker starts a broker that acknowledges connections with the given return code, and subscriptions to any topic,
+// echoing published messages to the subscriber. It returns the broker address, and a channel of the received CONNECT packets.
+func startMQTTBroker(t *testing.T, returnCode byte) (string, <-chan []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	connects := make(chan []byte, 10)
+	go func() {
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = netConn.Close() }()
+				conn := &mqttConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+				for {
+					packetType, payload, err := conn.readPacket()
+					if err != nil {
+						return
+					}
+					switch packetType {
+					case mqttConnect:
+						connects <- payload
+						_ = mqttWritePacket(conn, mqttConnAck<<4, []byte{0, returnCode})
+					case mqttSubscribe:
+						_ = mqttWritePacket(conn, mqttSubAck<<4, []byte{payload[0], payload[1], 0})
+						// a retained message, which is not the health message
+						var retained bytes.Buffer
+						mqttWriteString(&retained, "health")
+						retained.WriteString("retained")
+						_ = mqttWritePacket(conn, mqttPublish<<4|0x1, retained.Bytes())
+					case mqttPublish:
+						_ = mqttWritePacket(conn, mqttPublish<<4, payload)
+					case mqttDisconnect:
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), connects
+}
+
+func TestNewMQTTCheck_config(t *testing.T) {
+	_, err := NewMQTTCheck(MQTTCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "Address must not be empty")
+
+	_, err = NewMQTTCheck(MQTTCheckConfig{Address: "localhost:1883"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	check, err := NewMQTTCheck(MQTTCheckConfig{CheckName: checkName, Address: "localhost:1883"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+}
+
+func TestNewMQTTCheck(t *testing.T) {
+	address, connects := startMQTTBroker(t, 0)
+
+	check, err := NewMQTTCheck(MQTTCheckConfig{CheckName: checkName, Address: address, ClientID: "fixed-id", Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, details.(MQTTDetails).ConnectLatency > 0, "connect latency")
+	assert.Zero(t, details.(MQTTDetails).RoundTripLatency, "no round trip")
+
+	var expected bytes.Buffer
+	mqttWriteString(&expected, "MQTT")
+	expected.Write([]byte{4, 0xc2, 0, 0})
+	for _, s := range []string{"fixed-id", "user", "secret"} {
+		mqttWriteString(&expected, s)
+	}
+	assert.Equal(t, expected.Bytes(), <-connects, "CONNECT packet")
+
+	check, err = NewMQTTCheck(MQTTCheckConfig{CheckName: checkName, Address: address, HealthTopic: "health"})
+	require.NoError(t, err)
+	details, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, details.(MQTTDetails).RoundTripLatency > 0, "round trip latency")
+}
+
+func TestNewMQTTCheck_defaultClientID(t *testing.T) {
+	address, connects := startMQTTBroker(t, 0)
+
+	check, err := NewMQTTCheck(MQTTCheckConfig{CheckName: "a.long.check.name.that.exceeds.the.limit", Address: address})
+	require.NoError(t, err)
+	clientIDs := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		_, err = check.Execute(context.Background())
+		require.NoError(t, err)
+
+		connect := <-connects
+		// the client ID follows the protocol name (2+4 bytes) and the protocol level, flags and keep alive (4 bytes)
+		length := int(connect[10])<<8 | int(connect[11])
+		clientID := string(connect[12 : 12+length])
+		assert.Len(t, clientID, 23, "the client ID fits the 23 bytes limit")
+		assert.Regexp(t, "^gosundheit-[0-9a-f]{12}$", clientID)
+		clientIDs[clientID] = true
+	}
+	assert.Len(t, clientIDs, 2, "each connection has a unique client ID")
+}
+
+func TestNewMQTTCheck_refused(t *testing.T) {
+	address, _ := startMQTTBroker(t, 4)
+
+	check, err := NewMQTTCheck(MQTTCheckConfig{CheckName: checkName, Address: address})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "connection refused: bad user name or password")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+	check, err = NewMQTTCheck(MQTTCheckConfig{CheckName: checkName, Address: listener.Addr().String()})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to")
+}
+
+func TestMQTTRemainingLength(t *testing.T) {
+	for _, length := range []int{0, 127, 128, 16383, 16384, 2097151, 2097152} {
+		var buf bytes.Buffer
+		require.NoError(t, mqttWritePacket(&buf, 0, make([]byte, length)))
+		_, _ = buf.ReadByte()
+		decoded, err := mqttReadVarInt(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, length, decoded)
+		assert.Equal(t, length, buf.Len())
+	}
+	_, err := mqttReadVarInt(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}))
+	assert.EqualError(t, err, "malformed remaining length")
+}
@@ -0,0 +1,171 @@
+package checks
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// mutualTLSAlertWait is how long the check waits after the handshake for the server to reject the client certificate.
+// With TLS 1.3 the server verifies the client certificate after the client considers the handshake complete,
+// so a rejection only surfaces as an alert on the first read.
+const mutualTLSAlertWait = 100 * time.Millisecond
+
+// MutualTLSCheckConfig configures a check for a mutual TLS handshake with a server.
+type MutualTLSCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is the required host:port address of the server
+	Address string
+	// Certificate is the required client certificate presented to the server
+	Certificate tls.Certificate
+	// RootCAs are the certificate authorities used to verify the server, defaults to the system roots.
+	RootCAs *x509.CertPool
+	// ServerName is the name used to verify the server certificate, defaults to the host of Address.
+	ServerName string
+	// ExpectedSANs are optional names (DNS names, IP addresses, email addresses or URIs) which must all be present
+	// in the subject alternative names of the server certificate.
+	ExpectedSANs []string
+	// SPKIPins are optional base64 encoded SHA-256 hashes of a subject public key info,
+	// at least one of which must match a certificate of the verified server chain.
+	SPKIPins []string
+	// Timeout is the timeout used for the connection and TLS handshake, defaults to "1s".
+	Timeout time.Duration
+}
+
+// MutualTLSDetails are the details of a successful mutual TLS handshake
+type MutualTLSDetails struct {
+	CipherSuite string   `json:"cipherSuite"`
+	Subject     string   `json:"subject"`
+	SANs        []string `json:"sans,omitempty"`
+	// SPKIPin is the base64 encoded SHA-256 hash of the server certificate subject public key info
+	SPKIPin string `json:"spkiPin"`
+}
+
+type mutualTLSCheck struct {
+	config    MutualTLSCheckConfig
+	tlsConfig *tls.Config
+}
+
+// NewMutualTLSCheck returns a Check that performs a full TLS handshake with a server using a client certificate,
+// and fails unless the server accepts the client certificate and presents a certificate that is verified by the RootCAs,
+// carries the expected subject alternative names, and matches one of the SPKI pins (when configured).
+// The negotiated cipher suite and the server certificate identity are reported as the check details.
+func NewMutualTLSCheck(config MutualTLSCheckConfig) (gosundheit.Check, error) {
+	if config.Address == "" {
+		return nil, errors.New("Address must not be empty")
+	}
+	if len(config.Certificate.Certificate) == 0 {
+		return nil, errors.New("Certificate must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &mutualTLSCheck{
+		config: config,
+		tlsConfig: &tls.Config{
+			Certificates: []tls.Certificate{config.Certificate},
+			RootCAs:      config.RootCAs,
+			ServerName:   config.ServerName,
+		},
+	}, nil
+}
+
+func (c *mutualTLSCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *mutualTLSCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	conn, err := dialTLS(ctx, c.config.Address, c.tlsConfig, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := awaitClientCertAcceptance(conn); err != nil {
+		return nil, errors.Errorf("server rejected the client certificate: %v", err)
+	}
+
+	state := conn.ConnectionState()
+	leaf := state.PeerCertificates[0]
+	mtlsDetails := MutualTLSDetails{
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		Subject:     leaf.Subject.String(),
+		SANs:        certificateSANs(leaf),
+		SPKIPin:     spkiPin(leaf),
+	}
+
+	for _, expected := range c.config.ExpectedSANs {
+		if !containsString(mtlsDetails.SANs, expected) {
+			return mtlsDetails, errors.Errorf("server certificate does not contain the SAN '%s'", expected)
+		}
+	}
+	if len(c.config.SPKIPins) > 0 && !matchesSPKIPin(state.VerifiedChains, c.config.SPKIPins) {
+		return mtlsDetails, errors.New("server certificate chain does not match any of the SPKI pins")
+	}
+	return mtlsDetails, nil
+}
+
+// awaitClientCertAcceptance waits shortly for an alert from the server, treating a timeout or a clean close as acceptance.
+func awaitClientCertAcceptance(conn *tls.Conn) error {
+	_ = conn.SetReadDeadline(time.Now().Add(mutualTLSAlertWait))
+	_, err := conn.Read(make([]byte, 1))
+	if err == nil || err == io.EOF {
+		return nil
+	}
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return nil
+	}
+	return err
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func spkiPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func matchesSPKIPin(chains [][]*x509.Certificate, pins []string) bool {
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if containsString(pins, spkiPin(cert)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMutualTLSCheck_config(t *testing.T) {
+	ca := newTestCert(t, "test-ca", time.Now().Add(time.Hour), nil)
+
+	_, err := NewMutualTLSCheck(MutualTLSCheckConfig{CheckName: checkName, Certificate: ca.tlsCertificate()})
+	assert.EqualError(t, err, "Address must not be empty")
+
+	_, err = NewMutualTLSCheck(MutualTLSCheckConfig{CheckName: checkName, Address: "localhost:443"})
+	assert.EqualError(t, err, "Certificate must not be empty")
+
+	_, err = NewMutualTLSCheck(MutualTLSCheckConfig{Address: "localhost:443", Certificate: ca.tlsCertificate()})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewMutualTLSCheck(t *testing.T) {
+	ca := newTestCert(t, "test-ca", time.Now().Add(time.Hour), nil)
+	server := newTestCert(t, "test-server", time.Now().Add(time.Hour), ca)
+	client := newTestCert(t, "test-client", time.Now().Add(time.Hour), ca)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	address := startTLSServer(t, &tls.Config{
+		Certificates: []tls.Certificate{server.tlsCertificate()},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+	})
+
+	config := MutualTLSCheckConfig{
+		CheckName:    checkName,
+		Address:      address,
+		Certificate:  client.tlsCertificate(),
+		RootCAs:      roots,
+		ServerName:   "localhost",
+		ExpectedSANs: []string{"localhost", "127.0.0.1"},
+		SPKIPins:     []string{"other", spkiPin(ca.cert)},
+	}
+	check, err := NewMutualTLSCheck(config)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	mtlsDetails := details.(MutualTLSDetails)
+	assert.NotEmpty(t, mtlsDetails.CipherSuite)
+	assert.Equal(t, "CN=test-server", mtlsDetails.Subject)
+	assert.Equal(t, []string{"localhost", "127.0.0.1"}, mtlsDetails.SANs)
+	assert.Equal(t, spkiPin(server.cert), mtlsDetails.SPKIPin)
+
+	t.Run("unexpected SAN", func(t *testing.T) {
+		config := config
+		config.ExpectedSANs = []string{"api.example.com"}
+		check, err := NewMutualTLSCheck(config)
+		require.NoError(t, err)
+
+		details, err := check.Execute(context.Background())
+		assert.EqualError(t, err, "server certificate does not contain the SAN 'api.example.com'")
+		assert.Equal(t, mtlsDetails, details)
+	})
+
+	t.Run("SPKI pin mismatch", func(t *testing.T) {
+		config := config
+		config.SPKIPins = []string{spkiPin(client.cert)}
+		check, err := NewMutualTLSCheck(config)
+		require.NoError(t, err)
+
+		_, err = check.Execute(context.Background())
+		assert.EqualError(t, err, "server certificate chain does not match any of the SPKI pins")
+	})
+
+	t.Run("rejected client certificate", func(t *testing.T) {
+		config := config
+		config.Certificate = newTestCert(t, "untrusted-client", time.Now().Add(time.Hour), nil).tlsCertificate()
+		check, err := NewMutualTLSCheck(config)
+		require.NoError(t, err)
+
+		details, err := check.Execute(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server rejected the client certificate")
+		assert.Nil(t, details)
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		config := config
+		config.RootCAs = x509.NewCertPool()
+		check, err := NewMutualTLSCheck(config)
+		require.NoError(t, err)
+
+		_, err = check.Execute(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TLS handshake with '"+address+"' failed")
+	})
+}
@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	mysqlReplicaStatusQuery = "SHOW REPLICA STATUS"
+	// mysqlLegacyReplicaStatusQuery is used by MySQL before 8.0.22 and MariaDB before 10.5.1, which do not support SHOW REPLICA STATUS
+	mysqlLegacyReplicaStatusQuery = "SHOW SLAVE STATUS"
+)
+
+// mysqlReplicaStatusColumns maps the columns of the replica status to their legacy names, which are still used
+// by MySQL before 8.0.22 (with SHOW SLAVE STATUS) and by MariaDB
+var mysqlReplicaStatusColumns = map[string]string{
+	"Channel_Name":          "Channel_Name",
+	"Source_Host":           "Master_Host",
+	"Replica_IO_Running":    "Slave_IO_Running",
+	"Replica_SQL_Running":   "Slave_SQL_Running",
+	"Seconds_Behind_Source": "Seconds_Behind_Master",
+	"Last_IO_Error":         "Last_IO_Error",
+	"Last_SQL_Error":        "Last_SQL_Error",
+}
+
+// MySQLReplicaStatus is the status of a replication channel, as reported by SHOW REPLICA STATUS
+type MySQLReplicaStatus struct {
+	// Channel is the replication channel name, which is empty for the default channel
+	Channel    string `json:"channel,omitempty"`
+	SourceHost string `json:"sourceHost"`
+	// IORunning is the state of the replication I/O thread, i.e. "Yes", "No" or "Connecting"
+	IORunning string `json:"ioRunning"`
+	// SQLRunning is the state of the replication SQL thread, i.e. "Yes" or "No"
+	SQLRunning string `json:"sqlRunning"`
+	// SecondsBehindSource is the replication lag in seconds, or nil when unknown, e.g. when a thread is stopped
+	SecondsBehindSource *int64 `json:"secondsBehindSource"`
+	LastIOError         string `json:"lastIOError,omitempty"`
+	LastSQLError        string `json:"lastSQLError,omitempty"`
+}
+
+type mysqlReplicationLagCheck struct {
+	name   string
+	db     *sql.DB
+	maxLag time.Duration
+}
+
+// NewMySQLReplicationLagCheck returns a Check that reads the replica status of a MySQL or MariaDB replica using SHOW REPLICA STATUS,
+// falling back to SHOW SLAVE STATUS for the versions which do not support it (MySQL before 8.0.22 and MariaDB before 10.5.1),
+// and fails when the replication I/O or SQL thread of any channel is not running, or when its lag (Seconds_Behind_Source)
+// exceeds maxLag, unless maxLag is zero. The status of the replication channels is reported as the check details.
+func NewMySQLReplicationLagCheck(name string, db *sql.DB, maxLag time.Duration) (gosundheit.Check, error) {
+	if db == nil {
+		return nil, errors.New("DB must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &mysqlReplicationLagCheck{name: name, db: db, maxLag: maxLag}, nil
+}
+
+func (c *mysqlReplicationLagCheck) Name() string {
+	return c.name
+}
+
+func (c *mysqlReplicationLagCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	statuses, err := c.replicaStatus(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query the replica status")
+	}
+	if len(statuses) == 0 {
+		return statuses, errors.New("the database is not a replica")
+	}
+
+	for _, status := range statuses {
+		channel := ""
+		if status.Channel != "" {
+			channel = " of channel '" + status.Channel + "'"
+		}
+		switch {
+		case status.IORunning != "Yes":
+			return statuses, mysqlThreadError("I/O", channel, status.IORunning, status.LastIOError)
+		case status.SQLRunning != "Yes":
+			return statuses, mysqlThreadError("SQL", channel, status.SQLRunning, status.LastSQLError)
+		case status.SecondsBehindSource == nil:
+			return statuses, errors.Errorf("replication lag%s is unknown", channel)
+		case c.maxLag > 0 && time.Duration(*status.SecondsBehindSource)*time.Second > c.maxLag:
+			return statuses, errors.Errorf("replication lag%s of %v exceeds the maximum of %v",
+				channel, time.Duration(*status.SecondsBehindSource)*time.Second, c.maxLag)
+		}
+	}
+	return statuses, nil
+}
+
+// replicaStatus returns the status of each of the replication channels, reading the columns by name
+func (c *mysqlReplicationLagCheck) replicaStatus(ctx context.Context) ([]MySQLReplicaStatus, error) {
+	rows, err := c.db.QueryContext(ctx, mysqlReplicaStatusQuery)
+	if err != nil && ctx.Err() == nil {
+		// a syntax error on older versions
+		var legacyErr error
+		if rows, legacyErr = c.db.QueryContext(ctx, mysqlLegacyReplicaStatusQuery); legacyErr == nil {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	statuses := []MySQLReplicaStatus{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]sql.NullString, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		value := func(column string) sql.NullString {
+			if v, ok := row[column]; ok {
+				return v
+			}
+			return row[mysqlReplicaStatusColumns[column]]
+		}
+
+		status := MySQLReplicaStatus{
+			Channel:      value("Channel_Name").String,
+			SourceHost:   value("Source_Host").String,
+			IORunning:    value("Replica_IO_Running").String,
+			SQLRunning:   value("Replica_SQL_Running").String,
+			LastIOError:  value("Last_IO_Error").String,
+			LastSQLError: value("Last_SQL_Error").String,
+		}
+		if lag := value("Seconds_Behind_Source"); lag.Valid {
+			seconds, err := strconv.ParseInt(strings.TrimSpace(lag.String), 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid Seconds_Behind_Source '%s'", lag.String)
+			}
+			status.SecondsBehindSource = &seconds
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+func mysqlThreadError(thread, channel, state, lastError string) error {
+	if lastError == "" {
+		return errors.Errorf("replication %s thread%s is not running (%s)", thread, channel, state)
+	}
+	return errors.Errorf("replication %s thread%s is not running (%s): %s", thread, channel, state, lastError)
+}
@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMySQLReplicationLagCheck_nilDB(t *testing.T) {
+	check, err := NewMySQLReplicationLagCheck(checkName, nil, time.Minute)
+	assert.EqualError(t, err, "DB must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewMySQLReplicationLagCheck(t *testing.T) {
+	fake := &fakeDB{
+		rows: map[string][][]driver.Value{},
+		columns: map[string][]string{mysqlReplicaStatusQuery: {
+			"Replica_IO_State", "Source_Host", "Replica_IO_Running", "Replica_SQL_Running",
+			"Last_SQL_Error", "Seconds_Behind_Source", "Last_IO_Error", "Channel_Name",
+		}},
+	}
+	db := openFakeDB(fake)
+	defer db.Close()
+
+	check, err := NewMySQLReplicationLagCheck(checkName, db, 30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "the database is not a replica")
+	assert.Equal(t, []MySQLReplicaStatus{}, details)
+
+	fake.rows[mysqlReplicaStatusQuery] = [][]driver.Value{
+		{"Waiting for source to send event", "primary-1", "Yes", "Yes", "", []byte("12"), "", ""},
+	}
+	details, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+	lag := int64(12)
+	assert.Equal(t, []MySQLReplicaStatus{{SourceHost: "primary-1", IORunning: "Yes", SQLRunning: "Yes", SecondsBehindSource: &lag}}, details)
+
+	fake.rows[mysqlReplicaStatusQuery] = [][]driver.Value{
+		{"", "primary-1", "Yes", "Yes", "", "12", "", ""},
+		{"", "primary-2", "Yes", "Yes", "", "45", "", "analytics"},
+	}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "replication lag of channel 'analytics' of 45s exceeds the maximum of 30s")
+
+	fake.rows[mysqlReplicaStatusQuery] = [][]driver.Value{
+		{"", "primary-1", "Connecting", "Yes", "", nil, "error connecting to source", ""},
+	}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "replication I/O thread is not running (Connecting): error connecting to source")
+
+	fake.rows[mysqlReplicaStatusQuery] = [][]driver.Value{
+		{"", "primary-1", "Yes", "No", "", nil, "", ""},
+	}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "replication SQL thread is not running (No)")
+}
+
+func TestNewMySQLReplicationLagCheck_legacyColumns(t *testing.T) {
+	fake := &fakeDB{
+		rows: map[string][][]driver.Value{mysqlReplicaStatusQuery: {
+			{"primary-1", "Yes", "Yes", "3"},
+		}},
+		columns: map[string][]string{mysqlReplicaStatusQuery: {
+			"Master_Host", "Slave_IO_Running", "Slave_SQL_Running", "Seconds_Behind_Master",
+		}},
+	}
+	db := openFakeDB(fake)
+	defer db.Close()
+
+	check, err := NewMySQLReplicationLagCheck(checkName, db, 0)
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	lag := int64(3)
+	assert.Equal(t, []MySQLReplicaStatus{{SourceHost: "primary-1", IORunning: "Yes", SQLRunning: "Yes", SecondsBehindSource: &lag}}, details)
+}
+
+func TestNewMySQLReplicationLagCheck_legacyQuery(t *testing.T) {
+	fake := &fakeDB{
+		rows: map[string][][]driver.Value{mysqlLegacyReplicaStatusQuery: {
+			{"primary-1", "Yes", "Yes", "3"},
+		}},
+		columns: map[string][]string{mysqlLegacyReplicaStatusQuery: {
+			"Master_Host", "Slave_IO_Running", "Slave_SQL_Running", "Seconds_Behind_Master",
+		}},
+		queryErrs: map[string]error{mysqlReplicaStatusQuery: errors.New("Error 1064: You have an error in your SQL syntax")},
+	}
+	db := openFakeDB(fake)
+	defer db.Close()
+
+	check, err := NewMySQLReplicationLagCheck(checkName, db, 0)
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "primary-1", details.([]MySQLReplicaStatus)[0].SourceHost)
+
+	fake.queryErrs[mysqlLegacyReplicaStatusQuery] = errors.New("Error 1227: Access denied")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to query the replica status: Error 1064: You have an error in your SQL syntax",
+		"the error of the current query is reported")
+}
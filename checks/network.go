@@ -0,0 +1,172 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// NetworkCheckConfig configures a check for the local network configuration.
+// At least one of Interface and DefaultRoute is required.
+type NetworkCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Interface is the name of a network interface, e.g. "eth0", which must be up with an assigned (non link-local) address.
+	Interface string
+	// DefaultRoute indicates when true, that an IPv4 or IPv6 default route must exist. Default routes are only supported on Linux.
+	DefaultRoute bool
+}
+
+// NetworkDetails are the details of a network check
+type NetworkDetails struct {
+	Interface string   `json:"interface,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+	// DefaultRoutes are the default routes, when DefaultRoute is set
+	DefaultRoutes []DefaultRoute `json:"defaultRoutes,omitempty"`
+}
+
+// DefaultRoute is a default route of the host
+type DefaultRoute struct {
+	Interface string `json:"interface"`
+	// Gateway is the address of the default gateway, or nil for a directly connected route
+	Gateway net.IP `json:"gateway,omitempty"`
+}
+
+type networkCheck struct {
+	config NetworkCheckConfig
+}
+
+// NewNetworkCheck returns a Check that verifies a network interface is up with an assigned address, and/or that a default route exists,
+// which catches nodes that lose networking but keep running. The interface addresses and the default routes are reported as the check details.
+func NewNetworkCheck(config NetworkCheckConfig) (gosundheit.Check, error) {
+	if config.Interface == "" && !config.DefaultRoute {
+		return nil, errors.New("at least one of Interface and DefaultRoute must be set")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+
+	return &networkCheck{config: config}, nil
+}
+
+func (c *networkCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *networkCheck) Execute(_ context.Context) (details interface{}, err error) {
+	var networkDetails NetworkDetails
+	if c.config.Interface != "" {
+		networkDetails.Interface = c.config.Interface
+		if networkDetails.Addresses, err = interfaceAddresses(c.config.Interface); err != nil {
+			return networkDetails, err
+		}
+	}
+
+	if c.config.DefaultRoute {
+		if networkDetails.DefaultRoutes, err = readDefaultRoutes(); err != nil {
+			return networkDetails, err
+		}
+		if len(networkDetails.DefaultRoutes) == 0 {
+			return networkDetails, errors.New("no default route")
+		}
+	}
+	return networkDetails, nil
+}
+
+// interfaceAddresses returns the addresses of the network interface with the given name, and fails unless it is up with a non link-local address
+func interfaceAddresses(name string) ([]string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, errors.Errorf("failed to get network interface '%s': %v", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, errors.Errorf("failed to get the addresses of network interface '%s': %v", name, err)
+	}
+
+	addresses := make([]string, len(addrs))
+	assigned := false
+	for i, addr := range addrs {
+		addresses[i] = addr.String()
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLinkLocalUnicast() {
+			assigned = true
+		}
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return addresses, errors.Errorf("network interface '%s' is down", name)
+	}
+	if !assigned {
+		return addresses, errors.Errorf("network interface '%s' has no assigned address", name)
+	}
+	return addresses, nil
+}
+
+const (
+	// rtfUp is the RTF_UP route flag
+	rtfUp = 0x1
+	// rtfReject is the RTF_REJECT route flag, e.g. of unreachable routes
+	rtfReject = 0x200
+)
+
+// parseIPv4DefaultRoutes parses the default routes of a /proc/net/route file:
+// Iface Destination Gateway Flags RefCnt Use Metric Mask MTU Window IRTT, where addresses are little endian hex.
+func parseIPv4DefaultRoutes(r io.Reader) ([]DefaultRoute, error) {
+	var routes []DefaultRoute
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&rtfUp == 0 || flags&rtfReject != 0 {
+			continue
+		}
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != net.IPv4len {
+			return nil, errors.Errorf("malformed gateway '%s'", fields[2])
+		}
+		route := DefaultRoute{Interface: fields[0]}
+		if gateway := net.IPv4(gateway[3], gateway[2], gateway[1], gateway[0]); !gateway.Equal(net.IPv4zero) {
+			route.Gateway = gateway
+		}
+		routes = append(routes, route)
+	}
+	return routes, scanner.Err()
+}
+
+// parseIPv6DefaultRoutes parses the default routes of a /proc/net/ipv6_route file:
+// destination prefix-length source source-prefix-length next-hop metric ref-count use flags iface, where addresses are big endian hex.
+func parseIPv6DefaultRoutes(r io.Reader) ([]DefaultRoute, error) {
+	var routes []DefaultRoute
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] != strings.Repeat("0", 32) || fields[1] != "00" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&rtfUp == 0 || flags&rtfReject != 0 {
+			continue
+		}
+		gateway, err := hex.DecodeString(fields[4])
+		if err != nil || len(gateway) != net.IPv6len {
+			return nil, errors.Errorf("malformed gateway '%s'", fields[4])
+		}
+		route := DefaultRoute{Interface: fields[9]}
+		if !net.IP(gateway).Equal(net.IPv6zero) {
+			route.Gateway = gateway
+		}
+		routes = append(routes, route)
+	}
+	return routes, scanner.Err()
+}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// readDefaultRoutes reads the IPv4 and IPv6 default routes from /proc/net
+func readDefaultRoutes() ([]DefaultRoute, error) {
+	var routes []DefaultRoute
+	for _, routesFile := range []struct {
+		path  string
+		parse func(io.Reader) ([]DefaultRoute, error)
+	}{
+		{path: "/proc/net/route", parse: parseIPv4DefaultRoutes},
+		{path: "/proc/net/ipv6_route", parse: parseIPv6DefaultRoutes},
+	} {
+		f, err := os.Open(routesFile.path)
+		if os.IsNotExist(err) {
+			continue // e.g. IPv6 is disabled
+		}
+		if err != nil {
+			return nil, errors.Errorf("failed to read routes: %v", err)
+		}
+		fileRoutes, err := routesFile.parse(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, errors.Errorf("failed to parse '%s': %v", routesFile.path, err)
+		}
+		routes = append(routes, fileRoutes...)
+	}
+	return routes, nil
+}
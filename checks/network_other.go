@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import "github.com/pkg/errors"
+
+func readDefaultRoutes() ([]DefaultRoute, error) {
+	return nil, errors.New("default routes are only supported on Linux")
+}
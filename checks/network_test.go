@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNetworkCheck_config(t *testing.T) {
+	_, err := NewNetworkCheck(NetworkCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "at least one of Interface and DefaultRoute must be set")
+
+	_, err = NewNetworkCheck(NetworkCheckConfig{Interface: "eth0"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewNetworkCheck_interface(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	var loopback string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 && iface.Flags&net.FlagUp != 0 {
+			loopback = iface.Name
+		}
+	}
+	if loopback == "" {
+		t.Skip("no loopback interface")
+	}
+
+	check, err := NewNetworkCheck(NetworkCheckConfig{CheckName: checkName, Interface: loopback})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, loopback, details.(NetworkDetails).Interface)
+	assert.NotEmpty(t, details.(NetworkDetails).Addresses)
+
+	check, err = NewNetworkCheck(NetworkCheckConfig{CheckName: checkName, Interface: "no-such-if0"})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get network interface 'no-such-if0'")
+}
+
+func TestNewNetworkCheck_defaultRoute(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("default routes are only supported on Linux")
+	}
+	routes, err := readDefaultRoutes()
+	require.NoError(t, err)
+
+	check, err := NewNetworkCheck(NetworkCheckConfig{CheckName: checkName, DefaultRoute: true})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	if len(routes) == 0 {
+		assert.EqualError(t, err, "no default route")
+	} else {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, routes, details.(NetworkDetails).DefaultRoutes)
+}
+
+func TestParseDefaultRoutes(t *testing.T) {
+	routes, err := parseIPv4DefaultRoutes(strings.NewReader(
+		"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+			"eth0\t00000000\t010200C0\t0003\t0\t0\t0\t00000000\t0\t0\t0\n" +
+			"eth0\t000200C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n" +
+			"wg0\t00000000\t00000000\t0001\t0\t0\t0\t00000000\t0\t0\t0\n" +
+			"eth1\t00000000\t010200C0\t0002\t0\t0\t0\t00000000\t0\t0\t0\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []DefaultRoute{
+		{Interface: "eth0", Gateway: net.IPv4(192, 0, 2, 1)},
+		{Interface: "wg0"},
+	}, routes)
+
+	routes, err = parseIPv6DefaultRoutes(strings.NewReader(
+		"fd000000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0\n" +
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 fd000000000000000000000000000001 00000400 00000001 00000000 00000003     eth0\n" +
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []DefaultRoute{{Interface: "eth0", Gateway: net.ParseIP("fd00::1")}}, routes)
+}
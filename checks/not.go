@@ -0,0 +1,32 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+type notCheck struct {
+	name  string
+	check gosundheit.Check
+}
+
+// Not returns a check that passes when the given check fails, and fails when it passes, e.g. for asserting that
+// a legacy port is no longer reachable after a migration. The details of the given check are reported as is.
+func Not(name string, check gosundheit.Check) gosundheit.Check {
+	return &notCheck{name: name, check: check}
+}
+
+func (c *notCheck) Name() string {
+	return c.name
+}
+
+func (c *notCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	details, err = c.check.Execute(ctx)
+	if err != nil {
+		return details, nil
+	}
+	return details, errors.Errorf("check '%s' passed, but is expected to fail", c.check.Name())
+}
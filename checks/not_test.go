@@ -0,0 +1,42 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNot(t *testing.T) {
+	check := Not(checkName, childCheck("legacy", errors.New("connection refused")))
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy details", details)
+
+	check = Not(checkName, childCheck("legacy", nil))
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "check 'legacy' passed, but is expected to fail")
+	assert.Equal(t, "legacy details", details)
+}
+
+func TestNot_unreachablePort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	ping, err := NewPingCheck("legacy.port", NewDialPinger("tcp", address))
+	require.NoError(t, err)
+	check := Not("legacy.port.closed", ping)
+
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "check 'legacy.port' passed, but is expected to fail")
+
+	require.NoError(t, listener.Close())
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// oauth2ExpiryDelta is how long before their expiry tokens are refreshed
+const oauth2ExpiryDelta = 10 * time.Second
+
+// OAuth2ClientCredentials configures obtaining access tokens using the OAuth2 client credentials grant.
+type OAuth2ClientCredentials struct {
+	// TokenURL is the required URL of the authorization server token endpoint
+	TokenURL string
+	// ClientID is the required client ID
+	ClientID string
+	// ClientSecret is the client secret
+	ClientSecret string
+	// Scopes are the optional requested scopes
+	Scopes []string
+	// Client is optional; if undefined, a new client with a "1s" timeout will be used.
+	Client *http.Client
+}
+
+type oauth2TokenProvider struct {
+	credentials OAuth2ClientCredentials
+
+	lock   sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewOAuth2TokenProvider returns a bearer token provider (see HTTPCheckConfig.BearerTokenProvider), that obtains access tokens
+// using the OAuth2 client credentials grant, and caches them until shortly before they expire.
+// To use an oauth2.TokenSource instead, wrap its Token() method in a BearerTokenProvider function.
+func NewOAuth2TokenProvider(credentials OAuth2ClientCredentials) (func(ctx context.Context) (string, error), error) {
+	if credentials.TokenURL == "" {
+		return nil, errors.New("TokenURL must not be empty")
+	}
+	if credentials.ClientID == "" {
+		return nil, errors.New("ClientID must not be empty")
+	}
+	if credentials.Client == nil {
+		credentials.Client = &http.Client{Timeout: time.Second}
+	}
+
+	provider := &oauth2TokenProvider{credentials: credentials}
+	return provider.Token, nil
+}
+
+// Token returns a cached access token, or obtains a new one when it is about to expire.
+func (p *oauth2TokenProvider) Token(ctx context.Context) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.token != "" && time.Now().Add(oauth2ExpiryDelta).Before(p.expiry) {
+		return p.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(p.credentials.Scopes) > 0 {
+		form.Set("scope", strings.Join(p.credentials.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.credentials.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Errorf("unable to create token request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.credentials.ClientID), url.QueryEscape(p.credentials.ClientSecret))
+
+	resp, err := p.credentials.Client.Do(req)
+	if err != nil {
+		return "", errors.Errorf("token request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Errorf("failed to decode token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return "", errors.Errorf("token request failed with status code '%v': %s", resp.StatusCode, token.Error)
+	}
+
+	// tokens without an expiry are not cached
+	p.token, p.expiry = token.AccessToken, time.Time{}
+	if token.ExpiresIn > 0 {
+		p.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return p.token, nil
+}
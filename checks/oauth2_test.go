@@ -0,0 +1,103 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTokenServer starts an OAuth2 token endpoint, which issues sequential tokens with the given expiry
+func startTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		clientID, secret, _ := req.BasicAuth()
+		if req.PostFormValue("grant_type") != "client_credentials" || clientID != "health-checker" || secret != "s3cr3t" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, _ = rw.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		token := atomic.AddInt32(&issued, 1)
+		_, _ = fmt.Fprintf(rw, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d,"scope":%q}`, token, expiresIn, req.PostFormValue("scope"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func TestNewOAuth2TokenProvider_config(t *testing.T) {
+	_, err := NewOAuth2TokenProvider(OAuth2ClientCredentials{ClientID: "health-checker"})
+	assert.EqualError(t, err, "TokenURL must not be empty")
+
+	_, err = NewOAuth2TokenProvider(OAuth2ClientCredentials{TokenURL: "http://localhost/token"})
+	assert.EqualError(t, err, "ClientID must not be empty")
+}
+
+func TestNewOAuth2TokenProvider(t *testing.T) {
+	server, issued := startTokenServer(t, 3600)
+	provider, err := NewOAuth2TokenProvider(OAuth2ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     "health-checker",
+		ClientSecret: "s3cr3t",
+		Scopes:       []string{"health:read"},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		token, err := provider(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1", token, "the token is cached")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(issued))
+}
+
+func TestNewOAuth2TokenProvider_refresh(t *testing.T) {
+	// tokens expiring within the expiry delta are always refreshed
+	server, _ := startTokenServer(t, 5)
+	provider, err := NewOAuth2TokenProvider(OAuth2ClientCredentials{TokenURL: server.URL, ClientID: "health-checker", ClientSecret: "s3cr3t"})
+	require.NoError(t, err)
+
+	token, err := provider(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	token, err = provider(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+}
+
+func TestNewOAuth2TokenProvider_invalidClient(t *testing.T) {
+	server, _ := startTokenServer(t, 3600)
+	provider, err := NewOAuth2TokenProvider(OAuth2ClientCredentials{TokenURL: server.URL, ClientID: "health-checker", ClientSecret: "wrong"})
+	require.NoError(t, err)
+
+	_, err = provider(context.Background())
+	assert.EqualError(t, err, "token request failed with status code '401': invalid_client")
+}
+
+func TestNewHttpCheck_oauth2(t *testing.T) {
+	tokenServer, _ := startTokenServer(t, 3600)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token-1" {
+			rw.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPCheck(HTTPCheckConfig{CheckName: "url.check", URL: server.URL, OAuth2: &OAuth2ClientCredentials{}})
+	assert.EqualError(t, err, "invalid OAuth2 configuration: TokenURL must not be empty")
+
+	check, err := NewHTTPCheck(HTTPCheckConfig{
+		CheckName: "url.check",
+		URL:       server.URL,
+		Client:    server.Client(),
+		OAuth2:    &OAuth2ClientCredentials{TokenURL: tokenServer.URL, ClientID: "health-checker", ClientSecret: "s3cr3t"},
+	})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+}
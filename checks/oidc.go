@@ -0,0 +1,117 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// OIDCDiscoveryCheckConfig configures a check for the discovery endpoint of an OpenID Connect identity provider.
+type OIDCDiscoveryCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Issuer is the required issuer URL of the identity provider, e.g. "https://accounts.example.com".
+	// The provider configuration is fetched from its "/.well-known/openid-configuration" path, and must declare exactly this issuer.
+	Issuer string
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+// OIDCProviderMetadata is the OpenID Connect provider metadata, as returned by the discovery endpoint
+type OIDCProviderMetadata struct {
+	Issuer                           string   `json:"issuer"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	TokenEndpoint                    string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+type oidcDiscoveryCheck struct {
+	config       OIDCDiscoveryCheckConfig
+	discoveryURL string
+}
+
+// NewOIDCDiscoveryCheck returns a Check that fetches the OpenID Connect discovery document of an identity provider, and fails
+// unless it parses, declares the configured issuer, and contains the fields required by the OpenID Connect Discovery specification,
+// with valid endpoint URLs. The provider metadata is reported as the check details.
+func NewOIDCDiscoveryCheck(config OIDCDiscoveryCheckConfig) (gosundheit.Check, error) {
+	if config.Issuer == "" {
+		return nil, errors.New("Issuer must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &oidcDiscoveryCheck{
+		config:       config,
+		discoveryURL: strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration",
+	}, nil
+}
+
+func (c *oidcDiscoveryCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *oidcDiscoveryCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var metadata OIDCProviderMetadata
+	if err := fetchJSON(ctx, c.config.Client, c.discoveryURL, c.config.Options, &metadata); err != nil {
+		return nil, err
+	}
+
+	if metadata.Issuer != c.config.Issuer {
+		return metadata, errors.Errorf("issuer mismatch: '%s' expected: '%s'", metadata.Issuer, c.config.Issuer)
+	}
+	for _, endpoint := range []struct {
+		field    string
+		value    string
+		required bool
+	}{
+		{field: "authorization_endpoint", value: metadata.AuthorizationEndpoint, required: true},
+		{field: "jwks_uri", value: metadata.JWKSURI, required: true},
+		{field: "token_endpoint", value: metadata.TokenEndpoint},
+		{field: "userinfo_endpoint", value: metadata.UserinfoEndpoint},
+	} {
+		if endpoint.value == "" {
+			if endpoint.required {
+				return metadata, errors.Errorf("%s is missing", endpoint.field)
+			}
+			continue
+		}
+		if u, err := url.Parse(endpoint.value); err != nil || !u.IsAbs() || u.Host == "" {
+			return metadata, errors.Errorf("invalid %s '%s'", endpoint.field, endpoint.value)
+		}
+	}
+	for _, supported := range []struct {
+		field  string
+		values []string
+	}{
+		{field: "response_types_supported", values: metadata.ResponseTypesSupported},
+		{field: "subject_types_supported", values: metadata.SubjectTypesSupported},
+		{field: "id_token_signing_alg_values_supported", values: metadata.IDTokenSigningAlgValuesSupported},
+	} {
+		if len(supported.values) == 0 {
+			return metadata, errors.Errorf("%s is missing", supported.field)
+		}
+	}
+	return metadata, nil
+}
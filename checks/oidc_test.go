@@ -0,0 +1,100 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOIDCDiscoveryCheck_config(t *testing.T) {
+	_, err := NewOIDCDiscoveryCheck(OIDCDiscoveryCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "Issuer must not be empty")
+
+	_, err = NewOIDCDiscoveryCheck(OIDCDiscoveryCheckConfig{Issuer: "https://accounts.example.com"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	check, err := NewOIDCDiscoveryCheck(OIDCDiscoveryCheckConfig{CheckName: checkName, Issuer: "https://accounts.example.com/"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	assert.Equal(t, "https://accounts.example.com/.well-known/openid-configuration", check.(*oidcDiscoveryCheck).discoveryURL)
+}
+
+func TestNewOIDCDiscoveryCheck(t *testing.T) {
+	var metadata map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/realms/main/.well-known/openid-configuration" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if metadata == nil {
+			_, _ = rw.Write([]byte("<html>"))
+			return
+		}
+		_ = json.NewEncoder(rw).Encode(metadata)
+	}))
+	defer server.Close()
+	issuer := server.URL + "/realms/main"
+
+	reset := func() {
+		metadata = map[string]interface{}{
+			"issuer":                                issuer,
+			"authorization_endpoint":                issuer + "/auth",
+			"token_endpoint":                        issuer + "/token",
+			"jwks_uri":                              issuer + "/certs",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		}
+	}
+	execute := func() (interface{}, error) {
+		check, err := NewOIDCDiscoveryCheck(OIDCDiscoveryCheckConfig{CheckName: checkName, Issuer: issuer})
+		require.NoError(t, err)
+		return check.Execute(context.Background())
+	}
+
+	reset()
+	details, err := execute()
+	assert.NoError(t, err)
+	assert.Equal(t, OIDCProviderMetadata{
+		Issuer:                           issuer,
+		AuthorizationEndpoint:            issuer + "/auth",
+		TokenEndpoint:                    issuer + "/token",
+		JWKSURI:                          issuer + "/certs",
+		ResponseTypesSupported:           []string{"code"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{"RS256"},
+	}, details)
+
+	metadata["issuer"] = server.URL
+	_, err = execute()
+	assert.EqualError(t, err, "issuer mismatch: '"+server.URL+"' expected: '"+issuer+"'")
+
+	reset()
+	delete(metadata, "jwks_uri")
+	_, err = execute()
+	assert.EqualError(t, err, "jwks_uri is missing")
+
+	reset()
+	metadata["userinfo_endpoint"] = "/userinfo"
+	_, err = execute()
+	assert.EqualError(t, err, "invalid userinfo_endpoint '/userinfo'")
+
+	reset()
+	delete(metadata, "subject_types_supported")
+	_, err = execute()
+	assert.EqualError(t, err, "subject_types_supported is missing")
+
+	metadata = nil
+	_, err = execute()
+	assert.EqualError(t, err, "failed to decode response body: invalid character '<' looking for beginning of value")
+
+	check, err := NewOIDCDiscoveryCheck(OIDCDiscoveryCheckConfig{CheckName: checkName, Issuer: server.URL})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "unexpected status code: '404' expected: '[200]'")
+}
@@ -3,6 +3,7 @@ package checks
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -23,6 +24,20 @@ func (f PingContextFunc) PingContext(ctx context.Context) error {
 	return f(ctx)
 }
 
+// AddrPinger is a Pinger that also reports the address it pinged, e.g. the resolved address of a dialed host
+type AddrPinger interface {
+	Pinger
+	PingAddr(ctx context.Context) (net.Addr, error)
+}
+
+// PingDetails are the details of a latency ping check
+type PingDetails struct {
+	// Latency is the duration of the ping
+	Latency time.Duration `json:"latency"`
+	// Address is the pinged address, when the Pinger is an AddrPinger
+	Address string `json:"address,omitempty"`
+}
+
 // NewPingCheck returns a Check that pings using the specified Pinger and fails on context cancellation or ping failure
 func NewPingCheck(name string, pinger Pinger) (gosundheit.Check, error) {
 	if pinger == nil {
@@ -49,3 +64,63 @@ func NewDialPinger(network, address string) PingContextFunc {
 		return err
 	}
 }
+
+// NewLatencyPingCheck returns a Check that pings using the specified Pinger, and fails on context cancellation, ping failure,
+// or when the ping takes longer than maxLatency (zero means no threshold). The measured latency is reported as PingDetails,
+// which also include the pinged address when the Pinger is an AddrPinger (e.g. NewAddrDialPinger).
+func NewLatencyPingCheck(name string, pinger Pinger, maxLatency time.Duration) (gosundheit.Check, error) {
+	if pinger == nil {
+		return nil, errors.New("Pinger must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &CustomCheck{
+		CheckName: name,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			var pingDetails PingDetails
+			start := time.Now()
+			if addrPinger, ok := pinger.(AddrPinger); ok {
+				var addr net.Addr
+				if addr, err = addrPinger.PingAddr(ctx); addr != nil {
+					pingDetails.Address = addr.String()
+				}
+			} else {
+				err = pinger.PingContext(ctx)
+			}
+			pingDetails.Latency = time.Since(start)
+
+			if err == nil && maxLatency > 0 && pingDetails.Latency > maxLatency {
+				err = errors.Errorf("ping latency of %v exceeds the maximum of %v", pingDetails.Latency, maxLatency)
+			}
+			return pingDetails, err
+		},
+	}, nil
+}
+
+// NewAddrDialPinger returns an AddrPinger that pings the specified address, and reports the resolved remote address it connected to
+func NewAddrDialPinger(network, address string) AddrPinger {
+	return &dialPinger{network: network, address: address}
+}
+
+type dialPinger struct {
+	network string
+	address string
+	dialer  net.Dialer
+}
+
+func (p *dialPinger) PingContext(ctx context.Context) error {
+	_, err := p.PingAddr(ctx)
+	return err
+}
+
+func (p *dialPinger) PingAddr(ctx context.Context) (net.Addr, error) {
+	conn, err := p.dialer.DialContext(ctx, p.network, p.address)
+	if err != nil {
+		return nil, err
+	}
+	addr := conn.RemoteAddr()
+	_ = conn.Close()
+	return addr, nil
+}
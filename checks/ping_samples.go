@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// PingSamplesThresholds are the thresholds of a sampled ping check
+type PingSamplesThresholds struct {
+	// MaxLoss is the maximal ratio of failed pings, between 0 and 1, where zero means no failed ping is tolerated
+	MaxLoss float64
+	// MaxP95 is the maximal 95th percentile latency of the successful pings, where zero means no threshold
+	MaxP95 time.Duration
+}
+
+// PingSamplesDetails are the details of a sampled ping check, where the latencies are those of the successful pings
+type PingSamplesDetails struct {
+	Sent     int           `json:"sent"`
+	Received int           `json:"received"`
+	Loss     float64       `json:"loss"`
+	Min      time.Duration `json:"min,omitempty"`
+	Avg      time.Duration `json:"avg,omitempty"`
+	P50      time.Duration `json:"p50,omitempty"`
+	P95      time.Duration `json:"p95,omitempty"`
+	Max      time.Duration `json:"max,omitempty"`
+	// LastError is the error of the last failed ping, if any
+	LastError string `json:"lastError,omitempty"`
+}
+
+type pingSamplesCheck struct {
+	name       string
+	pinger     Pinger
+	samples    int
+	thresholds PingSamplesThresholds
+}
+
+// NewPingSamplesCheck returns a Check that pings sequentially the given number of samples using the specified Pinger
+// (e.g. NewDialPinger), and fails when the ratio of failed pings or the 95th percentile latency exceed the given thresholds.
+// Unlike a single ping, this detects intermittent degradation. Pings which are not sent before the check context is done
+// count as failed. The latency distribution is reported as the check details.
+func NewPingSamplesCheck(name string, pinger Pinger, samples int, thresholds PingSamplesThresholds) (gosundheit.Check, error) {
+	if pinger == nil {
+		return nil, errors.New("Pinger must not be nil")
+	}
+	if samples <= 0 {
+		return nil, errors.New("samples must be positive")
+	}
+	if thresholds.MaxLoss < 0 || thresholds.MaxLoss > 1 {
+		return nil, errors.New("MaxLoss must be between 0 and 1")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &pingSamplesCheck{name: name, pinger: pinger, samples: samples, thresholds: thresholds}, nil
+}
+
+func (c *pingSamplesCheck) Name() string {
+	return c.name
+}
+
+func (c *pingSamplesCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var samplesDetails PingSamplesDetails
+	latencies := make([]time.Duration, 0, c.samples)
+	for ; samplesDetails.Sent < c.samples && ctx.Err() == nil; samplesDetails.Sent++ {
+		start := time.Now()
+		if err := c.pinger.PingContext(ctx); err != nil {
+			samplesDetails.LastError = err.Error()
+			continue
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	if ctx.Err() != nil && samplesDetails.Sent < c.samples {
+		samplesDetails.LastError = ctx.Err().Error()
+	}
+
+	samplesDetails.Received = len(latencies)
+	samplesDetails.Loss = float64(c.samples-samplesDetails.Received) / float64(c.samples)
+	if samplesDetails.Received == 0 {
+		return samplesDetails, errors.Errorf("all %d pings failed: %s", c.samples, samplesDetails.LastError)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	samplesDetails.Min, samplesDetails.Max = latencies[0], latencies[len(latencies)-1]
+	samplesDetails.Avg = total / time.Duration(len(latencies))
+	samplesDetails.P50, samplesDetails.P95 = percentile(latencies, 0.5), percentile(latencies, 0.95)
+
+	if samplesDetails.Loss > c.thresholds.MaxLoss {
+		return samplesDetails, errors.Errorf("ping loss of %.0f%% exceeds the maximum of %.0f%%", samplesDetails.Loss*100, c.thresholds.MaxLoss*100)
+	}
+	if c.thresholds.MaxP95 > 0 && samplesDetails.P95 > c.thresholds.MaxP95 {
+		return samplesDetails, errors.Errorf("p95 ping latency of %v exceeds the maximum of %v", samplesDetails.P95, c.thresholds.MaxP95)
+	}
+	return samplesDetails, nil
+}
+
+// percentile returns the nearest-rank percentile p (between 0 and 1) of the given sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
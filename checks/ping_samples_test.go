@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// samplesPinger sleeps for each of its latencies in turn, where a zero latency fails the ping
+func samplesPinger(latencies ...time.Duration) PingContextFunc {
+	var i int
+	return func(ctx context.Context) error {
+		latency := latencies[i%len(latencies)]
+		i++
+		if latency == 0 {
+			return errors.New("connection refused")
+		}
+		time.Sleep(latency)
+		return nil
+	}
+}
+
+func TestNewPingSamplesCheck_config(t *testing.T) {
+	_, err := NewPingSamplesCheck(checkName, nil, 3, PingSamplesThresholds{})
+	assert.EqualError(t, err, "Pinger must not be nil")
+
+	_, err = NewPingSamplesCheck(checkName, samplesPinger(time.Millisecond), 0, PingSamplesThresholds{})
+	assert.EqualError(t, err, "samples must be positive")
+
+	_, err = NewPingSamplesCheck(checkName, samplesPinger(time.Millisecond), 3, PingSamplesThresholds{MaxLoss: 1.5})
+	assert.EqualError(t, err, "MaxLoss must be between 0 and 1")
+
+	_, err = NewPingSamplesCheck("", samplesPinger(time.Millisecond), 3, PingSamplesThresholds{})
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewPingSamplesCheck(t *testing.T) {
+	pinger := samplesPinger(time.Millisecond, 2*time.Millisecond, 0, 5*time.Millisecond)
+	check, err := NewPingSamplesCheck(checkName, pinger, 4, PingSamplesThresholds{MaxLoss: 0.25})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	samples := details.(PingSamplesDetails)
+	assert.Equal(t, 4, samples.Sent)
+	assert.Equal(t, 3, samples.Received)
+	assert.Equal(t, 0.25, samples.Loss)
+	assert.Equal(t, "connection refused", samples.LastError)
+	assert.True(t, samples.Min >= time.Millisecond && samples.Min < 2*time.Millisecond, "min %v", samples.Min)
+	assert.True(t, samples.P50 >= 2*time.Millisecond && samples.P50 < 5*time.Millisecond, "p50 %v", samples.P50)
+	assert.True(t, samples.P95 >= 5*time.Millisecond, "p95 %v", samples.P95)
+	assert.Equal(t, samples.P95, samples.Max)
+	assert.True(t, samples.Avg > samples.Min && samples.Avg < samples.Max, "avg %v", samples.Avg)
+}
+
+func TestNewPingSamplesCheck_thresholds(t *testing.T) {
+	check, err := NewPingSamplesCheck(checkName, samplesPinger(time.Millisecond, 0), 4, PingSamplesThresholds{MaxLoss: 0.25})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "ping loss of 50% exceeds the maximum of 25%")
+	assert.Equal(t, 2, details.(PingSamplesDetails).Received)
+
+	check, err = NewPingSamplesCheck(checkName, samplesPinger(0), 2, PingSamplesThresholds{MaxLoss: 1})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "all 2 pings failed: connection refused")
+
+	check, err = NewPingSamplesCheck(checkName, samplesPinger(time.Millisecond, 20*time.Millisecond), 2, PingSamplesThresholds{MaxP95: 10 * time.Millisecond})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "p95 ping latency of")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	check, err = NewPingSamplesCheck(checkName, samplesPinger(time.Millisecond), 3, PingSamplesThresholds{})
+	require.NoError(t, err)
+	details, err = check.Execute(ctx)
+	assert.EqualError(t, err, "all 3 pings failed: context canceled")
+	assert.Equal(t, 0, details.(PingSamplesDetails).Sent)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 20)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	assert.Equal(t, time.Duration(10), percentile(sorted, 0.5))
+	assert.Equal(t, time.Duration(19), percentile(sorted, 0.95))
+	assert.Equal(t, time.Duration(1), percentile(sorted[:1], 0.95))
+}
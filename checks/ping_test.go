@@ -76,3 +76,52 @@ func TestNewDialPinger_existingAddress(t *testing.T) {
 	defer cancel()
 	assertions.NoError(pinger.PingContext(ctx), "expecting success for an existing address")
 }
+
+func TestNewLatencyPingCheck(t *testing.T) {
+	_, err := NewLatencyPingCheck(checkName, nil, time.Second)
+	assert.EqualError(t, err, "Pinger must not be nil")
+	_, err = NewLatencyPingCheck("", mockPinger(false), time.Second)
+	assert.EqualError(t, err, "check name must not be empty")
+
+	check, err := NewLatencyPingCheck(checkName, mockPinger(false), time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, details.(PingDetails).Address, "the pinged address is unknown")
+
+	check, err = NewLatencyPingCheck(checkName, mockPinger(true), time.Second)
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "mock fail")
+
+	slowPinger := PingContextFunc(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	check, err = NewLatencyPingCheck(checkName, slowPinger, 10*time.Millisecond)
+	require.NoError(t, err)
+	details, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the maximum of 10ms")
+	assert.GreaterOrEqual(t, int64(details.(PingDetails).Latency), int64(20*time.Millisecond), "measured latency")
+}
+
+func TestNewLatencyPingCheck_addrDialPinger(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "failed to start a test listener")
+	defer func() { _ = ln.Close() }()
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	check, err := NewLatencyPingCheck(checkName, NewAddrDialPinger("tcp", net.JoinHostPort("localhost", port)), time.Second)
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ln.Addr().String(), details.(PingDetails).Address, "resolved address")
+
+	_ = ln.Close()
+	details, err = check.Execute(context.Background())
+	assert.Error(t, err)
+	assert.Empty(t, details.(PingDetails).Address)
+}
@@ -0,0 +1,149 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	pgInRecoveryQuery = "SELECT pg_is_in_recovery()"
+	// pgReplicaLagQuery returns the replay lag of a replica, which is zero when all the received WAL was replayed,
+	// since the time since the last replayed transaction grows while the primary is idle
+	pgReplicaLagQuery = "SELECT " +
+		"CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0 " +
+		"ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END::float8, " +
+		"COALESCE(pg_wal_lsn_diff(pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn()), 0)::bigint"
+	// pgStandbysLagQuery returns the replay lag of the standbys connected to a primary
+	pgStandbysLagQuery = "SELECT application_name, " +
+		"COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)::float8, " +
+		"COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn), 0)::bigint " +
+		"FROM pg_stat_replication"
+)
+
+// PostgresReplicationLagThresholds are the thresholds of a PostgreSQL replication lag check.
+// Zero values mean no threshold.
+type PostgresReplicationLagThresholds struct {
+	// MaxLag is the maximum replay lag duration.
+	MaxLag time.Duration
+	// MaxLagBytes is the maximum amount of WAL which was not replayed yet.
+	MaxLagBytes int64
+}
+
+// PostgresReplicationDetails are the details of a PostgreSQL replication lag check
+type PostgresReplicationDetails struct {
+	// Replica indicates whether the database is a replica (i.e. in recovery), or a primary
+	Replica bool `json:"replica"`
+	// Lag is the replay lag of the replica, or the maximal replay lag of the standbys of the primary
+	Lag time.Duration `json:"lag"`
+	// LagBytes is the amount of WAL not replayed by the replica yet, or the maximal amount of the standbys of the primary
+	LagBytes int64 `json:"lagBytes"`
+	// Standbys are the standbys connected to the primary
+	Standbys []PostgresStandbyLag `json:"standbys,omitempty"`
+}
+
+// PostgresStandbyLag is the replay lag of a standby connected to a primary, as reported by pg_stat_replication
+type PostgresStandbyLag struct {
+	ApplicationName string        `json:"applicationName"`
+	Lag             time.Duration `json:"lag"`
+	LagBytes        int64         `json:"lagBytes"`
+}
+
+type postgresReplicationLagCheck struct {
+	name       string
+	db         *sql.DB
+	thresholds PostgresReplicationLagThresholds
+}
+
+// NewPostgresReplicationLagCheck returns a Check that measures the replication lag of a PostgreSQL (10 or later) database,
+// and fails when any of the given thresholds is exceeded. On a replica, the lag is measured using pg_last_wal_replay_lsn,
+// and on a primary, the lag of each of its standbys is taken from pg_stat_replication. The measured lag is reported as the check details.
+func NewPostgresReplicationLagCheck(name string, db *sql.DB, thresholds PostgresReplicationLagThresholds) (gosundheit.Check, error) {
+	if db == nil {
+		return nil, errors.New("DB must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &postgresReplicationLagCheck{name: name, db: db, thresholds: thresholds}, nil
+}
+
+func (c *postgresReplicationLagCheck) Name() string {
+	return c.name
+}
+
+func (c *postgresReplicationLagCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var replicationDetails PostgresReplicationDetails
+	if err := c.db.QueryRowContext(ctx, pgInRecoveryQuery).Scan(&replicationDetails.Replica); err != nil {
+		return nil, errors.Wrap(err, "failed to query the recovery status")
+	}
+
+	if replicationDetails.Replica {
+		var lagSeconds float64
+		if err := c.db.QueryRowContext(ctx, pgReplicaLagQuery).Scan(&lagSeconds, &replicationDetails.LagBytes); err != nil {
+			return replicationDetails, errors.Wrap(err, "failed to query the replica lag")
+		}
+		replicationDetails.Lag = secondsToDuration(lagSeconds)
+		return replicationDetails, c.checkLag("replica", replicationDetails.Lag, replicationDetails.LagBytes)
+	}
+
+	standbys, err := c.standbysLag(ctx)
+	if err != nil {
+		return replicationDetails, errors.Wrap(err, "failed to query the standbys lag")
+	}
+	replicationDetails.Standbys = standbys
+	for _, standby := range standbys {
+		if standby.Lag > replicationDetails.Lag {
+			replicationDetails.Lag = standby.Lag
+		}
+		if standby.LagBytes > replicationDetails.LagBytes {
+			replicationDetails.LagBytes = standby.LagBytes
+		}
+	}
+	for _, standby := range standbys {
+		if err := c.checkLag("standby '"+standby.ApplicationName+"'", standby.Lag, standby.LagBytes); err != nil {
+			return replicationDetails, err
+		}
+	}
+	return replicationDetails, nil
+}
+
+func (c *postgresReplicationLagCheck) standbysLag(ctx context.Context) ([]PostgresStandbyLag, error) {
+	rows, err := c.db.QueryContext(ctx, pgStandbysLagQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var standbys []PostgresStandbyLag
+	for rows.Next() {
+		var standby PostgresStandbyLag
+		var lagSeconds float64
+		if err := rows.Scan(&standby.ApplicationName, &lagSeconds, &standby.LagBytes); err != nil {
+			return nil, err
+		}
+		standby.Lag = secondsToDuration(lagSeconds)
+		standbys = append(standbys, standby)
+	}
+	return standbys, rows.Err()
+}
+
+func (c *postgresReplicationLagCheck) checkLag(source string, lag time.Duration, lagBytes int64) error {
+	max := c.thresholds
+	switch {
+	case max.MaxLag > 0 && lag > max.MaxLag:
+		return errors.Errorf("%s replication lag of %v exceeds the maximum of %v", source, lag, max.MaxLag)
+	case max.MaxLagBytes > 0 && lagBytes > max.MaxLagBytes:
+		return errors.Errorf("%s replication lag of %d bytes exceeds the maximum of %d", source, lagBytes, max.MaxLagBytes)
+	}
+	return nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
@@ -0,0 +1,77 @@
+package checks
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPostgresReplicationLagCheck_nilDB(t *testing.T) {
+	check, err := NewPostgresReplicationLagCheck(checkName, nil, PostgresReplicationLagThresholds{})
+	assert.EqualError(t, err, "DB must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewPostgresReplicationLagCheck_replica(t *testing.T) {
+	rows := map[string][][]driver.Value{
+		pgInRecoveryQuery: {{true}},
+		pgReplicaLagQuery: {{2.5, int64(4096)}},
+	}
+	db := openFakeDB(&fakeDB{rows: rows})
+	defer db.Close()
+
+	check, err := NewPostgresReplicationLagCheck(checkName, db, PostgresReplicationLagThresholds{MaxLag: 5 * time.Second, MaxLagBytes: 4096})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, PostgresReplicationDetails{Replica: true, Lag: 2500 * time.Millisecond, LagBytes: 4096}, details)
+
+	rows[pgReplicaLagQuery] = [][]driver.Value{{10.0, int64(0)}}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "replica replication lag of 10s exceeds the maximum of 5s")
+
+	rows[pgReplicaLagQuery] = [][]driver.Value{{0.0, int64(8192)}}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "replica replication lag of 8192 bytes exceeds the maximum of 4096")
+
+	delete(rows, pgReplicaLagQuery)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to query the replica lag: sql: no rows in result set")
+}
+
+func TestNewPostgresReplicationLagCheck_primary(t *testing.T) {
+	rows := map[string][][]driver.Value{
+		pgInRecoveryQuery: {{false}},
+		pgStandbysLagQuery: {
+			{"replica-1", 0.5, int64(100)},
+			{"replica-2", 1.25, int64(50)},
+		},
+	}
+	db := openFakeDB(&fakeDB{rows: rows})
+	defer db.Close()
+
+	check, err := NewPostgresReplicationLagCheck(checkName, db, PostgresReplicationLagThresholds{MaxLag: time.Second})
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "standby 'replica-2' replication lag of 1.25s exceeds the maximum of 1s")
+	assert.Equal(t, PostgresReplicationDetails{
+		Lag:      1250 * time.Millisecond,
+		LagBytes: 100,
+		Standbys: []PostgresStandbyLag{
+			{ApplicationName: "replica-1", Lag: 500 * time.Millisecond, LagBytes: 100},
+			{ApplicationName: "replica-2", Lag: 1250 * time.Millisecond, LagBytes: 50},
+		},
+	}, details)
+
+	delete(rows, pgStandbysLagQuery)
+	details, err = check.Execute(context.Background())
+	assert.NoError(t, err, "no standbys")
+	assert.Equal(t, PostgresReplicationDetails{}, details)
+}
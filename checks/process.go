@@ -0,0 +1,127 @@
+package checks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ProcessCheckConfig configures a check for the liveness of a sibling process.
+// Exactly one of PID, PIDFile and ProcessName is required.
+type ProcessCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// PID is the ID of the process.
+	PID int
+	// PIDFile is the path of a file that contains the ID of the process, which is read on every execution.
+	PIDFile string
+	// ProcessName is the name of the process, i.e. its executable name (possibly truncated to 15 characters by the kernel).
+	// When several processes have the name, the longest running one is checked.
+	ProcessName string
+	// MinUptime is the optional minimum duration the process must have been running for, e.g. for detecting crash loops.
+	MinUptime time.Duration
+}
+
+// ProcessDetails are the details of a running process
+type ProcessDetails struct {
+	PID    int           `json:"pid"`
+	Name   string        `json:"name"`
+	Uptime time.Duration `json:"uptime"`
+}
+
+// processInfo is the state of a process
+type processInfo struct {
+	pid       int
+	name      string
+	zombie    bool
+	startTime time.Time
+}
+
+type processCheck struct {
+	config ProcessCheckConfig
+}
+
+// NewProcessCheck returns a Check that verifies a sibling process is running, found by its PID, PID file or name,
+// and optionally that it has been up for a minimum duration, e.g. for supervisor-style deployments.
+// The process is reported as the check details. Processes are only supported on Linux.
+func NewProcessCheck(config ProcessCheckConfig) (gosundheit.Check, error) {
+	identifiers := 0
+	for _, set := range []bool{config.PID != 0, config.PIDFile != "", config.ProcessName != ""} {
+		if set {
+			identifiers++
+		}
+	}
+	if identifiers != 1 {
+		return nil, errors.New("exactly one of PID, PIDFile and ProcessName must be set")
+	}
+	if config.PID < 0 {
+		return nil, errors.New("PID must be positive")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+
+	return &processCheck{config: config}, nil
+}
+
+func (c *processCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *processCheck) Execute(_ context.Context) (details interface{}, err error) {
+	process, err := c.findProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	processDetails := ProcessDetails{PID: process.pid, Name: process.name, Uptime: time.Since(process.startTime).Round(time.Second)}
+	if processDetails.Uptime < c.config.MinUptime {
+		return processDetails, errors.Errorf("process %d has been up for %v, less than the minimum of %v",
+			process.pid, processDetails.Uptime, c.config.MinUptime)
+	}
+	return processDetails, nil
+}
+
+func (c *processCheck) findProcess() (processInfo, error) {
+	if c.config.ProcessName != "" {
+		processes, err := findProcesses(c.config.ProcessName)
+		if err != nil {
+			return processInfo{}, err
+		}
+		var oldest *processInfo
+		for i := range processes {
+			if !processes[i].zombie && (oldest == nil || processes[i].startTime.Before(oldest.startTime)) {
+				oldest = &processes[i]
+			}
+		}
+		if oldest == nil {
+			return processInfo{}, errors.Errorf("no process named '%s' is running", c.config.ProcessName)
+		}
+		return *oldest, nil
+	}
+
+	pid := c.config.PID
+	if c.config.PIDFile != "" {
+		data, err := ioutil.ReadFile(c.config.PIDFile)
+		if err != nil {
+			return processInfo{}, errors.Errorf("failed to read PID file: %v", err)
+		}
+		if pid, err = strconv.Atoi(strings.TrimSpace(string(data))); err != nil || pid <= 0 {
+			return processInfo{}, errors.Errorf("invalid PID file '%s'", c.config.PIDFile)
+		}
+	}
+
+	process, err := readProcess(pid)
+	if os.IsNotExist(err) || (err == nil && process.zombie) {
+		return processInfo{}, errors.Errorf("process %d is not running", pid)
+	}
+	return process, err
+}
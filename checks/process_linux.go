@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// clockTicks is the USER_HZ unit of the process times in /proc, which is 100 on all the supported architectures
+const clockTicks = 100
+
+// readProcess reads the state of the process with the given ID from /proc/<pid>/stat
+func readProcess(pid int) (processInfo, error) {
+	data, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return processInfo{}, err
+	}
+	// pid (comm) state ppid ..., where comm may contain spaces and parentheses
+	stat := string(data)
+	nameStart, nameEnd := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if nameStart < 0 || nameEnd < nameStart {
+		return processInfo{}, errors.Errorf("malformed stat of process %d", pid)
+	}
+	fields := strings.Fields(stat[nameEnd+1:])
+	// the start time is the 22nd field of the stat, i.e. the 20th field from the state
+	if len(fields) < 20 {
+		return processInfo{}, errors.Errorf("malformed stat of process %d", pid)
+	}
+	startTicks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return processInfo{}, errors.Errorf("malformed stat of process %d: %v", pid, err)
+	}
+	bootTime, err := readBootTime()
+	if err != nil {
+		return processInfo{}, err
+	}
+
+	return processInfo{
+		pid:       pid,
+		name:      stat[nameStart+1 : nameEnd],
+		zombie:    fields[0] == "Z",
+		startTime: bootTime.Add(time.Duration(startTicks) * time.Second / clockTicks),
+	}, nil
+}
+
+// findProcesses returns the processes with the given name
+func findProcesses(name string) ([]processInfo, error) {
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil, errors.Errorf("failed to list processes: %v", err)
+	}
+
+	var processes []processInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		// processes may exit while listing
+		if process, err := readProcess(pid); err == nil && process.name == name {
+			processes = append(processes, process)
+		}
+	}
+	return processes, nil
+}
+
+// readBootTime reads the system boot time from /proc/stat
+func readBootTime() (time.Time, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, errors.Errorf("failed to read boot time: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, errors.Errorf("failed to parse boot time: %v", err)
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	return time.Time{}, errors.New("failed to read boot time: no btime line in /proc/stat")
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import "github.com/pkg/errors"
+
+var errProcessUnsupported = errors.New("processes are only supported on Linux")
+
+func readProcess(_ int) (processInfo, error) {
+	return processInfo{}, errProcessUnsupported
+}
+
+func findProcesses(_ string) ([]processInfo, error) {
+	return nil, errProcessUnsupported
+}
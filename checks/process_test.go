@@ -0,0 +1,79 @@
+package checks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProcessCheck_config(t *testing.T) {
+	_, err := NewProcessCheck(ProcessCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "exactly one of PID, PIDFile and ProcessName must be set")
+
+	_, err = NewProcessCheck(ProcessCheckConfig{CheckName: checkName, PID: 1, ProcessName: "init"})
+	assert.EqualError(t, err, "exactly one of PID, PIDFile and ProcessName must be set")
+
+	_, err = NewProcessCheck(ProcessCheckConfig{CheckName: checkName, PID: -1})
+	assert.EqualError(t, err, "PID must be positive")
+
+	_, err = NewProcessCheck(ProcessCheckConfig{PIDFile: "/run/nginx.pid"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewProcessCheck(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("processes are only supported on Linux")
+	}
+	self, err := readProcess(os.Getpid())
+	require.NoError(t, err)
+
+	pidFile := filepath.Join(t.TempDir(), "test.pid")
+	require.NoError(t, ioutil.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600))
+
+	for _, config := range []ProcessCheckConfig{{PID: os.Getpid()}, {PIDFile: pidFile}, {ProcessName: self.name}} {
+		config.CheckName = checkName
+		check, err := NewProcessCheck(config)
+		require.NoError(t, err)
+		assert.Equal(t, checkName, check.Name(), "check name")
+
+		details, err := check.Execute(context.Background())
+		assert.NoError(t, err, "%+v", config)
+		processDetails := details.(ProcessDetails)
+		assert.Equal(t, self.name, processDetails.Name)
+		assert.True(t, processDetails.Uptime >= 0 && processDetails.Uptime < time.Hour, "uptime %v", processDetails.Uptime)
+		if config.ProcessName == "" {
+			assert.Equal(t, os.Getpid(), processDetails.PID)
+		}
+	}
+
+	check, err := NewProcessCheck(ProcessCheckConfig{CheckName: checkName, PID: os.Getpid(), MinUptime: time.Hour})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "less than the minimum of 1h0m0s")
+	assert.Equal(t, os.Getpid(), details.(ProcessDetails).PID)
+
+	check, err = NewProcessCheck(ProcessCheckConfig{CheckName: checkName, PID: 1 << 30})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "process 1073741824 is not running")
+
+	check, err = NewProcessCheck(ProcessCheckConfig{CheckName: checkName, ProcessName: "no-such-process"})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "no process named 'no-such-process' is running")
+
+	require.NoError(t, ioutil.WriteFile(pidFile, []byte("nginx"), 0600))
+	check, err = NewProcessCheck(ProcessCheckConfig{CheckName: checkName, PIDFile: pidFile})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "invalid PID file '"+pidFile+"'")
+}
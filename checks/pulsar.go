@@ -0,0 +1,211 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// PulsarClient is the minimal Pulsar messaging API required by the Pulsar check for a produce/consume round trip,
+// so that any client library can be used, e.g. by wrapping a producer and a consumer of the Apache Pulsar Go client.
+// Receive returns the payload of the next message of the topic, which must be consumed from a subscription created
+// before the check sends its message.
+type PulsarClient interface {
+	Send(ctx context.Context, topic string, payload []byte) error
+	Receive(ctx context.Context, topic string) (payload []byte, err error)
+}
+
+// PulsarCheckConfig configures a check for an Apache Pulsar cluster.
+// At least one of AdminURL and PulsarClient is required.
+type PulsarCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// AdminURL is the base URL of the admin REST API of a broker, e.g. "http://localhost:8080".
+	// When defined, the check calls the broker health endpoint, and fetches the stats of Topic if defined.
+	AdminURL string
+	// Topic is the health topic, either fully qualified (e.g. "persistent://public/default/health") or a short name
+	// in the default namespace (e.g. "health"). Topic is required when PulsarClient is defined.
+	Topic string
+	// PulsarClient is optional; if defined, the check sends a message to Topic, and waits for it to be received.
+	PulsarClient PulsarClient
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout of the whole check, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the admin HTTP requests with arbitrary settings, e.g. add authentication headers, etc.
+	Options []RequestOption
+}
+
+// PulsarTopicStats are the stats of a topic, as returned by the Pulsar admin API
+type PulsarTopicStats struct {
+	MsgRateIn        float64                            `json:"msgRateIn"`
+	MsgRateOut       float64                            `json:"msgRateOut"`
+	MsgThroughputIn  float64                            `json:"msgThroughputIn"`
+	MsgThroughputOut float64                            `json:"msgThroughputOut"`
+	StorageSize      int64                              `json:"storageSize"`
+	BacklogSize      int64                              `json:"backlogSize"`
+	Subscriptions    map[string]PulsarSubscriptionStats `json:"subscriptions"`
+}
+
+// PulsarSubscriptionStats are the stats of a topic subscription
+type PulsarSubscriptionStats struct {
+	MsgRateOut float64 `json:"msgRateOut"`
+	MsgBacklog int64   `json:"msgBacklog"`
+}
+
+// PulsarDetails are the details of a Pulsar check
+type PulsarDetails struct {
+	// HealthLatency is the latency of the broker health endpoint, or zero when there's no AdminURL
+	HealthLatency time.Duration `json:"healthLatency,omitempty"`
+	// RoundTripLatency is the latency of the produce/consume round trip, or zero when there's no PulsarClient
+	RoundTripLatency time.Duration `json:"roundTripLatency,omitempty"`
+	// TopicStats are the stats of the health topic, when there are both AdminURL and Topic
+	TopicStats *PulsarTopicStats `json:"topicStats,omitempty"`
+}
+
+type pulsarCheck struct {
+	config    PulsarCheckConfig
+	healthURL string
+	statsURL  string
+}
+
+// NewPulsarCheck returns a Check that verifies the connectivity to an Apache Pulsar cluster, using the broker health endpoint
+// of the admin API, and/or a produce/consume round trip on a health topic through the given PulsarClient.
+// The latencies, and the stats of the health topic, are reported as the check details.
+func NewPulsarCheck(config PulsarCheckConfig) (gosundheit.Check, error) {
+	if config.AdminURL == "" && config.PulsarClient == nil {
+		return nil, errors.New("AdminURL or PulsarClient must be defined")
+	}
+	if config.PulsarClient != nil && config.Topic == "" {
+		return nil, errors.New("Topic must not be empty when PulsarClient is defined")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	check := &pulsarCheck{config: config}
+	if config.AdminURL != "" {
+		adminURL := strings.TrimSuffix(config.AdminURL, "/")
+		check.healthURL = adminURL + "/admin/v2/brokers/health"
+		if config.Topic != "" {
+			topicPath, err := pulsarTopicPath(config.Topic)
+			if err != nil {
+				return nil, err
+			}
+			check.statsURL = adminURL + "/admin/v2/" + topicPath + "/stats"
+		}
+	}
+	return check, nil
+}
+
+func (c *pulsarCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *pulsarCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var pulsarDetails PulsarDetails
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	if c.healthURL != "" {
+		start := time.Now()
+		err := c.brokerHealth(ctx)
+		pulsarDetails.HealthLatency = time.Since(start)
+		if err != nil {
+			return pulsarDetails, err
+		}
+	}
+
+	if c.statsURL != "" {
+		var stats PulsarTopicStats
+		if err := fetchJSON(ctx, c.config.Client, c.statsURL, c.config.Options, &stats); err != nil {
+			return pulsarDetails, errors.Errorf("failed to fetch the stats of topic '%s': %v", c.config.Topic, err)
+		}
+		pulsarDetails.TopicStats = &stats
+	}
+
+	if c.config.PulsarClient != nil {
+		start := time.Now()
+		err := c.roundTrip(ctx)
+		pulsarDetails.RoundTripLatency = time.Since(start)
+		if err != nil {
+			return pulsarDetails, err
+		}
+	}
+	return pulsarDetails, nil
+}
+
+// brokerHealth calls the broker health endpoint, which responds with "ok" after the broker completed its own round trip
+func (c *pulsarCheck) brokerHealth(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.healthURL, nil)
+	if err != nil {
+		return errors.Errorf("unable to create check HTTP request: %v", err)
+	}
+	configureHTTPOptions(req, c.config.Options)
+
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return errors.Errorf("fail to execute 'GET' request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code: '%v' expected: '%v'", resp.StatusCode, http.StatusOK)
+	}
+	return nil
+}
+
+// roundTrip sends a message to the health topic, and receives messages until it arrives
+func (c *pulsarCheck) roundTrip(ctx context.Context) error {
+	payload := []byte(c.config.CheckName + " " + strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := c.config.PulsarClient.Send(ctx, c.config.Topic, payload); err != nil {
+		return errors.Errorf("failed to send to topic '%s': %v", c.config.Topic, err)
+	}
+
+	// other messages may have been sent to the topic by previous or concurrent checks
+	for {
+		received, err := c.config.PulsarClient.Receive(ctx, c.config.Topic)
+		if err != nil {
+			return errors.Errorf("failed to receive from topic '%s': %v", c.config.Topic, err)
+		}
+		if bytes.Equal(received, payload) {
+			return nil
+		}
+	}
+}
+
+// pulsarTopicPath returns the admin API path of a topic, e.g. "persistent/public/default/health"
+func pulsarTopicPath(topic string) (string, error) {
+	domain, name := "persistent", topic
+	if i := strings.Index(topic, "://"); i >= 0 {
+		domain, name = topic[:i], topic[i+3:]
+	} else if !strings.Contains(topic, "/") {
+		name = "public/default/" + topic
+	}
+
+	parts := strings.SplitN(name, "/", 3)
+	if (domain != "persistent" && domain != "non-persistent") || len(parts) != 3 {
+		return "", errors.Errorf("invalid topic '%s'", topic)
+	}
+	for i, part := range parts {
+		if part == "" {
+			return "", errors.Errorf("invalid topic '%s'", topic)
+		}
+		parts[i] = url.PathEscape(part)
+	}
+	return domain + "/" + strings.Join(parts, "/"), nil
+}
@@ -0,0 +1,130 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPulsarClient struct {
+	messages chan []byte
+	sendErr  error
+	drop     bool
+}
+
+func newMockPulsarClient() *mockPulsarClient {
+	client := &mockPulsarClient{messages: make(chan []byte, 10)}
+	// a message left over from a previous check
+	client.messages <- []byte("stale")
+	return client
+}
+
+func (c *mockPulsarClient) Send(ctx context.Context, topic string, payload []byte) error {
+	if c.sendErr == nil && !c.drop {
+		c.messages <- payload
+	}
+	return c.sendErr
+}
+
+func (c *mockPulsarClient) Receive(ctx context.Context, topic string) ([]byte, error) {
+	select {
+	case payload := <-c.messages:
+		return payload, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestNewPulsarCheck_config(t *testing.T) {
+	_, err := NewPulsarCheck(PulsarCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "AdminURL or PulsarClient must be defined")
+
+	_, err = NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, PulsarClient: newMockPulsarClient()})
+	assert.EqualError(t, err, "Topic must not be empty when PulsarClient is defined")
+
+	_, err = NewPulsarCheck(PulsarCheckConfig{AdminURL: "http://localhost:8080"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, AdminURL: "http://localhost:8080", Topic: "public/health"})
+	assert.EqualError(t, err, "invalid topic 'public/health'")
+
+	_, err = NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, AdminURL: "http://localhost:8080", Topic: "transient://public/default/health"})
+	assert.EqualError(t, err, "invalid topic 'transient://public/default/health'")
+
+	check, err := NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, AdminURL: "http://localhost:8080/", Topic: "health"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	assert.Equal(t, "http://localhost:8080/admin/v2/persistent/public/default/health/stats", check.(*pulsarCheck).statsURL)
+
+	check, err = NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, AdminURL: "http://localhost:8080", Topic: "non-persistent://tenant/ns/health"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/admin/v2/non-persistent/tenant/ns/health/stats", check.(*pulsarCheck).statsURL)
+}
+
+func TestNewPulsarCheck_admin(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/admin/v2/brokers/health":
+			if !healthy {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = rw.Write([]byte("ok"))
+		case "/admin/v2/persistent/public/default/health/stats":
+			_, _ = rw.Write([]byte(`{"msgRateIn": 1.5, "msgRateOut": 3, "storageSize": 1024, "backlogSize": 10,
+				"subscriptions": {"health-check": {"msgRateOut": 1.5, "msgBacklog": 2}}}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	check, err := NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, AdminURL: server.URL, Topic: "health"})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	pulsarDetails := details.(PulsarDetails)
+	assert.True(t, pulsarDetails.HealthLatency > 0, "health latency")
+	assert.Zero(t, pulsarDetails.RoundTripLatency, "no round trip")
+	assert.Equal(t, &PulsarTopicStats{
+		MsgRateIn:     1.5,
+		MsgRateOut:    3,
+		StorageSize:   1024,
+		BacklogSize:   10,
+		Subscriptions: map[string]PulsarSubscriptionStats{"health-check": {MsgRateOut: 1.5, MsgBacklog: 2}},
+	}, pulsarDetails.TopicStats)
+
+	check, err = NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, AdminURL: server.URL, Topic: "missing"})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to fetch the stats of topic 'missing': unexpected status code: '404' expected: '[200]'")
+
+	healthy = false
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "unexpected status code: '500' expected: '200'")
+}
+
+func TestNewPulsarCheck_roundTrip(t *testing.T) {
+	client := newMockPulsarClient()
+	check, err := NewPulsarCheck(PulsarCheckConfig{CheckName: checkName, PulsarClient: client, Topic: "health", Timeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, details.(PulsarDetails).RoundTripLatency > 0, "round trip latency")
+	assert.Nil(t, details.(PulsarDetails).TopicStats, "no topic stats")
+
+	client.drop = true
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to receive from topic 'health': context deadline exceeded")
+
+	client.sendErr = errors.New("producer is closed")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to send to topic 'health': producer is closed")
+}
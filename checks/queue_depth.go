@@ -0,0 +1,43 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// QueueDepthDetails are the details of a queue depth check
+type QueueDepthDetails struct {
+	Depth    int64 `json:"depth"`
+	MaxDepth int64 `json:"maxDepth"`
+}
+
+// NewQueueDepthCheck returns a Check that measures the backlog size of a queue using the given function, which can query any queue system,
+// and fails when it exceeds maxDepth. The measured depth and the threshold are reported as the check details.
+func NewQueueDepthCheck(name string, depth func(ctx context.Context) (int64, error), maxDepth int64) (gosundheit.Check, error) {
+	if depth == nil {
+		return nil, errors.New("depth function must not be nil")
+	}
+	if maxDepth < 0 {
+		return nil, errors.New("maxDepth must not be negative")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &CustomCheck{
+		CheckName: name,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			queueDetails := QueueDepthDetails{MaxDepth: maxDepth}
+			if queueDetails.Depth, err = depth(ctx); err != nil {
+				return queueDetails, errors.Errorf("failed to get queue depth: %v", err)
+			}
+			if queueDetails.Depth > maxDepth {
+				return queueDetails, errors.Errorf("queue depth %d exceeds the maximum of %d", queueDetails.Depth, maxDepth)
+			}
+			return queueDetails, nil
+		},
+	}, nil
+}
@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQueueDepthCheck_config(t *testing.T) {
+	_, err := NewQueueDepthCheck(checkName, nil, 100)
+	assert.EqualError(t, err, "depth function must not be nil")
+
+	depth := func(context.Context) (int64, error) { return 0, nil }
+	_, err = NewQueueDepthCheck(checkName, depth, -1)
+	assert.EqualError(t, err, "maxDepth must not be negative")
+
+	_, err = NewQueueDepthCheck("", depth, 100)
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewQueueDepthCheck(t *testing.T) {
+	var depth int64
+	var depthErr error
+	check, err := NewQueueDepthCheck(checkName, func(context.Context) (int64, error) { return depth, depthErr }, 100)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	for _, d := range []int64{0, 50, 100} {
+		depth = d
+		details, err := check.Execute(context.Background())
+		assert.NoError(t, err, "depth %d", d)
+		assert.Equal(t, QueueDepthDetails{Depth: d, MaxDepth: 100}, details)
+	}
+
+	depth = 101
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "queue depth 101 exceeds the maximum of 100")
+	assert.Equal(t, QueueDepthDetails{Depth: 101, MaxDepth: 100}, details)
+
+	depthErr = errors.New("queue not found")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to get queue depth: queue not found")
+}
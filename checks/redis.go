@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// RedisDoer executes a Redis command, and returns its reply. It is the minimal Redis client API required by the Redis check,
+// so that any client library can be used, e.g. using go-redis:
+//
+//	checks.RedisDoFunc(func(ctx context.Context, args ...interface{}) (interface{}, error) {
+//		return client.Do(ctx, args...).Result()
+//	})
+type RedisDoer interface {
+	Do(ctx context.Context, args ...interface{}) (reply interface{}, err error)
+}
+
+// RedisDoFunc type is an adapter to allow the use of ordinary functions as RedisDoers.
+type RedisDoFunc func(ctx context.Context, args ...interface{}) (reply interface{}, err error)
+
+// Do calls f(ctx, args...).
+func (f RedisDoFunc) Do(ctx context.Context, args ...interface{}) (reply interface{}, err error) {
+	return f(ctx, args...)
+}
+
+// RedisCheckOption configures a Redis check
+type RedisCheckOption func(c *redisCheck)
+
+// WithRoundTrip sets the Redis check to also SET, GET and DEL the given key after a successful PING.
+// The key is set to expire after a minute, in case the DEL fails.
+func WithRoundTrip(key string) RedisCheckOption {
+	return func(c *redisCheck) {
+		c.roundTripKey = key
+	}
+}
+
+// RedisDetails are the details of a Redis check
+type RedisDetails struct {
+	// PingLatency is the duration of the PING command
+	PingLatency time.Duration `json:"pingLatency"`
+	// RoundTripLatency is the total duration of the SET, GET and DEL commands, or zero when there's no round trip
+	RoundTripLatency time.Duration `json:"roundTripLatency,omitempty"`
+}
+
+type redisCheck struct {
+	name         string
+	client       RedisDoer
+	roundTripKey string
+}
+
+// NewRedisCheck returns a Check that PINGs Redis using the given client, and optionally performs a round trip (see WithRoundTrip).
+// The commands latencies are reported as the check details.
+func NewRedisCheck(name string, client RedisDoer, opts ...RedisCheckOption) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &redisCheck{name: name, client: client}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *redisCheck) Name() string {
+	return c.name
+}
+
+func (c *redisCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var redisDetails RedisDetails
+
+	start := time.Now()
+	reply, err := c.client.Do(ctx, "PING")
+	redisDetails.PingLatency = time.Since(start)
+	if err != nil {
+		return redisDetails, errors.Wrap(err, "PING failed")
+	}
+	if s := replyString(reply); s != "PONG" {
+		return redisDetails, errors.Errorf("unexpected PING reply: '%s'", s)
+	}
+
+	if c.roundTripKey == "" {
+		return redisDetails, nil
+	}
+
+	start = time.Now()
+	err = c.roundTrip(ctx)
+	redisDetails.RoundTripLatency = time.Since(start)
+	return redisDetails, err
+}
+
+func (c *redisCheck) roundTrip(ctx context.Context) error {
+	value := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if _, err := c.client.Do(ctx, "SET", c.roundTripKey, value, "PX", int64(time.Minute/time.Millisecond)); err != nil {
+		return errors.Wrap(err, "SET failed")
+	}
+
+	reply, err := c.client.Do(ctx, "GET", c.roundTripKey)
+	if err != nil {
+		return errors.Wrap(err, "GET failed")
+	}
+	if s := replyString(reply); s != value {
+		return errors.Errorf("GET returned '%s', expected: '%s'", s, value)
+	}
+
+	if _, err := c.client.Do(ctx, "DEL", c.roundTripKey); err != nil {
+		return errors.Wrap(err, "DEL failed")
+	}
+	return nil
+}
+
+// replyString returns the string representation of a Redis reply, which client libraries return either as string or []byte
+func replyString(reply interface{}) string {
+	switch r := reply.(type) {
+	case string:
+		return r
+	case []byte:
+		return string(r)
+	default:
+		return fmt.Sprint(r)
+	}
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is an in memory RedisDoer, that fails the commands in failing
+type fakeRedis struct {
+	data     map[string]interface{}
+	failing  map[string]bool
+	commands []string
+}
+
+func newFakeRedis(failing ...string) *fakeRedis {
+	r := &fakeRedis{data: make(map[string]interface{}), failing: make(map[string]bool)}
+	for _, cmd := range failing {
+		r.failing[cmd] = true
+	}
+	return r
+}
+
+func (r *fakeRedis) Do(_ context.Context, args ...interface{}) (interface{}, error) {
+	cmd := args[0].(string)
+	r.commands = append(r.commands, cmd)
+	if r.failing[cmd] {
+		return nil, errors.New("connection reset")
+	}
+
+	switch cmd {
+	case "PING":
+		return "PONG", nil
+	case "SET":
+		r.data[args[1].(string)] = args[2]
+		return "OK", nil
+	case "GET":
+		// client libraries may return bulk strings as []byte
+		return []byte(fmt.Sprint(r.data[args[1].(string)])), nil
+	case "DEL":
+		delete(r.data, args[1].(string))
+		return int64(1), nil
+	}
+	return nil, errors.Errorf("unknown command '%s'", cmd)
+}
+
+func TestNewRedisCheck_nilClient(t *testing.T) {
+	check, err := NewRedisCheck(checkName, nil)
+	assert.EqualError(t, err, "client must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewRedisCheck(t *testing.T) {
+	redis := newFakeRedis()
+	check, err := NewRedisCheck(checkName, redis)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.IsType(t, RedisDetails{}, details)
+	assert.Zero(t, details.(RedisDetails).RoundTripLatency, "no round trip")
+	assert.Equal(t, []string{"PING"}, redis.commands)
+
+	check, err = NewRedisCheck(checkName, newFakeRedis("PING"))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "PING failed: connection reset")
+
+	check, err = NewRedisCheck(checkName, RedisDoFunc(func(_ context.Context, _ ...interface{}) (interface{}, error) {
+		return "LOADING", nil
+	}))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "unexpected PING reply: 'LOADING'")
+}
+
+func TestNewRedisCheck_roundTrip(t *testing.T) {
+	redis := newFakeRedis()
+	check, err := NewRedisCheck(checkName, redis, WithRoundTrip("health"))
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PING", "SET", "GET", "DEL"}, redis.commands)
+	assert.Empty(t, redis.data, "key is deleted")
+
+	check, err = NewRedisCheck(checkName, newFakeRedis("GET"), WithRoundTrip("health"))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "GET failed: connection reset")
+}
@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// redisClusterSlots is the number of hash slots of a Redis Cluster
+const redisClusterSlots = 16384
+
+// RedisSentinelDetails are the details of a Redis Sentinel check
+type RedisSentinelDetails struct {
+	// Master is the address of the master, as known by the Sentinel
+	Master string `json:"master"`
+	// Flags are the master flags, e.g. "master", "s_down", "failover_in_progress"
+	Flags          []string `json:"flags"`
+	Replicas       int      `json:"replicas"`
+	OtherSentinels int      `json:"otherSentinels"`
+	Quorum         int      `json:"quorum"`
+	// QuorumStatus is the reply of SENTINEL CKQUORUM
+	QuorumStatus string `json:"quorumStatus,omitempty"`
+}
+
+// RedisClusterDetails are the details of a Redis Cluster check, as reported by CLUSTER INFO
+type RedisClusterDetails struct {
+	State         string `json:"state"`
+	SlotsAssigned int    `json:"slotsAssigned"`
+	SlotsOK       int    `json:"slotsOk"`
+	SlotsPFail    int    `json:"slotsPFail"`
+	SlotsFail     int    `json:"slotsFail"`
+	KnownNodes    int    `json:"knownNodes"`
+	Size          int    `json:"size"`
+}
+
+type redisSentinelCheck struct {
+	name       string
+	client     RedisDoer
+	masterName string
+}
+
+// NewRedisSentinelCheck returns a Check that asks a Redis Sentinel, using the given client, about the master of the named service.
+// The check fails when no master is known, when the master is down or a failover is in progress, or when the Sentinels
+// cannot reach the quorum required to authorize a failover (see SENTINEL CKQUORUM). The master state is reported as the check details.
+func NewRedisSentinelCheck(name string, client RedisDoer, masterName string) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if masterName == "" {
+		return nil, errors.New("master name must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &redisSentinelCheck{name: name, client: client, masterName: masterName}, nil
+}
+
+func (c *redisSentinelCheck) Name() string {
+	return c.name
+}
+
+func (c *redisSentinelCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	reply, err := c.client.Do(ctx, "SENTINEL", "MASTER", c.masterName)
+	if err != nil {
+		return nil, errors.Wrap(err, "SENTINEL MASTER failed")
+	}
+	master := replyMap(reply)
+	if master["ip"] == "" {
+		return nil, errors.Errorf("no master is known for '%s'", c.masterName)
+	}
+	atoi := func(field string) int {
+		i, _ := strconv.Atoi(master[field])
+		return i
+	}
+	sentinelDetails := RedisSentinelDetails{
+		Master:         master["ip"] + ":" + master["port"],
+		Flags:          strings.Split(master["flags"], ","),
+		Replicas:       atoi("num-slaves"),
+		OtherSentinels: atoi("num-other-sentinels"),
+		Quorum:         atoi("quorum"),
+	}
+
+	for _, flag := range sentinelDetails.Flags {
+		switch flag {
+		case "failover_in_progress", "promoted":
+			return sentinelDetails, errors.Errorf("a failover of '%s' is in progress", c.masterName)
+		case "s_down", "o_down":
+			return sentinelDetails, errors.Errorf("master '%s' at %s is down (%s)", c.masterName, sentinelDetails.Master, flag)
+		}
+	}
+
+	reply, err = c.client.Do(ctx, "SENTINEL", "CKQUORUM", c.masterName)
+	if err != nil {
+		return sentinelDetails, errors.Wrap(err, "quorum check failed")
+	}
+	sentinelDetails.QuorumStatus = replyString(reply)
+	return sentinelDetails, nil
+}
+
+type redisClusterCheck struct {
+	name   string
+	client RedisDoer
+}
+
+// NewRedisClusterCheck returns a Check that reads the state of a Redis Cluster using CLUSTER INFO, and fails unless
+// the cluster state is ok and all of its hash slots are assigned to nodes which are not failing. The cluster info is reported as the check details.
+func NewRedisClusterCheck(name string, client RedisDoer) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &redisClusterCheck{name: name, client: client}, nil
+}
+
+func (c *redisClusterCheck) Name() string {
+	return c.name
+}
+
+func (c *redisClusterCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	reply, err := c.client.Do(ctx, "CLUSTER", "INFO")
+	if err != nil {
+		return nil, errors.Wrap(err, "CLUSTER INFO failed")
+	}
+
+	info := make(map[string]string)
+	for _, line := range strings.Split(replyString(reply), "\n") {
+		if parts := strings.SplitN(strings.TrimSpace(line), ":", 2); len(parts) == 2 {
+			info[parts[0]] = parts[1]
+		}
+	}
+	atoi := func(field string) int {
+		i, _ := strconv.Atoi(info[field])
+		return i
+	}
+	clusterDetails := RedisClusterDetails{
+		State:         info["cluster_state"],
+		SlotsAssigned: atoi("cluster_slots_assigned"),
+		SlotsOK:       atoi("cluster_slots_ok"),
+		SlotsPFail:    atoi("cluster_slots_pfail"),
+		SlotsFail:     atoi("cluster_slots_fail"),
+		KnownNodes:    atoi("cluster_known_nodes"),
+		Size:          atoi("cluster_size"),
+	}
+
+	switch {
+	case clusterDetails.SlotsAssigned < redisClusterSlots:
+		return clusterDetails, errors.Errorf("%d of %d slots are not assigned", redisClusterSlots-clusterDetails.SlotsAssigned, redisClusterSlots)
+	case clusterDetails.SlotsFail > 0:
+		return clusterDetails, errors.Errorf("%d slots are served by failing nodes", clusterDetails.SlotsFail)
+	case clusterDetails.State != "ok":
+		return clusterDetails, errors.Errorf("cluster state is %s", clusterDetails.State)
+	}
+	return clusterDetails, nil
+}
+
+// replyMap returns the fields of a Redis reply, which is either a flat array of field/value pairs (RESP2), or a map (RESP3)
+func replyMap(reply interface{}) map[string]string {
+	fields := make(map[string]string)
+	switch r := reply.(type) {
+	case []interface{}:
+		for i := 0; i+1 < len(r); i += 2 {
+			fields[replyString(r[i])] = replyString(r[i+1])
+		}
+	case map[interface{}]interface{}:
+		for field, value := range r {
+			fields[replyString(field)] = replyString(value)
+		}
+	case map[string]interface{}:
+		for field, value := range r {
+			fields[field] = replyString(value)
+		}
+	case map[string]string:
+		return r
+	}
+	return fields
+}
@@ -0,0 +1,117 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisSentinelCheck_config(t *testing.T) {
+	_, err := NewRedisSentinelCheck(checkName, nil, "mymaster")
+	assert.EqualError(t, err, "client must not be nil")
+
+	_, err = NewRedisSentinelCheck(checkName, newFakeRedis(), "")
+	assert.EqualError(t, err, "master name must not be empty")
+
+	_, err = NewRedisSentinelCheck("", newFakeRedis(), "mymaster")
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewRedisSentinelCheck(t *testing.T) {
+	var master interface{}
+	quorumErr := error(nil)
+	sentinel := RedisDoFunc(func(_ context.Context, args ...interface{}) (interface{}, error) {
+		switch args[1] {
+		case "MASTER":
+			if master == nil {
+				return nil, errors.New("ERR No such master with that name")
+			}
+			return master, nil
+		case "CKQUORUM":
+			if quorumErr != nil {
+				return nil, quorumErr
+			}
+			return "OK 3 usable Sentinels. Quorum and failover authorization can be reached", nil
+		}
+		return nil, errors.New("unknown command")
+	})
+
+	check, err := NewRedisSentinelCheck(checkName, sentinel, "mymaster")
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "SENTINEL MASTER failed: ERR No such master with that name")
+
+	master = []interface{}{}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "no master is known for 'mymaster'")
+
+	master = []interface{}{
+		"name", "mymaster", "ip", []byte("10.0.0.1"), "port", "6379", "flags", "master",
+		"num-slaves", "2", "num-other-sentinels", "2", "quorum", "2",
+	}
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RedisSentinelDetails{
+		Master:         "10.0.0.1:6379",
+		Flags:          []string{"master"},
+		Replicas:       2,
+		OtherSentinels: 2,
+		Quorum:         2,
+		QuorumStatus:   "OK 3 usable Sentinels. Quorum and failover authorization can be reached",
+	}, details)
+
+	quorumErr = errors.New("NOQUORUM 1 usable Sentinels. Not enough available Sentinels to reach the specified quorum for this master")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "quorum check failed: NOQUORUM 1 usable Sentinels. Not enough available Sentinels to reach the specified quorum for this master")
+
+	master = map[interface{}]interface{}{"ip": "10.0.0.1", "port": "6379", "flags": "master,failover_in_progress"}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "a failover of 'mymaster' is in progress")
+
+	master = map[string]interface{}{"ip": "10.0.0.1", "port": "6379", "flags": "master,s_down,o_down"}
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "master 'mymaster' at 10.0.0.1:6379 is down (s_down)")
+}
+
+func TestNewRedisClusterCheck(t *testing.T) {
+	_, err := NewRedisClusterCheck(checkName, nil)
+	assert.EqualError(t, err, "client must not be nil")
+
+	info := "cluster_state:ok\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\ncluster_slots_pfail:0\r\n" +
+		"cluster_slots_fail:0\r\ncluster_known_nodes:6\r\ncluster_size:3\r\n"
+	client := RedisDoFunc(func(_ context.Context, args ...interface{}) (interface{}, error) {
+		if info == "" {
+			return nil, errors.New("ERR This instance has cluster support disabled")
+		}
+		return []byte(info), nil
+	})
+
+	check, err := NewRedisClusterCheck(checkName, client)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RedisClusterDetails{State: "ok", SlotsAssigned: 16384, SlotsOK: 16384, KnownNodes: 6, Size: 3}, details)
+
+	info = "cluster_state:fail\r\ncluster_slots_assigned:16000\r\ncluster_slots_ok:16000\r\n"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "384 of 16384 slots are not assigned")
+
+	info = "cluster_state:fail\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:10923\r\ncluster_slots_fail:5461\r\n"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "5461 slots are served by failing nodes")
+
+	info = "cluster_state:fail\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\n"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "cluster state is fail")
+
+	info = ""
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "CLUSTER INFO failed: ERR This instance has cluster support disabled")
+}
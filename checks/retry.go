@@ -0,0 +1,66 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// RetryDetails are the details reported by a check wrapped using WithRetries
+type RetryDetails struct {
+	// Retries is the number of times the check was retried after its first attempt
+	Retries int `json:"retries"`
+	// LastError is the error message of the last failed attempt, if any
+	LastError string `json:"lastError,omitempty"`
+	// Details are the details of the last attempt
+	Details interface{} `json:"details,omitempty"`
+}
+
+type retryCheck struct {
+	check    gosundheit.Check
+	attempts int
+	delay    time.Duration
+}
+
+// WithRetries returns a gosundheit.Check that retries the given check up to `attempts` times in total,
+// waiting `delay` between attempts, before reporting a failure.
+// All the attempts are made within the same execution, and are bound by the execution context.
+func WithRetries(check gosundheit.Check, attempts int, delay time.Duration) gosundheit.Check {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &retryCheck{
+		check:    check,
+		attempts: attempts,
+		delay:    delay,
+	}
+}
+
+func (c *retryCheck) Name() string {
+	return c.check.Name()
+}
+
+func (c *retryCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	retryDetails := RetryDetails{}
+	for attempt := 1; ; attempt++ {
+		retryDetails.Details, err = c.check.Execute(ctx)
+		if err == nil || attempt == c.attempts {
+			break
+		}
+
+		retryDetails.LastError = err.Error()
+		retryDetails.Retries++
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			return retryDetails, err
+		}
+	}
+
+	if err != nil {
+		retryDetails.LastError = err.Error()
+	}
+	return retryDetails, err
+}
@@ -0,0 +1,54 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRetries(t *testing.T) {
+	chk := WithRetries(flakyCheck(2), 3, time.Millisecond)
+	assert.Equal(t, checkName, chk.Name(), "check name")
+
+	details, err := chk.Execute(context.Background())
+	assert.NoError(t, err, "check should pass on the third attempt")
+	assert.Equal(t, RetryDetails{Retries: 2, LastError: "attempt 2 failed", Details: "attempt 3"}, details)
+}
+
+func TestWithRetries_exhausted(t *testing.T) {
+	details, err := WithRetries(flakyCheck(5), 3, time.Millisecond).Execute(context.Background())
+	assert.EqualError(t, err, "attempt 3 failed")
+	assert.Equal(t, RetryDetails{Retries: 2, LastError: "attempt 3 failed", Details: "attempt 3"}, details)
+
+	details, err = WithRetries(flakyCheck(5), 0, time.Millisecond).Execute(context.Background())
+	assert.EqualError(t, err, "attempt 1 failed", "at least one attempt is made")
+	assert.Equal(t, RetryDetails{Retries: 0, LastError: "attempt 1 failed", Details: "attempt 1"}, details)
+}
+
+func TestWithRetries_contextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	details, err := WithRetries(flakyCheck(5), 3, time.Minute).Execute(ctx)
+	assert.EqualError(t, err, "attempt 1 failed")
+	assert.Equal(t, RetryDetails{Retries: 1, LastError: "attempt 1 failed", Details: "attempt 1"}, details)
+}
+
+// flakyCheck returns a check that fails on its first `failures` executions.
+func flakyCheck(failures int) *CustomCheck {
+	attempt := 0
+	return &CustomCheck{
+		CheckName: checkName,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			attempt++
+			details = fmt.Sprintf("attempt %d", attempt)
+			if attempt <= failures {
+				return details, fmt.Errorf("attempt %d failed", attempt)
+			}
+			return details, nil
+		},
+	}
+}
@@ -0,0 +1,234 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// RevocationCheckConfig configures a check for the revocation status of the certificates presented by a server.
+type RevocationCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is the host:port address of the server that presents the certificates.
+	// Address is required
+	Address string
+	// TLSConfig is optional, and configures the TLS handshake with the server, e.g. custom RootCAs or ServerName.
+	// If undefined, the server name is taken from Address, and the certificates are verified using the system roots.
+	TLSConfig *tls.Config
+	// Client is optional, and is used for fetching OCSP responses and CRLs; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the TLS handshake and for each OCSP or CRL request, defaults to "1s".
+	Timeout time.Duration
+}
+
+// Certificate revocation statuses
+const (
+	RevocationStatusGood    = "good"
+	RevocationStatusRevoked = "revoked"
+	RevocationStatusUnknown = "unknown"
+)
+
+// RevocationDetails are the details of a certificate whose revocation status was checked
+type RevocationDetails struct {
+	Subject string `json:"subject"`
+	// Status is one of RevocationStatusGood, RevocationStatusRevoked and RevocationStatusUnknown
+	Status string `json:"status"`
+	// Source is where the status was obtained from: "stapled OCSP", "OCSP" or "CRL"
+	Source string `json:"source,omitempty"`
+	// RevokedAt is the revocation time of a revoked certificate
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+type revocationCheck struct {
+	config RevocationCheckConfig
+}
+
+// NewRevocationCheck returns a Check that performs a TLS handshake with a server, and verifies the revocation status of
+// each certificate in its chain, except for the (self-signed) root. The status is taken from the OCSP response stapled by the server (for the leaf),
+// from the OCSP responder of the certificate, or from its CRL distribution point, in that order of preference.
+// The check fails when any certificate is revoked, or when its status is unknown or cannot be obtained.
+// The status of each certificate is reported as the check details, starting with the leaf certificate.
+func NewRevocationCheck(config RevocationCheckConfig) (gosundheit.Check, error) {
+	if config.Address == "" {
+		return nil, errors.New("Address must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &revocationCheck{config: config}, nil
+}
+
+func (c *revocationCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *revocationCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	state, err := tlsHandshake(ctx, c.config.Address, c.config.TLSConfig, c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	chain := state.PeerCertificates
+	if len(state.VerifiedChains) > 0 {
+		chain = state.VerifiedChains[0]
+	}
+
+	revocationDetails := make([]RevocationDetails, 0, len(chain))
+	for i, cert := range chain {
+		if isSelfSigned(cert) {
+			break // the root is trusted as is
+		}
+		if i == len(chain)-1 {
+			return revocationDetails, errors.Errorf("issuer of certificate '%s' was not presented", cert.Subject)
+		}
+
+		var stapled []byte
+		if i == 0 {
+			stapled = state.OCSPResponse
+		}
+		certDetails, err := c.revocationStatus(ctx, cert, chain[i+1], stapled)
+		revocationDetails = append(revocationDetails, certDetails)
+		if err != nil {
+			return revocationDetails, err
+		}
+		switch certDetails.Status {
+		case RevocationStatusRevoked:
+			return revocationDetails, errors.Errorf("certificate '%s' was revoked at %s", cert.Subject, certDetails.RevokedAt.Format(time.RFC3339))
+		case RevocationStatusUnknown:
+			return revocationDetails, errors.Errorf("revocation status of certificate '%s' is unknown", cert.Subject)
+		}
+	}
+	return revocationDetails, nil
+}
+
+// revocationStatus returns the revocation status of the given certificate, preferring the stapled OCSP response if any
+func (c *revocationCheck) revocationStatus(ctx context.Context, cert, issuer *x509.Certificate, stapled []byte) (RevocationDetails, error) {
+	details := RevocationDetails{Subject: cert.Subject.String(), Status: RevocationStatusUnknown}
+
+	switch {
+	case len(stapled) > 0:
+		details.Source = "stapled OCSP"
+		return details, ocspStatus(stapled, cert, issuer, &details)
+	case len(cert.OCSPServer) > 0:
+		details.Source = "OCSP"
+		response, err := c.fetchOCSP(ctx, cert.OCSPServer[0], cert, issuer)
+		if err != nil {
+			return details, err
+		}
+		return details, ocspStatus(response, cert, issuer, &details)
+	case len(cert.CRLDistributionPoints) > 0:
+		details.Source = "CRL"
+		return details, c.crlStatus(ctx, cert.CRLDistributionPoints[0], cert, issuer, &details)
+	default:
+		return details, errors.Errorf("certificate '%s' has no OCSP responder or CRL distribution point", cert.Subject)
+	}
+}
+
+func (c *revocationCheck) fetchOCSP(ctx context.Context, server string, cert, issuer *x509.Certificate) ([]byte, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, errors.Errorf("failed to create OCSP request: %v", err)
+	}
+	return c.fetch(ctx, http.MethodPost, server, bytes.NewReader(request), "application/ocsp-request")
+}
+
+func (c *revocationCheck) crlStatus(ctx context.Context, url string, cert, issuer *x509.Certificate, details *RevocationDetails) error {
+	data, err := c.fetch(ctx, http.MethodGet, url, nil, "")
+	if err != nil {
+		return err
+	}
+	// x509.ParseRevocationList requires Go 1.19
+	crl, err := x509.ParseCRL(data)
+	if err != nil {
+		return errors.Errorf("failed to parse CRL from '%s': %v", url, err)
+	}
+	if err := issuer.CheckCRLSignature(crl); err != nil {
+		return errors.Errorf("invalid signature of CRL from '%s': %v", url, err)
+	}
+	if crl.HasExpired(time.Now()) {
+		return errors.Errorf("CRL from '%s' expired at %s", url, crl.TBSCertList.NextUpdate.Format(time.RFC3339))
+	}
+
+	details.Status = RevocationStatusGood
+	for _, revoked := range crl.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			revokedAt := revoked.RevocationTime
+			details.Status, details.RevokedAt = RevocationStatusRevoked, &revokedAt
+			break
+		}
+	}
+	return nil
+}
+
+// fetch executes an HTTP request to the given URL, and returns the response body
+func (c *revocationCheck) fetch(ctx context.Context, method, url string, body io.Reader, contentType string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, errors.Errorf("unable to create request to '%s': %v", url, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return nil, errors.Errorf("fail to execute '%v' request: %v", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code of '%s': '%v' expected: '%v'", url, resp.StatusCode, http.StatusOK)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Errorf("failed to read response body of '%s': %v", url, err)
+	}
+	return data, nil
+}
+
+// ocspStatus parses and verifies the given OCSP response, and sets the details status accordingly
+func ocspStatus(response []byte, cert, issuer *x509.Certificate, details *RevocationDetails) error {
+	resp, err := ocsp.ParseResponseForCert(response, cert, issuer)
+	if err != nil {
+		return errors.Errorf("invalid OCSP response for certificate '%s': %v", cert.Subject, err)
+	}
+	if !resp.NextUpdate.IsZero() && time.Now().After(resp.NextUpdate) {
+		return errors.Errorf("OCSP response for certificate '%s' expired at %s", cert.Subject, resp.NextUpdate.Format(time.RFC3339))
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		details.Status = RevocationStatusGood
+	case ocsp.Revoked:
+		revokedAt := resp.RevokedAt
+		details.Status, details.RevokedAt = RevocationStatusRevoked, &revokedAt
+	default:
+		details.Status = RevocationStatusUnknown
+	}
+	return nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
@@ -0,0 +1,167 @@
+package checks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// newRevocationTestCert creates a certificate for "localhost" with the given OCSP responder and CRL distribution point,
+// signed by ca, or a CA that may sign CRLs when ca is nil
+func newRevocationTestCert(t *testing.T, commonName string, ca *testCert, ocspServer, crlURL string) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	if ocspServer != "" {
+		template.OCSPServer = []string{ocspServer}
+	}
+	if crlURL != "" {
+		template.CRLDistributionPoints = []string{crlURL}
+	}
+	signer, signerKey := template, key
+	if ca == nil {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	} else {
+		signer, signerKey = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{cert: cert, key: key}
+}
+
+func newOCSPResponse(t *testing.T, ca *testCert, serial *big.Int, status int) []byte {
+	response, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+		Status:       status,
+		SerialNumber: serial,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+		RevokedAt:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, ca.key)
+	require.NoError(t, err)
+	return response
+}
+
+func TestNewRevocationCheck_config(t *testing.T) {
+	_, err := NewRevocationCheck(RevocationCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "Address must not be empty")
+
+	_, err = NewRevocationCheck(RevocationCheckConfig{Address: "localhost:443"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	check, err := NewRevocationCheck(RevocationCheckConfig{CheckName: checkName, Address: "localhost:443"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+}
+
+func TestNewRevocationCheck(t *testing.T) {
+	ca := newRevocationTestCert(t, "ca", nil, "", "")
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	ocspStatus := ocsp.Good
+	ocspResponder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		ocspReq, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+		_, _ = rw.Write(newOCSPResponse(t, ca, ocspReq.SerialNumber, ocspStatus))
+	}))
+	defer ocspResponder.Close()
+
+	var revoked []pkix.RevokedCertificate
+	crlServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:              big.NewInt(1),
+			ThisUpdate:          time.Now().Add(-time.Minute),
+			NextUpdate:          time.Now().Add(time.Hour),
+			RevokedCertificates: revoked,
+		}, ca.cert, ca.key)
+		require.NoError(t, err)
+		_, _ = rw.Write(crl)
+	}))
+	defer crlServer.Close()
+
+	execute := func(leaf *testCert, staple []byte) (interface{}, error) {
+		certificate := leaf.tlsCertificate()
+		certificate.OCSPStaple = staple
+		address := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{certificate}})
+		check, err := NewRevocationCheck(RevocationCheckConfig{CheckName: checkName, Address: address, TLSConfig: &tls.Config{RootCAs: roots}})
+		require.NoError(t, err)
+		return check.Execute(context.Background())
+	}
+
+	t.Run("OCSP", func(t *testing.T) {
+		leaf := newRevocationTestCert(t, "leaf", ca, ocspResponder.URL, crlServer.URL)
+		details, err := execute(leaf, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []RevocationDetails{{Subject: "CN=leaf", Status: RevocationStatusGood, Source: "OCSP"}}, details)
+
+		ocspStatus = ocsp.Revoked
+		defer func() { ocspStatus = ocsp.Good }()
+		_, err = execute(leaf, nil)
+		assert.EqualError(t, err, "certificate 'CN=leaf' was revoked at 2020-01-02T03:04:05Z")
+
+		ocspStatus = ocsp.Unknown
+		_, err = execute(leaf, nil)
+		assert.EqualError(t, err, "revocation status of certificate 'CN=leaf' is unknown")
+	})
+
+	t.Run("stapled OCSP", func(t *testing.T) {
+		leaf := newRevocationTestCert(t, "leaf", ca, "http://127.0.0.1:1/ocsp", "")
+		details, err := execute(leaf, newOCSPResponse(t, ca, leaf.cert.SerialNumber, ocsp.Good))
+		assert.NoError(t, err)
+		assert.Equal(t, []RevocationDetails{{Subject: "CN=leaf", Status: RevocationStatusGood, Source: "stapled OCSP"}}, details)
+
+		_, err = execute(leaf, newOCSPResponse(t, ca, big.NewInt(1), ocsp.Good))
+		assert.Error(t, err, "a response for another certificate")
+	})
+
+	t.Run("CRL", func(t *testing.T) {
+		leaf := newRevocationTestCert(t, "leaf", ca, "", crlServer.URL)
+		details, err := execute(leaf, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []RevocationDetails{{Subject: "CN=leaf", Status: RevocationStatusGood, Source: "CRL"}}, details)
+
+		revokedAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+		revoked = []pkix.RevokedCertificate{{SerialNumber: leaf.cert.SerialNumber, RevocationTime: revokedAt}}
+		details, err = execute(leaf, nil)
+		assert.EqualError(t, err, "certificate 'CN=leaf' was revoked at 2021-01-02T03:04:05Z")
+		assert.Equal(t, []RevocationDetails{{Subject: "CN=leaf", Status: RevocationStatusRevoked, Source: "CRL", RevokedAt: &revokedAt}}, details)
+	})
+
+	t.Run("no revocation information", func(t *testing.T) {
+		leaf := newRevocationTestCert(t, "leaf", ca, "", "")
+		_, err := execute(leaf, nil)
+		assert.EqualError(t, err, "certificate 'CN=leaf' has no OCSP responder or CRL distribution point")
+	})
+}
@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// S3Client is the minimal S3 API required by the S3 check, so that any S3-compatible client library can be used,
+// e.g. by wrapping the HeadBucket and HeadObject operations of the AWS SDK or a MinIO client.
+// Implementations should return an error on any failure, including authentication, permission and connectivity errors.
+type S3Client interface {
+	HeadBucket(ctx context.Context, bucket string) error
+	HeadObject(ctx context.Context, bucket, key string) error
+}
+
+// S3CheckOption configures an S3 check
+type S3CheckOption func(c *s3Check)
+
+// WithS3SentinelObject sets the S3 check to also HEAD the given key in the bucket, which verifies the object read permissions.
+func WithS3SentinelObject(key string) S3CheckOption {
+	return func(c *s3Check) {
+		c.sentinelKey = key
+	}
+}
+
+type s3Check struct {
+	name        string
+	client      S3Client
+	bucket      string
+	sentinelKey string
+}
+
+// NewS3Check returns a Check that verifies the given bucket is accessible using HeadBucket,
+// and optionally that a sentinel object is readable (see WithS3SentinelObject).
+func NewS3Check(name string, client S3Client, bucket string, opts ...S3CheckOption) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if bucket == "" {
+		return nil, errors.New("bucket must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &s3Check{name: name, client: client, bucket: bucket}
+	for _, opt := range opts {
+		opt(check)
+	}
+	return check, nil
+}
+
+func (c *s3Check) Name() string {
+	return c.name
+}
+
+func (c *s3Check) Execute(ctx context.Context) (details interface{}, err error) {
+	if err := c.client.HeadBucket(ctx, c.bucket); err != nil {
+		return nil, errors.Errorf("HeadBucket '%s' failed: %v", c.bucket, err)
+	}
+	if c.sentinelKey != "" {
+		if err := c.client.HeadObject(ctx, c.bucket, c.sentinelKey); err != nil {
+			return nil, errors.Errorf("HeadObject '%s/%s' failed: %v", c.bucket, c.sentinelKey, err)
+		}
+	}
+	return nil, nil
+}
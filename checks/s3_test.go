@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeS3 struct {
+	buckets map[string]bool
+	objects map[string]bool
+}
+
+func (s *fakeS3) HeadBucket(_ context.Context, bucket string) error {
+	if !s.buckets[bucket] {
+		return errors.New("Forbidden: Access Denied")
+	}
+	return nil
+}
+
+func (s *fakeS3) HeadObject(_ context.Context, bucket, key string) error {
+	if !s.objects[bucket+"/"+key] {
+		return errors.New("NotFound: Not Found")
+	}
+	return nil
+}
+
+func TestNewS3Check_config(t *testing.T) {
+	_, err := NewS3Check(checkName, nil, "bucket")
+	assert.EqualError(t, err, "client must not be nil")
+
+	_, err = NewS3Check(checkName, &fakeS3{}, "")
+	assert.EqualError(t, err, "bucket must not be empty")
+
+	_, err = NewS3Check("", &fakeS3{}, "bucket")
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewS3Check(t *testing.T) {
+	s3 := &fakeS3{buckets: map[string]bool{"assets": true}}
+	check, err := NewS3Check(checkName, s3, "assets")
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	check, err = NewS3Check(checkName, s3, "reports")
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "HeadBucket 'reports' failed: Forbidden: Access Denied")
+}
+
+func TestNewS3Check_sentinelObject(t *testing.T) {
+	s3 := &fakeS3{buckets: map[string]bool{"assets": true}}
+	check, err := NewS3Check(checkName, s3, "assets", WithS3SentinelObject("health/sentinel"))
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "HeadObject 'assets/health/sentinel' failed: NotFound: Not Found")
+
+	s3.objects = map[string]bool{"assets/health/sentinel": true}
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// SMTPCheckConfig configures a check for an SMTP server.
+type SMTPCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is the required host:port address of the SMTP server, e.g. "smtp.example.com:587"
+	Address string
+	// LocalName is the host name sent with EHLO, defaults to "localhost".
+	LocalName string
+	// StartTLS indicates when true, that the connection must be upgraded using STARTTLS.
+	StartTLS bool
+	// TLSConfig is optional, and configures the STARTTLS handshake. The server name defaults to the host of Address.
+	TLSConfig *tls.Config
+	// Auth is optional, and authenticates with the server when defined, e.g. smtp.PlainAuth(...).
+	Auth smtp.Auth
+	// Timeout is the timeout of the whole SMTP conversation, defaults to "1s".
+	Timeout time.Duration
+}
+
+type smtpCheck struct {
+	config SMTPCheckConfig
+	host   string
+}
+
+// NewSMTPCheck returns a Check that connects to an SMTP server, sends EHLO, optionally upgrades the connection using STARTTLS
+// and authenticates, and quits without sending a message.
+func NewSMTPCheck(config SMTPCheckConfig) (gosundheit.Check, error) {
+	if config.Address == "" {
+		return nil, errors.New("Address must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	host, _, err := net.SplitHostPort(config.Address)
+	if err != nil {
+		return nil, errors.Errorf("invalid Address: %v", err)
+	}
+	if config.LocalName == "" {
+		config.LocalName = "localhost"
+	}
+	if config.TLSConfig == nil {
+		config.TLSConfig = &tls.Config{}
+	}
+	if config.TLSConfig.ServerName == "" {
+		config.TLSConfig = config.TLSConfig.Clone()
+		config.TLSConfig.ServerName = host
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &smtpCheck{config: config, host: host}, nil
+}
+
+func (c *smtpCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *smtpCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.config.Address)
+	if err != nil {
+		return nil, errors.Errorf("failed to connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		return nil, errors.Errorf("failed to read greeting: %v", err)
+	}
+	if err := client.Hello(c.config.LocalName); err != nil {
+		return nil, errors.Errorf("EHLO failed: %v", err)
+	}
+	if c.config.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return nil, errors.New("server does not support STARTTLS")
+		}
+		if err := client.StartTLS(c.config.TLSConfig); err != nil {
+			return nil, errors.Errorf("STARTTLS failed: %v", err)
+		}
+	}
+	if c.config.Auth != nil {
+		if err := client.Auth(c.config.Auth); err != nil {
+			return nil, errors.Errorf("AUTH failed: %v", err)
+		}
+	}
+	if err := client.Quit(); err != nil {
+		return nil, errors.Errorf("QUIT failed: %v", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,146 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSMTPServer starts a minimal SMTP server that supports STARTTLS when tlsConfig is not nil, and PLAIN authentication
+func startSMTPServer(t *testing.T, tlsConfig *tls.Config, password string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, tlsConfig, password)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveSMTP(conn net.Conn, tlsConfig *tls.Config, password string) {
+	defer func() { _ = conn.Close() }()
+	text := textproto.NewConn(conn)
+	_ = text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch strings.ToUpper(fields[0]) {
+		case "EHLO":
+			_ = text.PrintfLine("250-localhost")
+			if tlsConfig != nil {
+				_ = text.PrintfLine("250-STARTTLS")
+			}
+			_ = text.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			_ = text.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, tlsConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, text, tlsConfig = tlsConn, textproto.NewConn(tlsConn), nil
+		case "AUTH":
+			credentials, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			if strings.HasSuffix(string(credentials), "\x00"+password) {
+				_ = text.PrintfLine("235 authenticated")
+			} else {
+				_ = text.PrintfLine("535 authentication failed")
+			}
+		case "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			_ = text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestNewSMTPCheck_config(t *testing.T) {
+	_, err := NewSMTPCheck(SMTPCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "Address must not be empty")
+
+	_, err = NewSMTPCheck(SMTPCheckConfig{Address: "localhost:25"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewSMTPCheck(SMTPCheckConfig{CheckName: checkName, Address: "localhost"})
+	assert.EqualError(t, err, "invalid Address: address localhost: missing port in address")
+}
+
+func TestNewSMTPCheck(t *testing.T) {
+	address := startSMTPServer(t, nil, "secret")
+
+	check, err := NewSMTPCheck(SMTPCheckConfig{CheckName: checkName, Address: address})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	check, err = NewSMTPCheck(SMTPCheckConfig{CheckName: checkName, Address: address, StartTLS: true})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "server does not support STARTTLS")
+}
+
+func TestNewSMTPCheck_startTLSAndAuth(t *testing.T) {
+	ca := newTestCert(t, "test-ca", time.Now().Add(time.Hour), nil)
+	server := newTestCert(t, "test-server", time.Now().Add(time.Hour), ca)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	address := startSMTPServer(t, &tls.Config{Certificates: []tls.Certificate{server.tlsCertificate()}}, "secret")
+
+	config := SMTPCheckConfig{
+		CheckName: checkName,
+		Address:   address,
+		StartTLS:  true,
+		TLSConfig: &tls.Config{RootCAs: roots},
+		Auth:      smtp.PlainAuth("", "user", "secret", "127.0.0.1"),
+	}
+	check, err := NewSMTPCheck(config)
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	config.Auth = smtp.PlainAuth("", "user", "wrong", "127.0.0.1")
+	check, err = NewSMTPCheck(config)
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AUTH failed: 535")
+
+	config.TLSConfig = &tls.Config{RootCAs: x509.NewCertPool()}
+	check, err = NewSMTPCheck(config)
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STARTTLS failed")
+}
+
+func TestNewSMTPCheck_unreachable(t *testing.T) {
+	check, err := NewSMTPCheck(SMTPCheckConfig{CheckName: checkName, Address: "127.0.0.1:1"})
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect")
+}
@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// SQS queue attribute names
+const (
+	SQSApproximateNumberOfMessages           = "ApproximateNumberOfMessages"
+	SQSApproximateNumberOfMessagesNotVisible = "ApproximateNumberOfMessagesNotVisible"
+	SQSApproximateNumberOfMessagesDelayed    = "ApproximateNumberOfMessagesDelayed"
+)
+
+// SQSClient is the minimal SQS API required by the SQS check, so that any client library can be used,
+// e.g. by wrapping the GetQueueAttributes operation of the AWS SDK.
+// Implementations should return an error on any failure, including authentication, permission and connectivity errors.
+type SQSClient interface {
+	GetQueueAttributes(ctx context.Context, queueURL string, attributeNames ...string) (attributes map[string]string, err error)
+}
+
+// SQSClientFunc type is an adapter to allow the use of ordinary functions as SQSClients.
+type SQSClientFunc func(ctx context.Context, queueURL string, attributeNames ...string) (attributes map[string]string, err error)
+
+// GetQueueAttributes calls f(ctx, queueURL, attributeNames...).
+func (f SQSClientFunc) GetQueueAttributes(ctx context.Context, queueURL string, attributeNames ...string) (map[string]string, error) {
+	return f(ctx, queueURL, attributeNames...)
+}
+
+// SQSCheckOption configures an SQS check
+type SQSCheckOption func(c *sqsCheck)
+
+// WithSQSMaxBacklog sets the SQS check to fail when the approximate number of messages available in the queue exceeds maxMessages.
+func WithSQSMaxBacklog(maxMessages int64) SQSCheckOption {
+	return func(c *sqsCheck) {
+		c.maxBacklog = maxMessages
+	}
+}
+
+// SQSQueueDetails are the details of an SQS check
+type SQSQueueDetails struct {
+	// Messages is the approximate number of messages available for retrieval
+	Messages int64 `json:"messages"`
+	// MessagesNotVisible is the approximate number of messages which are in flight
+	MessagesNotVisible int64 `json:"messagesNotVisible"`
+	// MessagesDelayed is the approximate number of messages which are delayed
+	MessagesDelayed int64 `json:"messagesDelayed"`
+}
+
+type sqsCheck struct {
+	name       string
+	client     SQSClient
+	queueURL   string
+	maxBacklog int64
+}
+
+// NewSQSCheck returns a Check that calls GetQueueAttributes for the given queue URL, which verifies the queue is accessible,
+// and optionally fails when the queue backlog exceeds a threshold (see WithSQSMaxBacklog).
+// The approximate numbers of messages are reported as the check details.
+func NewSQSCheck(name string, client SQSClient, queueURL string, opts ...SQSCheckOption) (gosundheit.Check, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if queueURL == "" {
+		return nil, errors.New("queue URL must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &sqsCheck{name: name, client: client, queueURL: queueURL}
+	for _, opt := range opts {
+		opt(check)
+	}
+	if check.maxBacklog < 0 {
+		return nil, errors.New("max backlog must not be negative")
+	}
+	return check, nil
+}
+
+func (c *sqsCheck) Name() string {
+	return c.name
+}
+
+func (c *sqsCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	attributes, err := c.client.GetQueueAttributes(ctx, c.queueURL,
+		SQSApproximateNumberOfMessages, SQSApproximateNumberOfMessagesNotVisible, SQSApproximateNumberOfMessagesDelayed)
+	if err != nil {
+		return nil, errors.Errorf("GetQueueAttributes '%s' failed: %v", c.queueURL, err)
+	}
+
+	var queueDetails SQSQueueDetails
+	for name, value := range map[string]*int64{
+		SQSApproximateNumberOfMessages:           &queueDetails.Messages,
+		SQSApproximateNumberOfMessagesNotVisible: &queueDetails.MessagesNotVisible,
+		SQSApproximateNumberOfMessagesDelayed:    &queueDetails.MessagesDelayed,
+	} {
+		attribute, ok := attributes[name]
+		if !ok {
+			continue
+		}
+		if *value, err = strconv.ParseInt(attribute, 10, 64); err != nil {
+			return nil, errors.Errorf("invalid %s '%s'", name, attribute)
+		}
+	}
+
+	if c.maxBacklog > 0 && queueDetails.Messages > c.maxBacklog {
+		return queueDetails, errors.Errorf("queue backlog of %d messages exceeds the maximum of %d", queueDetails.Messages, c.maxBacklog)
+	}
+	return queueDetails, nil
+}
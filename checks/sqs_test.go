@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sqsQueueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/orders"
+
+func TestNewSQSCheck_config(t *testing.T) {
+	client := SQSClientFunc(func(context.Context, string, ...string) (map[string]string, error) { return nil, nil })
+
+	_, err := NewSQSCheck(checkName, nil, sqsQueueURL)
+	assert.EqualError(t, err, "client must not be nil")
+
+	_, err = NewSQSCheck(checkName, client, "")
+	assert.EqualError(t, err, "queue URL must not be empty")
+
+	_, err = NewSQSCheck("", client, sqsQueueURL)
+	assert.EqualError(t, err, "check name must not be empty")
+
+	_, err = NewSQSCheck(checkName, client, sqsQueueURL, WithSQSMaxBacklog(-1))
+	assert.EqualError(t, err, "max backlog must not be negative")
+}
+
+func TestNewSQSCheck(t *testing.T) {
+	var requested []string
+	attributes := map[string]string{
+		SQSApproximateNumberOfMessages:           "100",
+		SQSApproximateNumberOfMessagesNotVisible: "5",
+		SQSApproximateNumberOfMessagesDelayed:    "0",
+	}
+	var attributesErr error
+	client := SQSClientFunc(func(_ context.Context, url string, attributeNames ...string) (map[string]string, error) {
+		if url != sqsQueueURL {
+			return nil, errors.New("AWS.SimpleQueueService.NonExistentQueue")
+		}
+		requested = attributeNames
+		return attributes, attributesErr
+	})
+
+	check, err := NewSQSCheck(checkName, client, sqsQueueURL, WithSQSMaxBacklog(100))
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, SQSQueueDetails{Messages: 100, MessagesNotVisible: 5}, details)
+	assert.Equal(t, []string{SQSApproximateNumberOfMessages, SQSApproximateNumberOfMessagesNotVisible, SQSApproximateNumberOfMessagesDelayed}, requested)
+
+	attributes[SQSApproximateNumberOfMessages] = "101"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "queue backlog of 101 messages exceeds the maximum of 100")
+
+	attributes[SQSApproximateNumberOfMessages] = "many"
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "invalid ApproximateNumberOfMessages 'many'")
+
+	attributesErr = errors.New("AccessDenied: not authorized to perform sqs:GetQueueAttributes")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "GetQueueAttributes '"+sqsQueueURL+"' failed: AccessDenied: not authorized to perform sqs:GetQueueAttributes")
+
+	check, err = NewSQSCheck(checkName, client, sqsQueueURL+"-missing")
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "GetQueueAttributes '"+sqsQueueURL+"-missing' failed: AWS.SimpleQueueService.NonExistentQueue")
+}
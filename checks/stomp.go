@@ -0,0 +1,322 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// stompMaxFrameSize limits the size of the frames read by the check
+const stompMaxFrameSize = 1 << 20
+
+// STOMPCheckConfig configures a check for a STOMP message broker, e.g. ActiveMQ, Artemis or RabbitMQ.
+type STOMPCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is the required host:port address of the broker.
+	Address string
+	// Host is the virtual host to connect to, defaults to the host of Address.
+	Host string
+	// Login and Passcode are optional credentials.
+	Login    string
+	Passcode string
+	// TLSConfig is optional; if defined, the broker is connected using TLS.
+	TLSConfig *tls.Config
+	// HealthQueue is optional; if defined (e.g. "/topic/health"), the check subscribes to it, sends a message to it, and awaits the message.
+	// It must not be a queue shared by other consumers, e.g. the replicas of the service, which would compete for the message
+	// and fail the check spuriously. Use a topic, a temporary queue (e.g. "/temp-queue/health") or a per instance queue instead.
+	HealthQueue string
+	// Timeout is the timeout of the whole STOMP conversation, defaults to "1s".
+	Timeout time.Duration
+}
+
+// STOMPDetails are the details of a STOMP check
+type STOMPDetails struct {
+	// Server is the server name and version reported by the broker, if any
+	Server string `json:"server,omitempty"`
+	// Version is the negotiated STOMP protocol version
+	Version string `json:"version,omitempty"`
+	// ConnectLatency is the duration until the connection was acknowledged by the broker
+	ConnectLatency time.Duration `json:"connectLatency"`
+	// RoundTripLatency is the duration of the send/receive round trip, or zero when there's no HealthQueue
+	RoundTripLatency time.Duration `json:"roundTripLatency,omitempty"`
+	// Error is the ERROR frame sent by the broker, if any
+	Error *STOMPErrorFrame `json:"error,omitempty"`
+}
+
+// STOMPErrorFrame is an ERROR frame sent by a STOMP broker
+type STOMPErrorFrame struct {
+	Message string `json:"message"`
+	Body    string `json:"body,omitempty"`
+}
+
+type stompCheck struct {
+	config STOMPCheckConfig
+}
+
+// NewSTOMPCheck returns a Check that connects to a message broker using the STOMP protocol (1.0 to 1.2), and optionally sends
+// and receives a message on a health queue. The check fails on connection and authentication errors, and the broker ERROR frame
+// is reported as the check details, along with the broker latencies.
+func NewSTOMPCheck(config STOMPCheckConfig) (gosundheit.Check, error) {
+	if config.Address == "" {
+		return nil, errors.New("Address must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Host == "" {
+		host, _, err := net.SplitHostPort(config.Address)
+		if err != nil {
+			return nil, errors.Errorf("invalid Address '%s': %v", config.Address, err)
+		}
+		config.Host = host
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &stompCheck{config: config}, nil
+}
+
+func (c *stompCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *stompCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var stompDetails STOMPDetails
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := c.connect(ctx, &stompDetails)
+	stompDetails.ConnectLatency = time.Since(start)
+	if err != nil {
+		return stompDetails, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if c.config.HealthQueue != "" {
+		start = time.Now()
+		err = c.roundTrip(conn, &stompDetails)
+		stompDetails.RoundTripLatency = time.Since(start)
+		if err != nil {
+			return stompDetails, err
+		}
+	}
+
+	if err := conn.writeFrame("DISCONNECT", nil, nil); err != nil {
+		return stompDetails, errors.Errorf("failed to disconnect: %v", err)
+	}
+	return stompDetails, nil
+}
+
+// connect dials the broker, and sends a CONNECT frame, awaiting the CONNECTED frame
+func (c *stompCheck) connect(ctx context.Context, stompDetails *STOMPDetails) (*stompConn, error) {
+	var dialer interface {
+		DialContext(ctx context.Context, network, address string) (net.Conn, error)
+	} = &net.Dialer{}
+	if c.config.TLSConfig != nil {
+		dialer = &tls.Dialer{Config: c.config.TLSConfig}
+	}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.config.Address)
+	if err != nil {
+		return nil, errors.Errorf("failed to connect to '%s': %v", c.config.Address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = netConn.SetDeadline(deadline)
+	}
+	conn := &stompConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	// the headers of the CONNECT frame are not escaped
+	headers := []string{"accept-version", "1.0,1.1,1.2", "host", c.config.Host, "heart-beat", "0,0"}
+	if c.config.Login != "" {
+		headers = append(headers, "login", c.config.Login)
+	}
+	if c.config.Passcode != "" {
+		headers = append(headers, "passcode", c.config.Passcode)
+	}
+	if err := conn.writeFrame("CONNECT", headers, nil); err != nil {
+		_ = conn.Close()
+		return nil, errors.Errorf("failed to send CONNECT: %v", err)
+	}
+
+	frame, err := conn.readFrame()
+	if err == nil && frame.command == "ERROR" {
+		stompDetails.Error = frame.errorFrame()
+		err = errors.Errorf("connection refused: %s", stompDetails.Error.Message)
+	} else if err == nil && frame.command != "CONNECTED" {
+		err = errors.Errorf("unexpected frame %s", frame.command)
+	} else if err != nil {
+		err = errors.Errorf("failed to receive CONNECTED: %v", err)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	stompDetails.Server = frame.headers["server"]
+	stompDetails.Version = frame.headers["version"]
+	if stompDetails.Version == "" {
+		stompDetails.Version = "1.0"
+	}
+	conn.escape = stompDetails.Version != "1.0"
+	return conn, nil
+}
+
+// roundTrip subscribes to the health queue, sends a message to it, and awaits the message
+func (c *stompCheck) roundTrip(conn *stompConn, stompDetails *STOMPDetails) error {
+	if err := conn.writeFrame("SUBSCRIBE", []string{"destination", c.config.HealthQueue, "id", "0", "ack", "auto"}, nil); err != nil {
+		return errors.Errorf("failed to send SUBSCRIBE: %v", err)
+	}
+	message := []byte(c.config.CheckName + " " + strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := conn.writeFrame("SEND", []string{"destination", c.config.HealthQueue, "content-type", "text/plain"}, message); err != nil {
+		return errors.Errorf("failed to send SEND: %v", err)
+	}
+
+	// other messages may be pending in the queue, or sent to it concurrently
+	for {
+		frame, err := conn.readFrame()
+		if err != nil {
+			return errors.Errorf("failed to receive the sent message: %v", err)
+		}
+		switch {
+		case frame.command == "ERROR":
+			stompDetails.Error = frame.errorFrame()
+			return errors.Errorf("broker error: %s", stompDetails.Error.Message)
+		case frame.command == "MESSAGE" && bytes.Equal(frame.body, message):
+			return nil
+		}
+	}
+}
+
+type stompFrame struct {
+	command string
+	headers map[string]string
+	body    []byte
+}
+
+func (f *stompFrame) errorFrame() *STOMPErrorFrame {
+	return &STOMPErrorFrame{Message: f.headers["message"], Body: strings.TrimSpace(string(f.body))}
+}
+
+type stompConn struct {
+	net.Conn
+	reader *bufio.Reader
+	// escape indicates whether the frame headers are escaped, which is the case since STOMP 1.1
+	escape bool
+}
+
+// writeFrame writes a frame with the given command, headers (as name/value pairs) and body
+func (c *stompConn) writeFrame(command string, headers []string, body []byte) error {
+	var frame bytes.Buffer
+	frame.WriteString(command + "\n")
+	for i := 0; i+1 < len(headers); i += 2 {
+		name, value := headers[i], headers[i+1]
+		if c.escape {
+			name, value = stompEscaper.Replace(name), stompEscaper.Replace(value)
+		}
+		frame.WriteString(name + ":" + value + "\n")
+	}
+	if len(body) > 0 {
+		frame.WriteString("content-length:" + strconv.Itoa(len(body)) + "\n")
+	}
+	frame.WriteString("\n")
+	frame.Write(body)
+	frame.WriteByte(0)
+	_, err := c.Write(frame.Bytes())
+	return err
+}
+
+// readFrame reads a frame, skipping heart-beats
+func (c *stompConn) readFrame() (*stompFrame, error) {
+	var command string
+	for command == "" {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		command = line
+	}
+
+	frame := &stompFrame{command: command, headers: make(map[string]string)}
+	size := len(command)
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			break
+		}
+		if size += len(line); size > stompMaxFrameSize {
+			return nil, errors.Errorf("frame exceeds the maximum of %d bytes", stompMaxFrameSize)
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("malformed header '%s'", line)
+		}
+		name, value := parts[0], parts[1]
+		// CONNECTED frames are not escaped, and the first occurrence of a repeated header is used
+		if c.escape && command != "CONNECTED" {
+			name, value = stompUnescaper.Replace(name), stompUnescaper.Replace(value)
+		}
+		if _, ok := frame.headers[name]; !ok {
+			frame.headers[name] = value
+		}
+	}
+
+	if contentLength, ok := frame.headers["content-length"]; ok {
+		length, err := strconv.Atoi(contentLength)
+		if err != nil || length < 0 || length > stompMaxFrameSize {
+			return nil, errors.Errorf("invalid content-length '%s'", contentLength)
+		}
+		frame.body = make([]byte, length+1)
+		if _, err := io.ReadFull(c.reader, frame.body); err != nil {
+			return nil, err
+		}
+		if frame.body[length] != 0 {
+			return nil, errors.New("frame is not terminated")
+		}
+		frame.body = frame.body[:length]
+		return frame, nil
+	}
+
+	for {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			return frame, nil
+		}
+		if len(frame.body) >= stompMaxFrameSize {
+			return nil, errors.Errorf("frame exceeds the maximum of %d bytes", stompMaxFrameSize)
+		}
+		frame.body = append(frame.body, b)
+	}
+}
+
+// readLine reads a line terminated by either LF or CRLF
+func (c *stompConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+var (
+	stompEscaper   = strings.NewReplacer("\\", "\\\\", "\r", "\\r", "\n", "\\n", ":", "\\c")
+	stompUnescaper = strings.NewReplacer("\\\\", "\\", "\\r", "\r", "\\n", "\n", "\\c", ":")
+)
@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSTOMPBroker starts a STOMP 1.2 broker, which accepts the given passcode, and delivers the messages sent to a destination
+// to the subscriber of the connection. It returns the broker address, and a channel of the received CONNECT frames.
+func startSTOMPBroker(t *testing.T, passcode string) (string, <-chan *stompFrame) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	connects := make(chan *stompFrame, 10)
+	go func() {
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = netConn.Close() }()
+				conn := &stompConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+				subscriptions := make(map[string]string)
+				for {
+					frame, err := conn.readFrame()
+					if err != nil {
+						return
+					}
+					switch frame.command {
+					case "CONNECT":
+						connects <- frame
+						if frame.headers["passcode"] != passcode {
+							_ = conn.writeFrame("ERROR", []string{"message", "Authentication failed"}, []byte("User name [guest] or password is invalid.\n"))
+							return
+						}
+						_ = conn.writeFrame("CONNECTED", []string{"version", "1.2", "server", "ActiveMQ/5.18.3", "heart-beat", "0,0"}, nil)
+						conn.escape = true
+						// a heart-beat
+						_, _ = conn.Write([]byte("\n"))
+					case "SUBSCRIBE":
+						subscriptions[frame.headers["destination"]] = frame.headers["id"]
+					case "SEND":
+						destination := frame.headers["destination"]
+						id, ok := subscriptions[destination]
+						if !ok {
+							_ = conn.writeFrame("ERROR", []string{"message", "not subscribed to " + destination}, nil)
+							return
+						}
+						// a pending message, which is not the health message
+						_ = conn.writeFrame("MESSAGE", []string{"subscription", id, "destination", destination, "message-id", "1"}, []byte("pending"))
+						_ = conn.writeFrame("MESSAGE", []string{"subscription", id, "destination", destination, "message-id", "2"}, frame.body)
+					case "DISCONNECT":
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), connects
+}
+
+func TestNewSTOMPCheck_config(t *testing.T) {
+	_, err := NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "Address must not be empty")
+
+	_, err = NewSTOMPCheck(STOMPCheckConfig{Address: "localhost:61613"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+
+	_, err = NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName, Address: "localhost"})
+	assert.EqualError(t, err, "invalid Address 'localhost': address localhost: missing port in address")
+
+	check, err := NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName, Address: "broker:61613"})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	assert.Equal(t, "broker", check.(*stompCheck).config.Host, "default host")
+}
+
+func TestNewSTOMPCheck(t *testing.T) {
+	address, connects := startSTOMPBroker(t, "secret")
+
+	check, err := NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName, Address: address, Login: "guest", Passcode: "secret"})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	stompDetails := details.(STOMPDetails)
+	assert.Equal(t, "ActiveMQ/5.18.3", stompDetails.Server)
+	assert.Equal(t, "1.2", stompDetails.Version)
+	assert.True(t, stompDetails.ConnectLatency > 0, "connect latency")
+	assert.Zero(t, stompDetails.RoundTripLatency, "no round trip")
+
+	connect := <-connects
+	assert.Equal(t, map[string]string{
+		"accept-version": "1.0,1.1,1.2",
+		"host":           "127.0.0.1",
+		"heart-beat":     "0,0",
+		"login":          "guest",
+		"passcode":       "secret",
+	}, connect.headers)
+
+	check, err = NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName, Address: address, Passcode: "secret", HealthQueue: "/queue/health:check"})
+	require.NoError(t, err)
+	details, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, details.(STOMPDetails).RoundTripLatency > 0, "round trip latency")
+}
+
+func TestNewSTOMPCheck_refused(t *testing.T) {
+	address, _ := startSTOMPBroker(t, "secret")
+
+	check, err := NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName, Address: address, Login: "guest", Passcode: "wrong"})
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "connection refused: Authentication failed")
+	assert.Equal(t, &STOMPErrorFrame{Message: "Authentication failed", Body: "User name [guest] or password is invalid."}, details.(STOMPDetails).Error)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+	check, err = NewSTOMPCheck(STOMPCheckConfig{CheckName: checkName, Address: listener.Addr().String()})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to")
+}
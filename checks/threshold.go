@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// GaugeFunc measures a numeric value, e.g. a queue size or a cache hit ratio
+type GaugeFunc func(ctx context.Context) (float64, error)
+
+// NewThresholdCheck returns a Check that measures a value using the given gauge, and fails when it is outside of
+// the inclusive [min, max] range. For a one-sided threshold use math.Inf(-1) as min, or math.Inf(1) as max.
+// The measured value is reported as the check details.
+func NewThresholdCheck(name string, gauge GaugeFunc, min, max float64) (gosundheit.Check, error) {
+	if gauge == nil {
+		return nil, errors.New("gauge must not be nil")
+	}
+	if min > max {
+		return nil, errors.Errorf("min %v must not be greater than max %v", min, max)
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &CustomCheck{
+		CheckName: name,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			value, err := gauge(ctx)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case math.IsNaN(value):
+				err = errors.New("value is NaN")
+			case value < min:
+				err = errors.Errorf("value %v is below the minimum of %v", value, min)
+			case value > max:
+				err = errors.Errorf("value %v is above the maximum of %v", value, max)
+			}
+			return value, err
+		},
+	}, nil
+}
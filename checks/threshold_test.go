@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewThresholdCheck_config(t *testing.T) {
+	_, err := NewThresholdCheck(checkName, nil, 0, 1)
+	assert.EqualError(t, err, "gauge must not be nil")
+
+	gauge := func(context.Context) (float64, error) { return 0, nil }
+	_, err = NewThresholdCheck(checkName, gauge, 2, 1)
+	assert.EqualError(t, err, "min 2 must not be greater than max 1")
+
+	_, err = NewThresholdCheck("", gauge, 0, 1)
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewThresholdCheck(t *testing.T) {
+	var value float64
+	var gaugeErr error
+	check, err := NewThresholdCheck(checkName, func(context.Context) (float64, error) { return value, gaugeErr }, 10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	for _, v := range []float64{10, 15, 20} {
+		value = v
+		details, err := check.Execute(context.Background())
+		assert.NoError(t, err, "value %v", v)
+		assert.Equal(t, v, details)
+	}
+
+	value = 9.5
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "value 9.5 is below the minimum of 10")
+	assert.Equal(t, 9.5, details)
+
+	value = 21
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "value 21 is above the maximum of 20")
+
+	value = math.NaN()
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "value is NaN")
+
+	gaugeErr = errors.New("queue unavailable")
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "queue unavailable")
+	assert.Nil(t, details)
+}
+
+func TestNewThresholdCheck_oneSided(t *testing.T) {
+	check, err := NewThresholdCheck(checkName, func(context.Context) (float64, error) { return -1e9, nil }, math.Inf(-1), 100)
+	require.NoError(t, err)
+
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,171 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"math"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// TLSExpiryCheckConfig configures a check for the expiry of TLS certificates,
+// either presented by a server or read from a PEM file.
+type TLSExpiryCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// Address is the host:port address of the server that presents the certificates.
+	// Exactly one of Address and File is required
+	Address string
+	// File is the path of a PEM file that contains the certificates.
+	// Exactly one of Address and File is required
+	File string
+	// Window is the period before expiry in which a certificate fails the check, defaults to 14 days.
+	Window time.Duration
+	// TLSConfig is optional, and configures the TLS handshake with the server, e.g. custom RootCAs or ServerName.
+	// If undefined, the server name is taken from Address, and the certificates are verified using the system roots.
+	TLSConfig *tls.Config
+	// Timeout is the timeout used for the connection and TLS handshake, defaults to "1s".
+	Timeout time.Duration
+}
+
+// TLSCertificateDetails are the details of an inspected certificate
+type TLSCertificateDetails struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	NotAfter     time.Time `json:"notAfter"`
+	DaysToExpiry int       `json:"daysToExpiry"`
+}
+
+type tlsExpiryCheck struct {
+	config TLSExpiryCheckConfig
+}
+
+// NewTLSExpiryCheck returns a Check that inspects the leaf and chain certificates presented by a server
+// (or read from a PEM file), and fails when any of them expires within the configured window.
+// The days to expiry of each certificate are reported as the check details, starting with the leaf certificate.
+func NewTLSExpiryCheck(config TLSExpiryCheckConfig) (gosundheit.Check, error) {
+	if (config.Address == "") == (config.File == "") {
+		return nil, errors.New("exactly one of Address and File must be set")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Window == 0 {
+		config.Window = 14 * 24 * time.Hour
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &tlsExpiryCheck{config: config}, nil
+}
+
+func (c *tlsExpiryCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *tlsExpiryCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var certs []*x509.Certificate
+	if c.config.File != "" {
+		certs, err = readPEMCertificates(c.config.File)
+	} else {
+		var state tls.ConnectionState
+		state, err = tlsHandshake(ctx, c.config.Address, c.config.TLSConfig, c.config.Timeout)
+		certs = state.PeerCertificates
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	certDetails := make([]TLSCertificateDetails, len(certs))
+	var expiring *x509.Certificate
+	for i, cert := range certs {
+		certDetails[i] = TLSCertificateDetails{
+			Subject:      cert.Subject.String(),
+			Issuer:       cert.Issuer.String(),
+			NotAfter:     cert.NotAfter,
+			DaysToExpiry: int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+		}
+		if expiring == nil && cert.NotAfter.Sub(now) < c.config.Window {
+			expiring = cert
+		}
+	}
+
+	if expiring != nil {
+		return certDetails, errors.Errorf("certificate '%s' expires at %s", expiring.Subject, expiring.NotAfter.Format(time.RFC3339))
+	}
+	return certDetails, nil
+}
+
+// tlsHandshake connects to the given address and performs a TLS handshake, returning the resulting connection state.
+func tlsHandshake(ctx context.Context, address string, config *tls.Config, timeout time.Duration) (tls.ConnectionState, error) {
+	conn, err := dialTLS(ctx, address, config, timeout)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	return conn.ConnectionState(), nil
+}
+
+// dialTLS connects to the given address and performs a TLS handshake, returning the connection with a deadline
+// derived from the given timeout. The server name defaults to the host of the address.
+func dialTLS(ctx context.Context, address string, config *tls.Config, timeout time.Duration) (*tls.Conn, error) {
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, errors.Errorf("invalid address '%s': %v", address, err)
+		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, errors.Errorf("TLS handshake with '%s' failed: %v", address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	return conn.(*tls.Conn), nil
+}
+
+func readPEMCertificates(file string) ([]*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Errorf("failed to read certificates file: %v", err)
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Errorf("failed to parse certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.Errorf("no certificates found in '%s'", file)
+	}
+	return certs, nil
+}
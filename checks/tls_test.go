@@ -0,0 +1,178 @@
+package checks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCert creates a certificate for "localhost", signed by parent or self-signed when parent is nil
+func newTestCert(t *testing.T, commonName string, notAfter time.Time, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (c *testCert) tlsCertificate(chain ...*testCert) tls.Certificate {
+	certificate := tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key, Leaf: c.cert}
+	for _, cert := range chain {
+		certificate.Certificate = append(certificate.Certificate, cert.cert.Raw)
+	}
+	return certificate
+}
+
+// startTLSServer starts a TLS server that completes handshakes and closes the connections, returning its address
+func startTLSServer(t *testing.T, config *tls.Config) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestNewTLSExpiryCheck_config(t *testing.T) {
+	_, err := NewTLSExpiryCheck(TLSExpiryCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "exactly one of Address and File must be set")
+
+	_, err = NewTLSExpiryCheck(TLSExpiryCheckConfig{CheckName: checkName, Address: "localhost:443", File: "cert.pem"})
+	assert.EqualError(t, err, "exactly one of Address and File must be set")
+
+	_, err = NewTLSExpiryCheck(TLSExpiryCheckConfig{Address: "localhost:443"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewTLSExpiryCheck_address(t *testing.T) {
+	ca := newTestCert(t, "test-ca", time.Now().Add(365*24*time.Hour+time.Hour), nil)
+	leaf := newTestCert(t, "test-leaf", time.Now().Add(10*24*time.Hour+time.Hour), ca)
+	address := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{leaf.tlsCertificate(ca)}})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	config := TLSExpiryCheckConfig{
+		CheckName: checkName,
+		Address:   address,
+		TLSConfig: &tls.Config{RootCAs: roots, ServerName: "localhost"},
+		Window:    7 * 24 * time.Hour,
+	}
+
+	check, err := NewTLSExpiryCheck(config)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	require.NoError(t, err)
+	certs := details.([]TLSCertificateDetails)
+	require.Len(t, certs, 2)
+	assert.Equal(t, "CN=test-leaf", certs[0].Subject)
+	assert.Equal(t, "CN=test-ca", certs[0].Issuer)
+	assert.Equal(t, 10, certs[0].DaysToExpiry)
+	assert.Equal(t, "CN=test-ca", certs[1].Subject)
+	assert.Equal(t, 365, certs[1].DaysToExpiry)
+
+	config.Window = 30 * 24 * time.Hour
+	check, err = NewTLSExpiryCheck(config)
+	require.NoError(t, err)
+	details, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate 'CN=test-leaf' expires at")
+	assert.Len(t, details, 2)
+}
+
+func TestNewTLSExpiryCheck_untrusted(t *testing.T) {
+	leaf := newTestCert(t, "test-leaf", time.Now().Add(365*24*time.Hour), nil)
+	address := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{leaf.tlsCertificate()}})
+
+	check, err := NewTLSExpiryCheck(TLSExpiryCheckConfig{CheckName: checkName, Address: address})
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TLS handshake with '"+address+"' failed")
+	assert.Nil(t, details)
+}
+
+func TestNewTLSExpiryCheck_file(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-check")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ca := newTestCert(t, "test-ca", time.Now().Add(5*24*time.Hour+time.Hour), nil)
+	leaf := newTestCert(t, "test-leaf", time.Now().Add(90*24*time.Hour+time.Hour), ca)
+	file := filepath.Join(dir, "chain.pem")
+	require.NoError(t, ioutil.WriteFile(file, append(leaf.pem, ca.pem...), 0600))
+
+	check, err := NewTLSExpiryCheck(TLSExpiryCheckConfig{CheckName: checkName, File: file})
+	require.NoError(t, err)
+
+	details, err := check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate 'CN=test-ca' expires at", "a chain certificate expiry fails the check")
+	certs := details.([]TLSCertificateDetails)
+	require.Len(t, certs, 2)
+	assert.Equal(t, 90, certs[0].DaysToExpiry)
+	assert.Equal(t, 5, certs[1].DaysToExpiry)
+
+	empty := filepath.Join(dir, "empty.pem")
+	require.NoError(t, ioutil.WriteFile(empty, []byte("not a certificate"), 0600))
+	check, err = NewTLSExpiryCheck(TLSExpiryCheckConfig{CheckName: checkName, File: empty})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "no certificates found in '"+empty+"'")
+}
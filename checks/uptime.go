@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// UptimeDetails are the details of a host uptime check
+type UptimeDetails struct {
+	Uptime   time.Duration `json:"uptime"`
+	BootTime time.Time     `json:"bootTime"`
+}
+
+type uptimeCheck struct {
+	name      string
+	minUptime time.Duration
+	// uptime returns the host uptime
+	uptime func() (time.Duration, error)
+}
+
+// NewUptimeCheck returns a Check that reports the host uptime and boot time as the check details, and fails when the host
+// rebooted within minUptime, unless it is zero, which helps correlating instability with node churn.
+// Register the check with the gosundheit.Severity(gosundheit.SeverityWarning) option for reporting recent reboots without failing the health.
+// Uptime is only supported on Linux.
+func NewUptimeCheck(name string, minUptime time.Duration) (gosundheit.Check, error) {
+	if minUptime < 0 {
+		return nil, errors.New("minUptime must not be negative")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &uptimeCheck{name: name, minUptime: minUptime, uptime: readUptime}, nil
+}
+
+func (c *uptimeCheck) Name() string {
+	return c.name
+}
+
+func (c *uptimeCheck) Execute(_ context.Context) (details interface{}, err error) {
+	uptime, err := c.uptime()
+	if err != nil {
+		return nil, err
+	}
+
+	uptimeDetails := UptimeDetails{Uptime: uptime, BootTime: time.Now().Add(-uptime).Truncate(time.Second)}
+	if uptime < c.minUptime {
+		return uptimeDetails, errors.Errorf("the host rebooted %v ago, within the minimum uptime of %v", uptime, c.minUptime)
+	}
+	return uptimeDetails, nil
+}
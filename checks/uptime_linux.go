@@ -0,0 +1,28 @@
+package checks
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// readUptime reads the host uptime from /proc/uptime, which is not affected by changes of the system clock
+func readUptime() (time.Duration, error) {
+	data, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, errors.Errorf("failed to read uptime: %v", err)
+	}
+	// the uptime and the idle time in seconds
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, errors.New("malformed uptime")
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, errors.Errorf("malformed uptime: %v", err)
+	}
+	return secondsToDuration(seconds).Truncate(time.Second), nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package checks
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func readUptime() (time.Duration, error) {
+	return 0, errors.New("uptime is only supported on Linux")
+}
@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUptimeCheck_config(t *testing.T) {
+	_, err := NewUptimeCheck(checkName, -time.Second)
+	assert.EqualError(t, err, "minUptime must not be negative")
+
+	_, err = NewUptimeCheck("", time.Hour)
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewUptimeCheck(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uptime is only supported on Linux")
+	}
+
+	check, err := NewUptimeCheck(checkName, 0)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	uptimeDetails := details.(UptimeDetails)
+	assert.True(t, uptimeDetails.Uptime > 0, "uptime")
+	assert.WithinDuration(t, time.Now().Add(-uptimeDetails.Uptime), uptimeDetails.BootTime, time.Second, "boot time")
+}
+
+func TestNewUptimeCheck_recentReboot(t *testing.T) {
+	check, err := NewUptimeCheck(checkName, 10*time.Minute)
+	require.NoError(t, err)
+
+	uptime, uptimeErr := 5*time.Minute, error(nil)
+	check.(*uptimeCheck).uptime = func() (time.Duration, error) {
+		return uptime, uptimeErr
+	}
+
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "the host rebooted 5m0s ago, within the minimum uptime of 10m0s")
+	assert.Equal(t, 5*time.Minute, details.(UptimeDetails).Uptime)
+
+	uptime = time.Hour
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	uptimeErr = errors.New("failed to read uptime: permission denied")
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "failed to read uptime: permission denied")
+}
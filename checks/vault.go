@@ -0,0 +1,99 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// VaultCheckConfig configures a check for the health of a HashiCorp Vault server.
+type VaultCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the required base URL of the Vault server, e.g. "https://vault.example.com:8200"
+	URL string
+	// AllowSealed indicates when true, that a sealed server passes the check.
+	AllowSealed bool
+	// AllowUninitialized indicates when true, that an uninitialized server passes the check.
+	AllowUninitialized bool
+	// FailOnStandby indicates when true, that a standby server fails the check, e.g. when Vault is not deployed in HA mode.
+	FailOnStandby bool
+	// Client is optional; if undefined, a new client will be created using "Timeout".
+	Client *http.Client
+	// Timeout is the timeout used for the HTTP request, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the HTTP request with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+// VaultHealth is the server health, as returned by the Vault sys/health API
+type VaultHealth struct {
+	Initialized                bool   `json:"initialized"`
+	Sealed                     bool   `json:"sealed"`
+	Standby                    bool   `json:"standby"`
+	PerformanceStandby         bool   `json:"performance_standby"`
+	ReplicationPerformanceMode string `json:"replication_performance_mode,omitempty"`
+	ReplicationDRMode          string `json:"replication_dr_mode,omitempty"`
+	ServerTimeUTC              int64  `json:"server_time_utc"`
+	Version                    string `json:"version"`
+	ClusterName                string `json:"cluster_name,omitempty"`
+}
+
+// vaultHealthStatuses are the response statuses of the sys/health API, which reflect the server state:
+// active, standby, DR secondary, performance standby, uninitialized and sealed respectively.
+var vaultHealthStatuses = []int{200, 429, 472, 473, 501, 503}
+
+type vaultCheck struct {
+	config    VaultCheckConfig
+	healthURL string
+}
+
+// NewVaultCheck returns a Check that queries the sys/health API of a Vault server, and fails when the server is sealed,
+// uninitialized, or optionally a standby (each condition is configurable). The server health, including its seal status,
+// is reported as the check details.
+func NewVaultCheck(config VaultCheckConfig) (gosundheit.Check, error) {
+	if config.URL == "" {
+		return nil, errors.New("URL must not be empty")
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &vaultCheck{
+		config:    config,
+		healthURL: strings.TrimSuffix(config.URL, "/") + "/v1/sys/health",
+	}, nil
+}
+
+func (c *vaultCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *vaultCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	var health VaultHealth
+	if err := fetchJSON(ctx, c.config.Client, c.healthURL, c.config.Options, &health, vaultHealthStatuses...); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !health.Initialized && !c.config.AllowUninitialized:
+		return health, errors.New("vault is not initialized")
+	case health.Sealed && !c.config.AllowSealed:
+		return health, errors.New("vault is sealed")
+	case health.Standby && c.config.FailOnStandby:
+		return health, errors.New("vault is a standby")
+	}
+	return health, nil
+}
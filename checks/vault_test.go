@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func vaultServer(status int, initialized, sealed, standby bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/sys/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = fmt.Fprintf(w, `{"initialized":%t,"sealed":%t,"standby":%t,"version":"1.6.0","cluster_name":"vault"}`,
+			initialized, sealed, standby)
+	}))
+}
+
+func TestNewVaultCheck_requiredFields(t *testing.T) {
+	_, err := NewVaultCheck(VaultCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "URL must not be empty")
+
+	_, err = NewVaultCheck(VaultCheckConfig{URL: "http://localhost:8200"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewVaultCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		server      *httptest.Server
+		config      VaultCheckConfig
+		expectedErr string
+	}{
+		{name: "active", server: vaultServer(200, true, false, false)},
+		{name: "standby", server: vaultServer(429, true, false, true)},
+		{name: "standby failing", server: vaultServer(429, true, false, true), config: VaultCheckConfig{FailOnStandby: true}, expectedErr: "vault is a standby"},
+		{name: "sealed", server: vaultServer(503, true, true, false), expectedErr: "vault is sealed"},
+		{name: "sealed allowed", server: vaultServer(503, true, true, false), config: VaultCheckConfig{AllowSealed: true}},
+		{name: "uninitialized", server: vaultServer(501, false, true, false), expectedErr: "vault is not initialized"},
+		{name: "unexpected status", server: vaultServer(500, true, false, false), expectedErr: "unexpected status code: '500' expected: '[200 429 472 473 501 503]'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer test.server.Close()
+
+			config := test.config
+			config.CheckName = checkName
+			config.URL = test.server.URL
+			check, err := NewVaultCheck(config)
+			require.NoError(t, err)
+
+			details, err := check.Execute(context.Background())
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "1.6.0", details.(VaultHealth).Version)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
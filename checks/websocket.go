@@ -0,0 +1,282 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 -- mandated by the WebSocket handshake (RFC 6455)
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	wsOpText   = 0x1
+	wsOpBinary = 0x2
+	wsOpClose  = 0x8
+	wsOpPing   = 0x9
+	wsOpPong   = 0xa
+
+	wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// wsMaxFrameSize limits the size of the frames read by the check
+	wsMaxFrameSize = 1 << 20
+)
+
+// WebSocketCheckConfig configures a check for a WebSocket endpoint.
+type WebSocketCheckConfig struct {
+	// CheckName is the health check name - must be a valid metric name.
+	// CheckName is required
+	CheckName string
+	// URL is the required ws:// or wss:// URL of the endpoint
+	URL string
+	// Ping indicates when true, that a ping frame is sent after the handshake, and a matching pong is expected.
+	Ping bool
+	// Message is an optional text message sent after the handshake, which must be responded with a message.
+	Message string
+	// ExpectedResponse is optional; if defined, operates as a basic "response message should contain <string>".
+	ExpectedResponse string
+	// TLSConfig is optional, and configures the TLS connection of wss:// URLs.
+	TLSConfig *tls.Config
+	// Timeout is the timeout of the whole WebSocket conversation, defaults to "1s".
+	Timeout time.Duration
+	// Options allow you to configure the handshake HTTP request with arbitrary settings, e.g. add request headers, etc.
+	Options []RequestOption
+}
+
+type webSocketCheck struct {
+	config  WebSocketCheckConfig
+	httpURL string
+	client  *http.Client
+}
+
+// NewWebSocketCheck returns a Check that performs a WebSocket handshake with the given URL, optionally sends a ping
+// and/or a message and validates the response, and closes the connection cleanly.
+func NewWebSocketCheck(config WebSocketCheckConfig) (gosundheit.Check, error) {
+	if config.URL == "" {
+		return nil, errors.New("URL must not be empty")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	default:
+		return nil, errors.Errorf("unsupported URL scheme '%s', expected 'ws' or 'wss'", u.Scheme)
+	}
+	if config.CheckName == "" {
+		return nil, errors.New("CheckName must not be empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+
+	return &webSocketCheck{
+		config:  config,
+		httpURL: u.String(),
+		// a dedicated HTTP/1.1 transport, since the upgrade is not supported over HTTP/2
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: config.TLSConfig,
+		}},
+	}, nil
+}
+
+func (c *webSocketCheck) Name() string {
+	return c.config.CheckName
+}
+
+func (c *webSocketCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	details = c.config.URL
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	conn, err := c.handshake(ctx)
+	if err != nil {
+		return details, err
+	}
+	defer func() { _ = conn.Close() }()
+	// the upgraded connection has no deadlines, so it is closed when the context is done to abort blocked reads
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	if c.config.Ping {
+		if err := wsWriteFrame(conn, wsOpPing, []byte(c.config.CheckName), true); err != nil {
+			return details, errors.Errorf("failed to send ping: %v", err)
+		}
+		_, payload, err := wsReadFrameOf(conn, wsOpPong)
+		if err != nil {
+			return details, errors.Errorf("failed to receive pong: %v", err)
+		}
+		if !bytes.Equal(payload, []byte(c.config.CheckName)) {
+			return details, errors.New("pong payload does not match the ping payload")
+		}
+	}
+
+	if c.config.Message != "" {
+		if err := wsWriteFrame(conn, wsOpText, []byte(c.config.Message), true); err != nil {
+			return details, errors.Errorf("failed to send message: %v", err)
+		}
+		_, response, err := wsReadFrameOf(conn, wsOpText, wsOpBinary)
+		if err != nil {
+			return details, errors.Errorf("failed to receive response: %v", err)
+		}
+		if !strings.Contains(string(response), c.config.ExpectedResponse) {
+			return details, errors.Errorf("response does not contain expected content '%v'", c.config.ExpectedResponse)
+		}
+	}
+
+	// normal closure
+	if err := wsWriteFrame(conn, wsOpClose, []byte{0x03, 0xe8}, true); err != nil {
+		return details, errors.Errorf("failed to send close: %v", err)
+	}
+	if _, _, err := wsReadFrameOf(conn, wsOpClose); err != nil {
+		return details, errors.Errorf("failed to receive close: %v", err)
+	}
+	return details, nil
+}
+
+// handshake upgrades an HTTP connection to the WebSocket protocol, and returns the upgraded connection
+func (c *webSocketCheck) handshake(ctx context.Context) (io.ReadWriteCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.httpURL, nil)
+	if err != nil {
+		return nil, errors.Errorf("unable to create check HTTP request: %v", err)
+	}
+	keyBytes := make([]byte, 16)
+	_, _ = rand.Read(keyBytes)
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	configureHTTPOptions(req, c.config.Options)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Errorf("handshake failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = resp.Body.Close()
+		return nil, errors.Errorf("unexpected handshake status code: '%v' expected: '%v'", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	conn := resp.Body.(io.ReadWriteCloser)
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		_ = conn.Close()
+		return nil, errors.New("handshake failed: invalid Sec-WebSocket-Accept header")
+	}
+	return conn, nil
+}
+
+func wsAcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID)) // #nosec G401
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// wsWriteFrame writes a single final frame, masked as required from clients
+func wsWriteFrame(w io.Writer, opcode byte, payload []byte, masked bool) error {
+	header := []byte{0x80 | opcode, 0}
+	switch length := len(payload); {
+	case length < 126:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = 127
+		header = append(header, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if masked {
+		header[1] |= 0x80
+		mask := make([]byte, 4)
+		_, _ = rand.Read(mask)
+		header = append(header, mask...)
+		maskedPayload := make([]byte, len(payload))
+		for i := range payload {
+			maskedPayload[i] = payload[i] ^ mask[i%4]
+		}
+		payload = maskedPayload
+	}
+
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// wsReadFrame reads a single frame, and returns its opcode and unmasked payload
+func wsReadFrame(r io.Reader) (opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	opcode = header[0] & 0x0f
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+	if length > wsMaxFrameSize {
+		return 0, nil, errors.Errorf("frame of %d bytes exceeds the maximum of %d", length, wsMaxFrameSize)
+	}
+
+	var mask []byte
+	if header[1]&0x80 != 0 {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(r, mask); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if mask != nil {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+// wsReadFrameOf reads frames until one of the given opcodes, skipping others, and failing if the server closes the connection first
+func wsReadFrameOf(r io.Reader, opcodes ...byte) (opcode byte, payload []byte, err error) {
+	for {
+		opcode, payload, err = wsReadFrame(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, expected := range opcodes {
+			if opcode == expected {
+				return opcode, payload, nil
+			}
+		}
+		if opcode == wsOpClose {
+			return 0, nil, errors.New("connection closed by the server")
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webSocketEchoHandler upgrades connections, answers pings, echoes messages with the given prefix, and answers close frames
+func webSocketEchoHandler(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		reader := bufio.NewReader(rw)
+		for {
+			opcode, payload, err := wsReadFrame(reader)
+			if err != nil {
+				return
+			}
+			switch opcode {
+			case wsOpPing:
+				_ = wsWriteFrame(conn, wsOpPong, payload, false)
+			case wsOpText:
+				_ = wsWriteFrame(conn, wsOpText, append([]byte(prefix), payload...), false)
+			case wsOpClose:
+				_ = wsWriteFrame(conn, wsOpClose, payload, false)
+				return
+			}
+		}
+	}
+}
+
+func TestNewWebSocketCheck_config(t *testing.T) {
+	_, err := NewWebSocketCheck(WebSocketCheckConfig{CheckName: checkName})
+	assert.EqualError(t, err, "URL must not be empty")
+
+	_, err = NewWebSocketCheck(WebSocketCheckConfig{CheckName: checkName, URL: "http://localhost/ws"})
+	assert.EqualError(t, err, "unsupported URL scheme 'http', expected 'ws' or 'wss'")
+
+	_, err = NewWebSocketCheck(WebSocketCheckConfig{URL: "ws://localhost/ws"})
+	assert.EqualError(t, err, "CheckName must not be empty")
+}
+
+func TestNewWebSocketCheck(t *testing.T) {
+	server := httptest.NewServer(webSocketEchoHandler("echo: "))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	check, err := NewWebSocketCheck(WebSocketCheckConfig{CheckName: checkName, URL: wsURL})
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, wsURL, details)
+
+	check, err = NewWebSocketCheck(WebSocketCheckConfig{
+		CheckName:        checkName,
+		URL:              wsURL,
+		Ping:             true,
+		Message:          strings.Repeat("hello ", 100),
+		ExpectedResponse: "echo: hello",
+	})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	check, err = NewWebSocketCheck(WebSocketCheckConfig{CheckName: checkName, URL: wsURL, Message: "hello", ExpectedResponse: "pong"})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "response does not contain expected content 'pong'")
+}
+
+func TestNewWebSocketCheck_tls(t *testing.T) {
+	server := httptest.NewTLSServer(webSocketEchoHandler(""))
+	defer server.Close()
+
+	check, err := NewWebSocketCheck(WebSocketCheckConfig{
+		CheckName: checkName,
+		URL:       "wss" + strings.TrimPrefix(server.URL, "https"),
+		TLSConfig: server.Client().Transport.(*http.Transport).TLSClientConfig,
+		Ping:      true,
+	})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestNewWebSocketCheck_notUpgraded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	check, err := NewWebSocketCheck(WebSocketCheckConfig{CheckName: checkName, URL: "ws" + strings.TrimPrefix(server.URL, "http")})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "unexpected handshake status code: '200' expected: '101'")
+}
+
+func TestNewWebSocketCheck_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, _ := w.(http.Hijacker).Hijack()
+		defer func() { _ = conn.Close() }()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+		// never respond
+		time.Sleep(time.Second)
+	}))
+	defer server.Close()
+
+	check, err := NewWebSocketCheck(WebSocketCheckConfig{
+		CheckName: checkName,
+		URL:       "ws" + strings.TrimPrefix(server.URL, "http"),
+		Ping:      true,
+		Timeout:   50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to receive pong")
+}
@@ -0,0 +1,131 @@
+package checks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ZooKeeperNodeDetails are the details of a ZooKeeper ensemble node
+type ZooKeeperNodeDetails struct {
+	// OK is true when the node responded "imok" to the "ruok" command
+	OK bool `json:"ok"`
+	// Mode is the mode of the node as reported by the "srvr" command, e.g. "leader", "follower" or "standalone"
+	Mode string `json:"mode,omitempty"`
+	// Error is the error that occurred when querying the node, if any
+	Error string `json:"error,omitempty"`
+}
+
+type zooKeeperCheck struct {
+	name    string
+	servers []string
+	dialer  net.Dialer
+}
+
+// NewZooKeeperCheck returns a Check that issues the "ruok" and "srvr" four letter word commands to each of the ZooKeeper
+// ensemble servers (given as host:port addresses), and fails when fewer than a quorum of them respond "imok".
+// The response and mode of each server are reported as the check details.
+// Note that the commands must be whitelisted in the servers configuration (4lw.commands.whitelist) of ZooKeeper 3.5 and above.
+func NewZooKeeperCheck(name string, servers ...string) (gosundheit.Check, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("servers must not be empty")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &zooKeeperCheck{name: name, servers: servers}, nil
+}
+
+func (c *zooKeeperCheck) Name() string {
+	return c.name
+}
+
+func (c *zooKeeperCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	nodes := make(map[string]ZooKeeperNodeDetails, len(c.servers))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, server := range c.servers {
+		wg.Add(1)
+		go func(server string) {
+			defer wg.Done()
+			node := c.queryNode(ctx, server)
+			lock.Lock()
+			nodes[server] = node
+			lock.Unlock()
+		}(server)
+	}
+	wg.Wait()
+
+	ok := 0
+	for _, node := range nodes {
+		if node.OK {
+			ok++
+		}
+	}
+	quorum := len(c.servers)/2 + 1
+	if ok < quorum {
+		return nodes, errors.Errorf("%d out of %d servers are ok, but a quorum of %d is required", ok, len(c.servers), quorum)
+	}
+	return nodes, nil
+}
+
+func (c *zooKeeperCheck) queryNode(ctx context.Context, server string) ZooKeeperNodeDetails {
+	var node ZooKeeperNodeDetails
+
+	response, err := c.command(ctx, server, "ruok")
+	if err != nil {
+		node.Error = err.Error()
+		return node
+	}
+	if response != "imok" {
+		node.Error = fmt.Sprintf("unexpected response: '%s'", response)
+		return node
+	}
+	node.OK = true
+
+	// the mode is informational, so failing to get it does not fail the node
+	if response, err := c.command(ctx, server, "srvr"); err == nil {
+		scanner := bufio.NewScanner(strings.NewReader(response))
+		for scanner.Scan() {
+			if mode := strings.TrimPrefix(scanner.Text(), "Mode: "); mode != scanner.Text() {
+				node.Mode = mode
+			}
+		}
+	}
+	return node
+}
+
+// command sends the given four letter word command to the given server, and returns its response.
+func (c *zooKeeperCheck) command(ctx context.Context, server, cmd string) (string, error) {
+	conn, err := c.dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Second))
+	}
+
+	if _, err := conn.Write([]byte(cmd)); err != nil {
+		return "", err
+	}
+	// the server closes the connection once the response is written
+	response, err := ioutil.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(response)), nil
+}
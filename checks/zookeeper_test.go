@@ -0,0 +1,65 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zooKeeperServer starts a fake ZooKeeper server that responds to the four letter word commands with the given responses
+func zooKeeperServer(t *testing.T, responses map[string]string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				cmd := make([]byte, 4)
+				if _, err := conn.Read(cmd); err == nil {
+					_, _ = conn.Write([]byte(responses[string(cmd)]))
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestNewZooKeeperCheck_noServers(t *testing.T) {
+	check, err := NewZooKeeperCheck(checkName)
+	assert.EqualError(t, err, "servers must not be empty")
+	assert.Nil(t, check)
+}
+
+func TestNewZooKeeperCheck(t *testing.T) {
+	leader := zooKeeperServer(t, map[string]string{"ruok": "imok", "srvr": "Zookeeper version: 3.6.2\nMode: leader\nNode count: 5\n"})
+	follower := zooKeeperServer(t, map[string]string{"ruok": "imok", "srvr": "Zookeeper version: 3.6.2\nMode: follower\n"})
+	notServing := zooKeeperServer(t, map[string]string{})
+
+	check, err := NewZooKeeperCheck(checkName, leader, follower, notServing)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	details, err := check.Execute(ctx)
+	assert.NoError(t, err, "a quorum of servers is ok")
+	nodes := details.(map[string]ZooKeeperNodeDetails)
+	assert.Equal(t, ZooKeeperNodeDetails{OK: true, Mode: "leader"}, nodes[leader])
+	assert.Equal(t, ZooKeeperNodeDetails{OK: true, Mode: "follower"}, nodes[follower])
+	assert.Equal(t, ZooKeeperNodeDetails{Error: "unexpected response: ''"}, nodes[notServing])
+
+	check, _ = NewZooKeeperCheck(checkName, leader, notServing, "127.0.0.1:1")
+	details, err = check.Execute(ctx)
+	assert.EqualError(t, err, "1 out of 3 servers are ok, but a quorum of 2 is required")
+	assert.NotEmpty(t, details.(map[string]ZooKeeperNodeDetails)["127.0.0.1:1"].Error, "connection error")
+}
@@ -0,0 +1,65 @@
+package gosundheit
+
+import "time"
+
+// Clock is the source of time used for scheduling and timing the checks.
+// The default clock is the system clock. A custom Clock may be set using WithClock,
+// e.g. for driving the checks scheduling deterministically in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTicker returns a new Ticker that ticks every period d.
+	NewTicker(d time.Duration) Ticker
+	// NewTimer returns a new Timer that fires once d has elapsed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer delivers a single event on a channel once it expires, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the expiration time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, releasing its resources.
+	Stop()
+}
+
+// Ticker delivers ticks on a channel at intervals, like time.Ticker.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker.
+	Stop()
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+func (t systemTimer) Stop() {
+	t.Timer.Stop()
+}
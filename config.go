@@ -18,4 +18,40 @@ type checkConfig struct {
 	// executionTimeout is the maximum allowed execution time for a check. If this timeout is exceeded, the provided Context will be cancelled.
 	// defaults to no timeout.
 	executionTimeout time.Duration
+
+	// hardTimeout is the maximum time to wait for a check execution. If this timeout is exceeded, the execution is abandoned
+	// and the check fails with ErrExecutionTimeout. defaults to no hard timeout.
+	hardTimeout time.Duration
+
+	// severity determines whether a failure of this check makes the system unhealthy or only degraded.
+	// defaults to SeverityCritical.
+	severity SeverityLevel
+
+	// resultTTL is the maximum time an async check result is valid, after which the check fails.
+	// defaults to no TTL.
+	resultTTL time.Duration
+
+	// maxStaleness is the maximum age of a check result, after which the check is considered failing.
+	// defaults to no limit.
+	maxStaleness time.Duration
+
+	// dependencies are the names of the checks this check depends on. While any of them is failing,
+	// the check is skipped and reported as failing with ErrDependencyFailing.
+	dependencies []string
+
+	// preserveHistory indicates when true, that a check replacing a previously registered check with the same name
+	// keeps the previous result until its first execution; defaults to false.
+	preserveHistory bool
+
+	// debounceWindow is the time a change in the check health must persist before it's reported.
+	// defaults to no debouncing.
+	debounceWindow time.Duration
+
+	// unhealthyAfter is the time a check must be failing continuously before it makes the system unhealthy.
+	// defaults to zero, i.e. failures make the system unhealthy immediately.
+	unhealthyAfter time.Duration
+
+	// classifications are the classifications (e.g. liveness, readiness) the check belongs to.
+	// defaults to none.
+	classifications []Classification
 }
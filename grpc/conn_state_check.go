@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// ConnStateCheckOption configures a connectivity state check
+type ConnStateCheckOption func(c *connStateCheck)
+
+// WithConnect makes the check force an idle connection to connect, and wait (up to the check context deadline)
+// for the connection attempt to settle before inspecting the state.
+func WithConnect() ConnStateCheckOption {
+	return func(c *connStateCheck) {
+		c.connect = true
+	}
+}
+
+type connStateCheck struct {
+	name    string
+	conn    *grpc.ClientConn
+	connect bool
+}
+
+// NewGRPCConnStateCheck returns a Check that inspects the connectivity state of a gRPC client connection,
+// and fails when the connection is in TRANSIENT_FAILURE or SHUTDOWN.
+// The connectivity state is reported as the check details.
+func NewGRPCConnStateCheck(name string, conn *grpc.ClientConn, opts ...ConnStateCheckOption) (gosundheit.Check, error) {
+	if conn == nil {
+		return nil, errors.New("connection must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &connStateCheck{
+		name: name,
+		conn: conn,
+	}
+	for _, opt := range opts {
+		opt(check)
+	}
+
+	return check, nil
+}
+
+func (c *connStateCheck) Name() string {
+	return c.name
+}
+
+func (c *connStateCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	state := c.conn.GetState()
+	if c.connect {
+		state = c.awaitConnect(ctx, state)
+	}
+
+	switch state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return state.String(), errors.Errorf("connection is in %s state", state)
+	default:
+		return state.String(), nil
+	}
+}
+
+// awaitConnect connects an idle connection, and waits for it to leave the IDLE and CONNECTING states or for the context to be done.
+func (c *connStateCheck) awaitConnect(ctx context.Context, state connectivity.State) connectivity.State {
+	for state == connectivity.Idle || state == connectivity.Connecting {
+		if state == connectivity.Idle {
+			c.conn.Connect()
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return state
+		}
+		state = c.conn.GetState()
+	}
+	return state
+}
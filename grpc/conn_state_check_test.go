@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestNewGRPCConnStateCheck_nilConn(t *testing.T) {
+	check, err := NewGRPCConnStateCheck(checkName, nil)
+	assert.EqualError(t, err, "connection must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewGRPCConnStateCheck(t *testing.T) {
+	_, conn := startHealthServer(t)
+
+	check, err := NewGRPCConnStateCheck(checkName, conn)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	check, err = NewGRPCConnStateCheck(checkName, conn, WithConnect())
+	require.NoError(t, err)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "READY", details)
+
+	require.NoError(t, conn.Close())
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "connection is in SHUTDOWN state")
+	assert.Equal(t, "SHUTDOWN", details)
+}
+
+func TestNewGRPCConnStateCheck_transientFailure(t *testing.T) {
+	conn, err := grpc.Dial("unreachable",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: assert.AnError}
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	check, err := NewGRPCConnStateCheck(checkName, conn, WithConnect())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	details, err := check.Execute(ctx)
+	assert.EqualError(t, err, "connection is in TRANSIENT_FAILURE state")
+	assert.Equal(t, "TRANSIENT_FAILURE", details)
+}
@@ -0,0 +1,13 @@
+module github.com/AppsFlyer/go-sundheit/grpc
+
+go 1.15
+
+require (
+	github.com/AppsFlyer/go-sundheit v0.4.0
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.8.3
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.30.0
+)
+
+replace github.com/AppsFlyer/go-sundheit => ../
@@ -0,0 +1,54 @@
+// Package grpc provides go-sundheit checks for gRPC dependencies, and a grpc.health.v1 server backed by a gosundheit.Health.
+// It is a separate module, so that users who do not use gRPC do not depend on it.
+package grpc
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+type healthCheck struct {
+	name    string
+	client  healthpb.HealthClient
+	service string
+}
+
+// NewGRPCHealthCheck returns a Check that calls the grpc.health.v1.Health/Check method of a dependency using the given connection,
+// for the given service (or for the server overall when empty), and fails unless the service is SERVING.
+// The call honors the check context deadline. The serving status is reported as the check details.
+func NewGRPCHealthCheck(name string, conn grpc.ClientConnInterface, service string) (gosundheit.Check, error) {
+	if conn == nil {
+		return nil, errors.New("connection must not be nil")
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	return &healthCheck{
+		name:    name,
+		client:  healthpb.NewHealthClient(conn),
+		service: service,
+	}, nil
+}
+
+func (c *healthCheck) Name() string {
+	return c.name
+}
+
+func (c *healthCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	resp, err := c.client.Check(ctx, &healthpb.HealthCheckRequest{Service: c.service})
+	if err != nil {
+		return nil, errors.Wrap(err, "health check call failed")
+	}
+
+	status := resp.GetStatus()
+	if status != healthpb.HealthCheckResponse_SERVING {
+		return status.String(), errors.Errorf("service '%s' is %s", c.service, status)
+	}
+	return status.String(), nil
+}
@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const checkName = "grpc.check"
+
+// startHealthServer starts an in-memory gRPC server with the standard health service, and returns a connection to it
+func startHealthServer(t *testing.T) (*health.Server, *grpc.ClientConn) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthServer, conn
+}
+
+func TestNewGRPCHealthCheck_nilConn(t *testing.T) {
+	check, err := NewGRPCHealthCheck(checkName, nil, "")
+	assert.EqualError(t, err, "connection must not be nil")
+	assert.Nil(t, check)
+}
+
+func TestNewGRPCHealthCheck(t *testing.T) {
+	healthServer, conn := startHealthServer(t)
+
+	check, err := NewGRPCHealthCheck(checkName, conn, "payments")
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "health check call failed: rpc error: code = NotFound desc = unknown service")
+
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+	details, err := check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "SERVING", details)
+
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+	details, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "service 'payments' is NOT_SERVING")
+	assert.Equal(t, "NOT_SERVING", details)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = check.Execute(ctx)
+	assert.Error(t, err, "honors the context")
+}
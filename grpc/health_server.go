@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// HealthServerOption configures a health server
+type HealthServerOption func(s *healthServer)
+
+// WithServiceClassification maps the given gRPC service name to the health of the checks of the given classification
+// (see gosundheit.Classifications), e.g. for reporting the readiness of the server as the health of its service.
+func WithServiceClassification(service string, classification gosundheit.Classification) HealthServerOption {
+	return func(s *healthServer) {
+		s.classifications[service] = classification
+	}
+}
+
+// WithWatchInterval sets the interval at which the health is polled for status changes by Watch calls, which defaults to 1 second.
+// Non-positive intervals are ignored.
+func WithWatchInterval(interval time.Duration) HealthServerOption {
+	return func(s *healthServer) {
+		if interval > 0 {
+			s.watchInterval = interval
+		}
+	}
+}
+
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	health          gosundheit.Health
+	classifications map[string]gosundheit.Classification
+	watchInterval   time.Duration
+}
+
+// NewHealthServer returns a grpc.health.v1 HealthServer backed by the given Health, so that gRPC services can expose their health
+// without an HTTP endpoint, by registering it using healthpb.RegisterHealthServer. The serving status of a service is:
+//   - the overall health, for the empty service name
+//   - the health of the checks of a classification, for the services mapped using WithServiceClassification
+//   - the result of the check with the same name, otherwise
+//
+// Other services are unknown.
+func NewHealthServer(h gosundheit.Health, opts ...HealthServerOption) healthpb.HealthServer {
+	server := &healthServer{
+		health:          h,
+		classifications: make(map[string]gosundheit.Classification),
+		watchInterval:   time.Second,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
+
+func (s *healthServer) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	servingStatus := s.servingStatus(req.GetService())
+	if servingStatus == healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+func (s *healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	lastStatus := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		if servingStatus := s.servingStatus(req.GetService()); servingStatus != lastStatus {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+			lastStatus = servingStatus
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		}
+	}
+}
+
+func (s *healthServer) servingStatus(service string) healthpb.HealthCheckResponse_ServingStatus {
+	var healthy bool
+	if service == "" {
+		healthy = s.health.IsHealthy()
+	} else if classification, ok := s.classifications[service]; ok {
+		healthy = s.health.IsHealthyFor(classification)
+	} else {
+		results, _ := s.health.Results()
+		result, ok := results[service]
+		if !ok {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		healthy = result.IsHealthy()
+	}
+
+	if healthy {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
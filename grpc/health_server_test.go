@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// startGosundheitHealthServer starts an in-memory gRPC server with a health service backed by the given Health, and returns a client of it
+func startGosundheitHealthServer(t *testing.T, h gosundheit.Health, opts ...HealthServerOption) healthpb.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, NewHealthServer(h, opts...))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestNewHealthServer_Check(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	require.NoError(t, h.RegisterAsyncCheck("db.check", gosundheit.Classifications(gosundheit.ClassificationReadiness)))
+	require.NoError(t, h.RegisterAsyncCheck("cache.check"))
+	require.NoError(t, h.ReportResult("db.check", nil, nil))
+
+	client := startGosundheitHealthServer(t, h, WithServiceClassification("orders.v1.Orders", gosundheit.ClassificationReadiness))
+	checkStatus := func(service string) string {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err, service)
+		return resp.GetStatus().String()
+	}
+
+	assert.Equal(t, "NOT_SERVING", checkStatus(""), "overall health")
+	assert.Equal(t, "SERVING", checkStatus("orders.v1.Orders"), "classification health")
+	assert.Equal(t, "SERVING", checkStatus("db.check"), "check health")
+	assert.Equal(t, "NOT_SERVING", checkStatus("cache.check"), "check health")
+
+	require.NoError(t, h.ReportResult("cache.check", nil, nil))
+	assert.Equal(t, "SERVING", checkStatus(""), "overall health")
+
+	require.NoError(t, h.ReportResult("db.check", nil, errors.New("failing")))
+	assert.Equal(t, "NOT_SERVING", checkStatus("orders.v1.Orders"), "classification health")
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestNewHealthServer_Watch(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	require.NoError(t, h.RegisterAsyncCheck("db.check"))
+
+	client := startGosundheitHealthServer(t, h, WithWatchInterval(10*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "db.check"})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	require.NoError(t, h.ReportResult("db.check", nil, nil))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	h.Deregister("db.check")
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.GetStatus(), "unknown services are watched")
+}
+
+func TestNewHealthServer_WithWatchInterval(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		server := NewHealthServer(h, WithWatchInterval(interval)).(*healthServer)
+		assert.Equal(t, time.Second, server.watchInterval, "non-positive interval %v is ignored", interval)
+	}
+
+	client := startGosundheitHealthServer(t, h, WithWatchInterval(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
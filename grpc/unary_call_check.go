@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// UnaryCallCheckOption configures a unary call check
+type UnaryCallCheckOption func(c *unaryCallCheck)
+
+// WithExpectedCode sets the status code expected by the unary call check, which defaults to OK,
+// e.g. codes.Unauthenticated for verifying a dependency is reachable without credentials.
+func WithExpectedCode(code codes.Code) UnaryCallCheckOption {
+	return func(c *unaryCallCheck) {
+		c.expectedCode = code
+	}
+}
+
+// WithExpectedMetadata sets the unary call check to fail unless the response header or trailer metadata contains the given key,
+// with the given value when not empty.
+func WithExpectedMetadata(key, value string) UnaryCallCheckOption {
+	return func(c *unaryCallCheck) {
+		c.expectedMetadata = append(c.expectedMetadata, [2]string{strings.ToLower(key), value})
+	}
+}
+
+// WithRequestMetadata adds the given key and value to the metadata of the call, e.g. for authentication.
+func WithRequestMetadata(key, value string) UnaryCallCheckOption {
+	return func(c *unaryCallCheck) {
+		c.requestMetadata = append(c.requestMetadata, key, value)
+	}
+}
+
+// UnaryCallDetails are the details of a unary call check
+type UnaryCallDetails struct {
+	// Code is the status code of the call
+	Code string `json:"code"`
+	// Message is the status message of the call, if any
+	Message string        `json:"message,omitempty"`
+	Latency time.Duration `json:"latency"`
+}
+
+type unaryCallCheck struct {
+	name             string
+	conn             grpc.ClientConnInterface
+	method           string
+	request          []byte
+	expectedCode     codes.Code
+	expectedMetadata [][2]string
+	requestMetadata  []string
+}
+
+// NewGRPCUnaryCallCheck returns a Check that invokes an arbitrary unary method of a dependency using the given connection,
+// for dependencies which do not implement the grpc.health.v1 protocol. The method is the full method name, e.g. "/package.Service/Method",
+// and the request is the encoded request message (e.g. using proto.Marshal), which may be empty for a message with default values.
+// The response message is not decoded, so the check does not depend on the generated code of the dependency.
+// The check fails unless the call returns the expected status code (see WithExpectedCode) and metadata (see WithExpectedMetadata).
+// The call honors the check context deadline. The status and latency of the call are reported as the check details.
+func NewGRPCUnaryCallCheck(name string, conn grpc.ClientConnInterface, method string, request []byte, opts ...UnaryCallCheckOption) (gosundheit.Check, error) {
+	if conn == nil {
+		return nil, errors.New("connection must not be nil")
+	}
+	if !strings.HasPrefix(method, "/") || strings.Count(method, "/") != 2 || strings.HasSuffix(method, "/") {
+		return nil, errors.Errorf("invalid method '%s', expected a full method name e.g. '/package.Service/Method'", method)
+	}
+	if name == "" {
+		return nil, errors.New("check name must not be empty")
+	}
+
+	check := &unaryCallCheck{
+		name:         name,
+		conn:         conn,
+		method:       method,
+		request:      request,
+		expectedCode: codes.OK,
+	}
+	for _, opt := range opts {
+		opt(check)
+	}
+
+	return check, nil
+}
+
+func (c *unaryCallCheck) Name() string {
+	return c.name
+}
+
+func (c *unaryCallCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	if len(c.requestMetadata) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, c.requestMetadata...)
+	}
+
+	var header, trailer metadata.MD
+	var response rawMessage
+	start := time.Now()
+	err = c.conn.Invoke(ctx, c.method, rawMessage(c.request), &response,
+		grpc.ForceCodec(rawCodec{}), grpc.Header(&header), grpc.Trailer(&trailer))
+	st := status.Convert(err)
+	callDetails := UnaryCallDetails{Code: st.Code().String(), Message: st.Message(), Latency: time.Since(start)}
+
+	if st.Code() != c.expectedCode {
+		return callDetails, errors.Errorf("unexpected status code: '%s' expected: '%s'", st.Code(), c.expectedCode)
+	}
+	for _, expected := range c.expectedMetadata {
+		key, value := expected[0], expected[1]
+		values := append(header.Get(key), trailer.Get(key)...)
+		if len(values) == 0 {
+			return callDetails, errors.Errorf("metadata '%s' is missing", key)
+		}
+		if value != "" && !contains(values, value) {
+			return callDetails, errors.Errorf("metadata '%s' is %v, expected: '%s'", key, values, value)
+		}
+	}
+	return callDetails, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// rawMessage is an encoded message, which is sent and received as is by rawCodec
+type rawMessage []byte
+
+// rawCodec is a codec which passes encoded messages through, so that arbitrary methods can be invoked without their generated code.
+// It is named "proto" so that the content type of the call is the one expected by protobuf services.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(rawMessage)
+	if !ok {
+		return nil, errors.Errorf("unexpected message type %T", v)
+	}
+	return message, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(*rawMessage)
+	if !ok {
+		return errors.Errorf("unexpected message type %T", v)
+	}
+	*message = append((*message)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
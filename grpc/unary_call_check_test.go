@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// startUnaryServer starts an in-memory gRPC server with the standard health service, which also serves any other method
+// by responding with the request metadata as the response header, and returns a connection to it
+func startUnaryServer(t *testing.T) (*health.Server, *grpc.ClientConn) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&healthpb.HealthCheckRequest{}); err != nil {
+			return err
+		}
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		if len(md.Get("authorization")) == 0 {
+			return status.Error(codes.Unauthenticated, "missing credentials")
+		}
+		if err := stream.SetHeader(metadata.Pairs("x-version", "2")); err != nil {
+			return err
+		}
+		return stream.SendMsg(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+	}))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthServer, conn
+}
+
+func TestNewGRPCUnaryCallCheck_config(t *testing.T) {
+	_, conn := startUnaryServer(t)
+
+	_, err := NewGRPCUnaryCallCheck(checkName, nil, "/orders.Orders/Get", nil)
+	assert.EqualError(t, err, "connection must not be nil")
+
+	for _, method := range []string{"", "orders.Orders/Get", "/orders.Orders", "/orders.Orders/", "/orders/Orders/Get"} {
+		_, err = NewGRPCUnaryCallCheck(checkName, conn, method, nil)
+		assert.EqualError(t, err, "invalid method '"+method+"', expected a full method name e.g. '/package.Service/Method'")
+	}
+
+	_, err = NewGRPCUnaryCallCheck("", conn, "/orders.Orders/Get", nil)
+	assert.EqualError(t, err, "check name must not be empty")
+}
+
+func TestNewGRPCUnaryCallCheck(t *testing.T) {
+	healthServer, conn := startUnaryServer(t)
+	request, err := proto.Marshal(&healthpb.HealthCheckRequest{Service: "payments"})
+	require.NoError(t, err)
+
+	check, err := NewGRPCUnaryCallCheck(checkName, conn, "/grpc.health.v1.Health/Check", request)
+	require.NoError(t, err)
+	assert.Equal(t, checkName, check.Name(), "check name")
+
+	details, err := check.Execute(context.Background())
+	assert.EqualError(t, err, "unexpected status code: 'NotFound' expected: 'OK'")
+	assert.Equal(t, "NotFound", details.(UnaryCallDetails).Code)
+	assert.Equal(t, "unknown service", details.(UnaryCallDetails).Message)
+
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+	details, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", details.(UnaryCallDetails).Code)
+	assert.True(t, details.(UnaryCallDetails).Latency > 0, "latency")
+
+	check, err = NewGRPCUnaryCallCheck(checkName, conn, "/orders.Orders/Get", nil, WithExpectedCode(codes.Unauthenticated))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err, "expected code")
+}
+
+func TestNewGRPCUnaryCallCheck_metadata(t *testing.T) {
+	_, conn := startUnaryServer(t)
+
+	check, err := NewGRPCUnaryCallCheck(checkName, conn, "/orders.Orders/Get", nil,
+		WithRequestMetadata("authorization", "Bearer token"), WithExpectedMetadata("X-Version", "2"))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.NoError(t, err)
+
+	check, err = NewGRPCUnaryCallCheck(checkName, conn, "/orders.Orders/Get", nil,
+		WithRequestMetadata("authorization", "Bearer token"), WithExpectedMetadata("x-version", "3"))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "metadata 'x-version' is [2], expected: '3'")
+
+	check, err = NewGRPCUnaryCallCheck(checkName, conn, "/orders.Orders/Get", nil,
+		WithRequestMetadata("authorization", "Bearer token"), WithExpectedMetadata("x-region", ""))
+	require.NoError(t, err)
+	_, err = check.Execute(context.Background())
+	assert.EqualError(t, err, "metadata 'x-region' is missing")
+}
@@ -2,6 +2,8 @@ package gosundheit
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,28 +15,93 @@ type Health interface {
 	// RegisterCheck registers a health check according to the given configuration.
 	// Once RegisterCheck() is called, the check is scheduled to run in it's own goroutine.
 	// Callers must make sure the checks complete at a reasonable time frame, or the next execution will delay.
+	// If a check with the same name is already registered, it is atomically replaced by the new check:
+	// the old check is stopped, and its result is replaced without a window where the name is missing.
 	RegisterCheck(check Check, opts ...CheckOption) error
+	// RegisterCheckFunc registers a health check with the given name, that is implemented by the given function.
+	// It is a shorthand for registering a Check that has the given name, and executes the given function.
+	RegisterCheckFunc(name string, fn ExecuteFunc, opts ...CheckOption) error
+	// RegisterChecks registers multiple health checks atomically: all the registrations are validated first,
+	// and either all checks are registered, or none are. In case of validation errors, they are all returned joined into a single error.
+	RegisterChecks(registrations ...CheckRegistration) error
+	// RegisterHealth registers another Health instance as a sub-tree of this instance, under the given name.
+	// The sub instance is checked like any other check (according to the given options), where its results are reported
+	// hierarchically as the check details, and the check fails when the sub instance is unhealthy.
+	RegisterHealth(name string, sub Health, opts ...CheckOption) error
+	// RegisterAsyncCheck registers a check with the given name, whose results are pushed by the caller using ReportResult(),
+	// rather than being polled on schedule. This is useful for event driven health signals.
+	// When the ResultTTL option is set, the check fails if no result has been reported within the TTL.
+	RegisterAsyncCheck(name string, opts ...CheckOption) error
+	// ReportResult reports the result of an async check, that was registered using RegisterAsyncCheck().
+	// An error is returned when there is no such async check.
+	ReportResult(name string, details interface{}, err error) error
 	// Deregister removes a health check from this instance, and stops it's next executions.
 	// If the check is running while Deregister() is called, the check may complete it's current execution.
 	// Once a check is removed, it's results are no longer returned.
 	Deregister(name string)
 	// Results returns a snapshot of the health checks execution results at the time of calling, and the current health.
-	// A system is considered healthy iff all checks are passing
+	// A system is considered healthy iff all critical checks are passing
 	Results() (results map[string]Result, healthy bool)
+	// OrderedResults is the same as Results(), but returns the results as a slice sorted by check name,
+	// which is useful for a stable representation in logs, responses and tests.
+	OrderedResults() (results []NamedResult, healthy bool)
 	// IsHealthy returns the current health of the system.
-	// A system is considered healthy iff all checks are passing.
+	// A system is considered healthy iff all critical checks are passing.
 	IsHealthy() bool
+	// ResultsFor is the same as Results(), but only for the checks of the given classification (see the Classifications option).
+	// The health is computed from these checks alone.
+	ResultsFor(classification Classification) (results map[string]Result, healthy bool)
+	// IsHealthyFor returns the current health of the checks of the given classification.
+	IsHealthyFor(classification Classification) bool
+	// FilteredResults is the same as Results(), but only for the checks whose names match the given filter.
+	// The health is computed from these checks alone.
+	FilteredResults(filter func(name string) bool) (results map[string]Result, healthy bool)
+	// Status returns the current status of the system:
+	// StatusHealthy when all checks are passing, StatusDegraded when only non critical checks are failing,
+	// and StatusUnhealthy when at least one critical check is failing.
+	// When a HealthPolicy is set, the system is StatusUnhealthy when the policy deems it unhealthy,
+	// and StatusDegraded when the policy deems it healthy while some checks are failing.
+	Status() Status
+	// Summary returns an aggregated view of the current results: the overall status, the check counts by state,
+	// the oldest failure time and the last update time.
+	Summary() Summary
+	// Override pins the result of the check with the given name to the given result until the given time, e.g. during planned maintenance.
+	// The check keeps executing, but its results are replaced by the override, which is marked by the OverriddenUntil field.
+	// The override expires automatically, and can be cleared earlier by overriding with a time in the past.
+	// An error is returned when there is no such check.
+	Override(name string, result Result, until time.Time) error
+	// Snapshot returns the serialized state of the checks results, including their failure counters,
+	// which can be restored into a new Health instance using the WithRestoredState option (e.g. after a restart).
+	Snapshot() ([]byte, error)
+	// ListChecks returns the configuration of the registered checks, and their next scheduled execution time, sorted by name.
+	ListChecks() []CheckInfo
+	// AwaitFirstExecution blocks until each of the checks with the given names has completed its first execution,
+	// or reported its first result in case of async checks. When no names are given, it waits for all the currently registered checks.
+	// Checks that are not registered yet are waited for as well. The context error is returned if the context is done first.
+	AwaitFirstExecution(ctx context.Context, names ...string) error
+	// AwaitHealthy blocks until the system is healthy, or returns the context error if the context is done first.
+	AwaitHealthy(ctx context.Context) error
 	// DeregisterAll Deregister removes all health checks from this instance, and stops their next executions.
 	// It is equivalent of calling Deregister() for each currently registered check.
 	DeregisterAll()
+	// DeregisterAndWait is the same as Deregister(), but blocks until the check is stopped and its result is removed,
+	// including the completion of its current execution, if any.
+	// The context error is returned if the context is done first.
+	DeregisterAndWait(ctx context.Context, name string) error
+	// DeregisterAllAndWait is the same as DeregisterAll(), but blocks until all the checks are stopped and their results are removed.
+	// The context error is returned if the context is done first.
+	DeregisterAllAndWait(ctx context.Context) error
 }
 
 // New returns a new Health instance.
 func New(opts ...HealthOption) Health {
 	h := &health{
-		ctx:        context.TODO(),
-		results:    make(map[string]Result, maxExpectedChecks),
-		checkTasks: make(map[string]checkTask, maxExpectedChecks),
+		ctx:         context.Background(),
+		clock:       systemClock{},
+		results:     make(map[string]Result, maxExpectedChecks),
+		checkTasks:  make(map[string]*checkTask, maxExpectedChecks),
+		updated:     make(chan struct{}),
+		lastHealthy: true,
 	}
 	for _, opt := range append(opts, WithDefaults()) {
 		opt.apply(h)
@@ -44,42 +111,197 @@ func New(opts ...HealthOption) Health {
 }
 
 type health struct {
-	ctx            context.Context
-	results        map[string]Result
-	checkTasks     map[string]checkTask
-	checksListener CheckListeners
-	healthListener HealthListeners
-	lock           sync.RWMutex
+	ctx                  context.Context
+	results              map[string]Result
+	checkTasks           map[string]*checkTask
+	checksListener       CheckListeners
+	healthListener       HealthListeners
+	healthChangeListener HealthChangeListeners
+	lock                 sync.RWMutex
+	executionSlots       chan struct{}
+	policy               HealthPolicy
+	clock                Clock
+	interceptors         []Interceptor
+	// updated is closed and replaced whenever results are updated, to wake up awaiting callers.
+	updated chan struct{}
+	// lastHealthy is the overall health last reported to the listeners.
+	lastHealthy bool
+	// notificationLock serializes the health listeners notifications, so that they are delivered in the order of the transitions.
+	notificationLock sync.Mutex
+	// started is set once all the startup checks passed (see ClassificationStartup).
+	started bool
+	// manualExecution is set when checks are executed on demand rather than by the task go routines.
+	manualExecution      bool
+	minExecutionInterval time.Duration
+	// executionLock serializes on demand executions, so that concurrent queries don't execute the same checks.
+	executionLock sync.Mutex
+	// restoredState is the restored state of the checks that were not registered yet, by name.
+	restoredState map[string]checkState
 
 	// Check config defaults
 	defaultExecutionPeriod  time.Duration
 	defaultInitialDelay     time.Duration
 	defaultInitiallyPassing bool
+	defaultMaxStaleness     time.Duration
 }
 
 func (h *health) RegisterCheck(check Check, opts ...CheckOption) error {
+	return h.RegisterChecks(CheckRegistration{Check: check, Options: opts})
+}
+
+func (h *health) RegisterCheckFunc(name string, fn ExecuteFunc, opts ...CheckOption) error {
+	if fn == nil {
+		return errors.New("check function must not be nil")
+	}
+
+	return h.RegisterCheck(&funcCheck{name: name, execute: fn}, opts...)
+}
+
+func (h *health) RegisterChecks(registrations ...CheckRegistration) error {
+	var errs joinedErrors
+	pending := make([]pendingRegistration, 0, len(registrations))
+	names := make(map[string]bool, len(registrations))
+	for _, registration := range registrations {
+		p, err := h.prepareRegistration(registration.Check, registration.Options)
+		if err == nil && names[p.check.Name()] {
+			err = errors.New("check is registered more than once")
+		}
+		if err != nil {
+			if len(registrations) > 1 && registration.Check != nil {
+				err = errors.Wrapf(err, "check '%s'", registration.Check.Name())
+			}
+			errs = append(errs, err)
+			continue
+		}
+		names[p.check.Name()] = true
+		pending = append(pending, p)
+	}
+	if len(errs) > 0 {
+		return errs.join()
+	}
+
+	tasks, err := h.createCheckTasks(pending)
+	if err != nil {
+		return err
+	}
+	for i, task := range tasks {
+		h.checksListener.OnCheckRegistered(task.check.Name(), pending[i].result)
+	}
+	for _, task := range tasks {
+		h.scheduleCheck(task, task.initialDelay, task.executionPeriod)
+	}
+	return nil
+}
+
+// pendingRegistration is a validated check registration
+type pendingRegistration struct {
+	check Check
+	cfg   checkConfig
+	// result is the initial result of the check, set once its task is created
+	result Result
+}
+
+// prepareRegistration validates the given check registration, and applies the check interceptors.
+func (h *health) prepareRegistration(check Check, opts []CheckOption) (pendingRegistration, error) {
 	if check == nil {
-		return errors.New("check must not be nil")
+		return pendingRegistration{}, errors.New("check must not be nil")
 	}
 	if check.Name() == "" {
-		return errors.New("check name must not be empty")
+		return pendingRegistration{}, errors.New("check name must not be empty")
+	}
+	if err := h.ctx.Err(); err != nil {
+		return pendingRegistration{}, errors.Wrap(err, "health context is done")
 	}
 
 	cfg := h.initCheckConfig(opts)
 
 	if cfg.executionPeriod <= 0 {
-		return errors.New("execution period must be greater than 0")
+		return pendingRegistration{}, errors.New("execution period must be greater than 0")
+	}
+	for _, dependency := range cfg.dependencies {
+		if dependency == check.Name() {
+			return pendingRegistration{}, errors.New("check must not depend on itself")
+		}
 	}
 
-	// checks are initially failing by default, but we allow overrides...
-	var initialErr error
-	if !cfg.initiallyPassing {
-		initialErr = ErrNotRunYet
+	intercepted := intercept(check, h.interceptors)
+	if intercepted == nil || intercepted.Name() != check.Name() {
+		return pendingRegistration{}, errors.New("check interceptors must preserve the check name")
+	}
+
+	return pendingRegistration{check: intercepted, cfg: cfg}, nil
+}
+
+func (h *health) RegisterHealth(name string, sub Health, opts ...CheckOption) error {
+	if sub == nil {
+		return errors.New("health must not be nil")
 	}
+	if sub == Health(h) {
+		return errors.New("health must not be registered into itself")
+	}
+
+	return h.RegisterCheck(&subHealthCheck{name: name, sub: sub}, opts...)
+}
 
-	result := h.updateResult(check.Name(), ErrNotRunYet.Error(), 0, initialErr, time.Now())
-	h.checksListener.OnCheckRegistered(check.Name(), result)
-	h.scheduleCheck(h.createCheckTask(check, cfg.executionTimeout), cfg.initialDelay, cfg.executionPeriod)
+func (h *health) RegisterAsyncCheck(name string, opts ...CheckOption) error {
+	if name == "" {
+		return errors.New("check name must not be empty")
+	}
+	if err := h.ctx.Err(); err != nil {
+		return errors.Wrap(err, "health context is done")
+	}
+
+	cfg := h.initCheckConfig(opts)
+
+	task, result := h.createCheckTask(&asyncCheck{name: name}, cfg)
+	h.checksListener.OnCheckRegistered(name, result)
+	h.scheduleAsyncCheck(task, cfg.resultTTL)
+	return nil
+}
+
+func (h *health) ReportResult(name string, details interface{}, err error) error {
+	h.lock.Lock()
+	task, ok := h.checkTasks[name]
+	if !ok || !task.isAsync() {
+		h.lock.Unlock()
+		return errors.Errorf("no async check named '%s' is registered", name)
+	}
+	if task.startupCompleted {
+		// completed startup checks are no longer evaluated
+		h.lock.Unlock()
+		return nil
+	}
+	result, _ := h.recordExecutionLocked(task, details, 0, err, h.clock.Now())
+	h.lock.Unlock()
+
+	h.checksListener.OnCheckCompleted(name, result)
+	h.reportResults()
+
+	// reset the TTL timer, unless there's already a pending reset
+	select {
+	case task.reportChan <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (h *health) Override(name string, result Result, until time.Time) error {
+	h.lock.Lock()
+	task, ok := h.checkTasks[name]
+	if !ok {
+		h.lock.Unlock()
+		return errors.Errorf("no check named '%s' is registered", name)
+	}
+	if result.Timestamp.IsZero() {
+		result.Timestamp = h.clock.Now()
+	}
+	result.Error = newMarshalableError(result.Error)
+	task.override = &result
+	task.overrideUntil = until
+	h.notifyUpdated()
+	h.lock.Unlock()
+
+	h.reportResults()
 	return nil
 }
 
@@ -88,6 +310,7 @@ func (h *health) initCheckConfig(opts []CheckOption) checkConfig {
 		executionPeriod:  h.defaultExecutionPeriod,
 		initialDelay:     h.defaultInitialDelay,
 		initiallyPassing: h.defaultInitiallyPassing,
+		maxStaleness:     h.defaultMaxStaleness,
 	}
 
 	for _, opt := range opts {
@@ -97,43 +320,194 @@ func (h *health) initCheckConfig(opts []CheckOption) checkConfig {
 	return cfg
 }
 
-func (h *health) createCheckTask(check Check, timeout time.Duration) *checkTask {
+// createCheckTasks atomically creates the tasks of the given registrations, and sets their initial results.
+func (h *health) createCheckTasks(pending []pendingRegistration) ([]*checkTask, error) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
-	task := checkTask{
-		stopChan: make(chan bool, 1),
-		check:    check,
-		timeout:  timeout,
+	// checked while holding the lock, so that concurrent registrations can not form a cycle either
+	if cycle := h.dependencyCycleLocked(pending); cycle != nil {
+		return nil, errors.Errorf("check dependencies must not form a cycle: %s", strings.Join(cycle, " -> "))
 	}
-	h.checkTasks[check.Name()] = task
 
-	return &task
+	tasks := make([]*checkTask, len(pending))
+	for i := range pending {
+		tasks[i], pending[i].result = h.createCheckTaskLocked(pending[i].check, pending[i].cfg)
+	}
+	return tasks, nil
 }
 
-func (h *health) stopCheckTask(name string) {
+// dependencyCycleLocked returns a cycle of dependencies that the given registrations would form with the registered checks,
+// starting and ending with the same check name, or nil if there's none. Such checks would be skipped forever, since each waits
+// for another to pass. Async checks are never skipped, so their dependencies are ignored. It must be called while holding the lock.
+func (h *health) dependencyCycleLocked(pending []pendingRegistration) []string {
+	dependencies := make(map[string][]string, len(h.checkTasks)+len(pending))
+	for name, task := range h.checkTasks {
+		if !task.isAsync() {
+			dependencies[name] = task.dependencies
+		}
+	}
+	for _, p := range pending {
+		dependencies[p.check.Name()] = p.cfg.dependencies
+	}
+
+	// a depth first search, where the checks on the current path are visiting
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := make(map[string]int, len(dependencies))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch states[name] {
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		states[name] = visiting
+		path = append(path, name)
+		for _, dependency := range dependencies[name] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		states[name] = visited
+		return nil
+	}
+
+	// any new cycle passes through one of the registrations
+	for _, p := range pending {
+		if cycle := visit(p.check.Name()); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// createCheckTask creates a task for the given check along with its initial result.
+// A previously registered task with the same name is stopped and replaced.
+func (h *health) createCheckTask(check Check, cfg checkConfig) (*checkTask, Result) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
-	task := h.checkTasks[name]
+	return h.createCheckTaskLocked(check, cfg)
+}
+
+// createCheckTaskLocked is the same as createCheckTask, but must be called while holding the lock.
+func (h *health) createCheckTaskLocked(check Check, cfg checkConfig) (*checkTask, Result) {
+	task := &checkTask{
+		stopChan:        make(chan bool, 1),
+		doneChan:        make(chan struct{}),
+		reportChan:      make(chan struct{}, 1),
+		check:           check,
+		timeout:         cfg.executionTimeout,
+		hardTimeout:     cfg.hardTimeout,
+		severity:        cfg.severity,
+		maxStaleness:    cfg.maxStaleness,
+		dependencies:    cfg.dependencies,
+		debounceWindow:  cfg.debounceWindow,
+		classifications: cfg.classifications,
+		unhealthyAfter:  cfg.unhealthyAfter,
+		executionPeriod: cfg.executionPeriod,
+		initialDelay:    cfg.initialDelay,
+		resultTTL:       cfg.resultTTL,
+	}
+	if !task.isAsync() {
+		task.nextRun = h.clock.Now().Add(cfg.initialDelay)
+	}
+	name := check.Name()
+	prevTask, replacing := h.checkTasks[name]
+	h.checkTasks[name] = task
+	if replacing {
+		h.requestStopLocked(prevTask)
+	}
+
+	if prevResult, ok := h.results[name]; ok && replacing && cfg.preserveHistory {
+		task.lastResult = prevTask.lastResult
+		return task, prevResult
+	}
+
+	// checks are initially failing by default, but we allow overrides...
+	var initialErr error
+	if !cfg.initiallyPassing {
+		initialErr = ErrNotRunYet
+	}
+
+	delete(h.results, name)
+	if result, ok := h.restoreResultLocked(task); ok {
+		return task, result
+	}
+	return task, h.updateResultLocked(name, ErrNotRunYet.Error(), 0, initialErr, h.clock.Now())
+}
+
+// requestStopLocked requests the given task to stop. The task go routine cleans up after itself,
+// except in manual execution mode where there's no such go routine, and the task is stopped right away.
+// It must be called while holding the lock.
+func (h *health) requestStopLocked(task *checkTask) {
+	if h.manualExecution {
+		h.stopCheckTaskLocked(task)
+		return
+	}
+	task.requestStop()
+}
+
+// stopCheckTask stops the given task, and removes it along with its result, unless it has already been replaced.
+func (h *health) stopCheckTask(task *checkTask) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.stopCheckTaskLocked(task)
+}
+
+// stopCheckTaskLocked is the same as stopCheckTask, but must be called while holding the lock.
+func (h *health) stopCheckTaskLocked(task *checkTask) {
+	select {
+	case <-task.doneChan:
+		// already stopped
+		return
+	default:
+	}
 
 	task.stop()
+	close(task.doneChan)
+
+	name := task.check.Name()
+	if h.checkTasks[name] != task {
+		return
+	}
 
 	delete(h.results, name)
 	delete(h.checkTasks, name)
+	h.notifyUpdated()
 }
 
 func (h *health) scheduleCheck(task *checkTask, initialDelay, executionPeriod time.Duration) {
+	if h.manualExecution {
+		return
+	}
+
 	go func() {
 		// initial execution
-		if !h.runCheckOrStop(task, time.After(initialDelay)) {
+		timer := h.clock.NewTimer(initialDelay)
+		ok := h.runCheckOrStop(task, timer.C())
+		timer.Stop()
+		if !ok {
 			return
 		}
 		h.reportResults()
 		// scheduled recurring execution
-		task.ticker = time.NewTicker(executionPeriod)
+		h.setNextRun(task, h.clock.Now().Add(executionPeriod))
+		task.ticker = h.clock.NewTicker(executionPeriod)
 		for {
-			if !h.runCheckOrStop(task, task.ticker.C) {
+			if !h.runCheckOrStop(task, task.ticker.C()) {
 				return
 			}
 			h.reportResults()
@@ -141,80 +515,608 @@ func (h *health) scheduleCheck(task *checkTask, initialDelay, executionPeriod ti
 	}()
 }
 
+func (h *health) scheduleAsyncCheck(task *checkTask, ttl time.Duration) {
+	if h.manualExecution {
+		return
+	}
+
+	go func() {
+		for {
+			if !h.awaitAsyncResult(task, ttl) {
+				return
+			}
+		}
+	}()
+}
+
+// awaitAsyncResult waits for a result of the given async task to be reported within the TTL, and fails the check once the TTL expires.
+// It returns false once the task is stopped.
+func (h *health) awaitAsyncResult(task *checkTask, ttl time.Duration) bool {
+	var expired <-chan time.Time
+	if ttl > 0 {
+		// stopped once a result is reported, otherwise every report would leave a pending timer behind
+		timer := h.clock.NewTimer(ttl)
+		defer timer.Stop()
+		expired = timer.C()
+	}
+
+	select {
+	case <-task.stopChan:
+		h.stopCheckTask(task)
+		return false
+	case <-h.ctx.Done():
+		h.stopCheckTask(task)
+		return false
+	case <-task.reportChan:
+		// a result was reported in time, start a new TTL period
+	case t := <-expired:
+		if h.isStartupCompleted(task) {
+			return true
+		}
+		if result, ok := h.updateTaskResult(task, nil, 0, ErrResultExpired, t); ok {
+			h.checksListener.OnCheckCompleted(task.check.Name(), result)
+			h.reportResults()
+		}
+	}
+	return true
+}
+
 func (h *health) reportResults() {
-	h.lock.RLock()
-	resultsCopy := copyResultsMap(h.results)
-	h.lock.RUnlock()
+	// held from computing the transition until it's delivered, otherwise concurrent reports may deliver it out of order
+	h.notificationLock.Lock()
+	defer h.notificationLock.Unlock()
+
+	h.lock.Lock()
+	resultsCopy := h.resultsSnapshot()
+	healthy := h.status(resultsCopy) != StatusUnhealthy
+	previous := h.lastHealthy
+	h.lastHealthy = healthy
+	h.lock.Unlock()
+
 	h.healthListener.OnResultsUpdated(resultsCopy)
+	if healthy != previous {
+		h.healthChangeListener.OnHealthChanged(previous, healthy, resultsCopy)
+	}
 }
 
 func (h *health) runCheckOrStop(task *checkTask, timerChan <-chan time.Time) bool {
 	select {
 	case <-task.stopChan:
-		h.stopCheckTask(task.check.Name())
+		h.stopCheckTask(task)
+		return false
+	case <-h.ctx.Done():
+		h.stopCheckTask(task)
 		return false
 	case t := <-timerChan:
+		h.setNextRun(task, t.Add(task.executionPeriod))
 		h.checkAndUpdateResult(task, t)
 		return true
 	}
 }
 
+func (h *health) setNextRun(task *checkTask, t time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	task.nextRun = t
+}
+
 func (h *health) checkAndUpdateResult(task *checkTask, checkTime time.Time) {
+	if h.isStartupCompleted(task) {
+		return
+	}
+	if failing := h.failingDependencies(task); len(failing) > 0 {
+		if result, ok := h.recordExecution(task, failing, 0, ErrDependencyFailing, checkTime); ok {
+			h.checksListener.OnCheckCompleted(task.check.Name(), result)
+		}
+		return
+	}
+
+	if !h.acquireExecutionSlot() {
+		return
+	}
+
 	h.checksListener.OnCheckStarted(task.check.Name())
-	details, duration, err := task.execute(h.ctx)
-	result := h.updateResult(task.check.Name(), details, duration, err, checkTime)
-	h.checksListener.OnCheckCompleted(task.check.Name(), result)
+	// an abandoned execution keeps its slot until it returns, so the limit caps the actually running checks
+	details, duration, err, timedOut := task.execute(h.ctx, h.clock, h.releaseExecutionSlot)
+	if result, ok := h.recordExecution(task, details, duration, err, checkTime); ok {
+		if timedOut {
+			h.checksListener.OnCheckTimeout(task.check.Name(), result)
+		}
+		h.checksListener.OnCheckCompleted(task.check.Name(), result)
+	}
 }
 
-func (h *health) Deregister(name string) {
+// failingDependencies returns the names of the dependencies of the given task that are currently failing or missing.
+func (h *health) failingDependencies(task *checkTask) (failing []string) {
+	if len(task.dependencies) == 0 {
+		return nil
+	}
+
 	h.lock.RLock()
 	defer h.lock.RUnlock()
 
+	now := h.clock.Now()
+	for _, dependency := range task.dependencies {
+		result, ok := h.results[dependency]
+		if !ok || !result.IsHealthy() || h.checkTasks[dependency].isStale(result, now) {
+			failing = append(failing, dependency)
+		}
+	}
+
+	return failing
+}
+
+// acquireExecutionSlot blocks until the number of concurrently executing checks is below the configured limit.
+// It returns false if the health context is done before a slot is available.
+func (h *health) acquireExecutionSlot() bool {
+	if h.executionSlots == nil {
+		return true
+	}
+	select {
+	case h.executionSlots <- struct{}{}:
+		return true
+	case <-h.ctx.Done():
+		return false
+	}
+}
+
+func (h *health) releaseExecutionSlot() {
+	if h.executionSlots != nil {
+		<-h.executionSlots
+	}
+}
+
+func (h *health) Deregister(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
 	task, ok := h.checkTasks[name]
 	if ok {
-		// actual cleanup happens in the task go routine
-		task.stopChan <- true
+		h.requestStopLocked(task)
+	}
+}
+
+func (h *health) DeregisterAndWait(ctx context.Context, name string) error {
+	h.lock.Lock()
+	task, ok := h.checkTasks[name]
+	if ok {
+		h.requestStopLocked(task)
+	}
+	h.lock.Unlock()
+
+	if !ok {
+		return nil
 	}
+	return task.awaitDone(ctx)
 }
 
 func (h *health) DeregisterAll() {
-	h.lock.RLock()
-	defer h.lock.RUnlock()
+	h.lock.Lock()
+	defer h.lock.Unlock()
 
 	for _, task := range h.checkTasks {
-		task.stopChan <- true
+		h.requestStopLocked(task)
 	}
 }
 
+func (h *health) DeregisterAllAndWait(ctx context.Context) error {
+	h.lock.Lock()
+	tasks := make([]*checkTask, 0, len(h.checkTasks))
+	for _, task := range h.checkTasks {
+		h.requestStopLocked(task)
+		tasks = append(tasks, task)
+	}
+	h.lock.Unlock()
+
+	for _, task := range tasks {
+		if err := task.awaitDone(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (h *health) Results() (results map[string]Result, healthy bool) {
+	h.executeDueChecks()
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	results = h.resultsSnapshot()
+	healthy = h.status(results) != StatusUnhealthy
+
+	return
+}
+
+func (h *health) OrderedResults() (results []NamedResult, healthy bool) {
+	resultsMap, healthy := h.Results()
+	return sortedResults(resultsMap), healthy
+}
+
+func (h *health) IsHealthy() (healthy bool) {
+	h.executeDueChecks()
+
 	h.lock.RLock()
 	defer h.lock.RUnlock()
 
-	results = make(map[string]Result, len(h.results))
+	return h.status(h.resultsSnapshot()) != StatusUnhealthy
+}
+
+func (h *health) ResultsFor(classification Classification) (results map[string]Result, healthy bool) {
+	h.executeDueChecks()
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
 
-	healthy = true
-	for k, v := range h.results {
-		results[k] = v
-		healthy = healthy && v.IsHealthy()
+	results = h.resultsSnapshot()
+	for name := range results {
+		if task, ok := h.checkTasks[name]; !ok || !task.isClassified(classification) {
+			delete(results, name)
+		}
 	}
+	// a started system does not go back to starting
+	healthy = (classification == ClassificationStartup && h.started) || h.status(results) != StatusUnhealthy
 
 	return
 }
 
-func (h *health) IsHealthy() (healthy bool) {
+func (h *health) IsHealthyFor(classification Classification) bool {
+	_, healthy := h.ResultsFor(classification)
+	return healthy
+}
+
+func (h *health) FilteredResults(filter func(name string) bool) (results map[string]Result, healthy bool) {
+	h.executeDueChecks()
+
 	h.lock.RLock()
 	defer h.lock.RUnlock()
 
-	return allHealthy(h.results)
+	results = h.resultsSnapshot()
+	for name := range results {
+		if !filter(name) {
+			delete(results, name)
+		}
+	}
+	healthy = h.status(results) != StatusUnhealthy
+
+	return
 }
 
-func (h *health) updateResult(
-	name string, details interface{}, checkDuration time.Duration, err error, t time.Time) (result Result) {
+func (h *health) Status() Status {
+	h.executeDueChecks()
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.status(h.resultsSnapshot())
+}
+
+func (h *health) Summary() Summary {
+	h.executeDueChecks()
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	results := h.resultsSnapshot()
+	summary := Summary{
+		Status: h.status(results),
+		Total:  len(results),
+	}
+	for _, result := range results {
+		if result.Timestamp.After(summary.LastUpdate) {
+			summary.LastUpdate = result.Timestamp
+		}
+		if result.IsHealthy() {
+			summary.Passing++
+			continue
+		}
+
+		summary.Failing++
+		if result.Error == ErrStaleResult {
+			summary.Stale++
+		}
+		firstFailure := result.TimeOfFirstFailure
+		if firstFailure == nil {
+			firstFailure = &result.Timestamp
+		}
+		if summary.OldestFailure == nil || firstFailure.Before(*summary.OldestFailure) {
+			t := *firstFailure
+			summary.OldestFailure = &t
+		}
+	}
+
+	return summary
+}
+
+// resultsSnapshot returns a copy of the current results, where overridden results are replaced by their override,
+// and results that are older than the max staleness of their check are marked as failing.
+// It must be called while holding the lock.
+func (h *health) resultsSnapshot() map[string]Result {
+	results := copyResultsMap(h.results)
+	now := h.clock.Now()
+	for name, result := range results {
+		task, ok := h.checkTasks[name]
+		if !ok {
+			continue
+		}
+		if override, overridden := task.overridden(now); overridden {
+			results[name] = override
+			continue
+		}
+		if task.isStale(result, now) {
+			result.Error = ErrStaleResult
+			results[name] = result
+		}
+	}
+
+	return results
+}
+
+// status computes the overall status of the given results according to the health policy if set,
+// or to the registered checks severity otherwise. Failures that are tolerated by the UnhealthyAfter option,
+// or whose errors are Degraded, only degrade the status. It must be called while holding the lock.
+func (h *health) status(results map[string]Result) Status {
+	now := h.clock.Now()
+	if h.policy != nil {
+		// the policy considers tolerated and degraded failures as passing
+		policyResults := copyResultsMap(results)
+		for name, result := range policyResults {
+			if task, ok := h.checkTasks[name]; ok && (IsDegraded(result.Error) || task.isTolerated(result, now)) {
+				result.Error = nil
+				policyResults[name] = result
+			}
+		}
+		if !h.policy.IsHealthy(policyResults) {
+			return StatusUnhealthy
+		}
+		for _, result := range results {
+			if !result.IsHealthy() {
+				return StatusDegraded
+			}
+		}
+		return StatusHealthy
+	}
+
+	status := StatusHealthy
+	for name, result := range results {
+		if result.IsHealthy() {
+			continue
+		}
+		task, ok := h.checkTasks[name]
+		if ok && (task.severity == SeverityWarning || IsDegraded(result.Error) || task.isTolerated(result, now)) {
+			status = StatusDegraded
+			continue
+		}
+		return StatusUnhealthy
+	}
+
+	return status
+}
+
+func (h *health) ListChecks() []CheckInfo {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	infos := make([]CheckInfo, 0, len(h.checkTasks))
+	for name, task := range h.checkTasks {
+		info := CheckInfo{
+			Name:            name,
+			Async:           task.isAsync(),
+			Timeout:         task.timeout,
+			Severity:        task.severity,
+			Classifications: task.classifications,
+		}
+		if !info.Async {
+			info.ExecutionPeriod = task.executionPeriod
+			info.InitialDelay = task.initialDelay
+			info.NextRun = task.nextRun
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+func (h *health) AwaitFirstExecution(ctx context.Context, names ...string) error {
+	return h.await(ctx, func() bool {
+		if len(names) == 0 {
+			for _, task := range h.checkTasks {
+				if !task.executed {
+					return false
+				}
+			}
+			return true
+		}
+
+		for _, name := range names {
+			task, ok := h.checkTasks[name]
+			if !ok || !task.executed {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (h *health) AwaitHealthy(ctx context.Context) error {
+	return h.await(ctx, func() bool {
+		return h.status(h.resultsSnapshot()) != StatusUnhealthy
+	})
+}
+
+// await blocks until the given condition is met, re-evaluating it whenever results are updated.
+// The condition is evaluated while holding the lock.
+func (h *health) await(ctx context.Context, condition func() bool) error {
+	for {
+		h.executeDueChecks()
+
+		h.lock.RLock()
+		done := condition()
+		updated := h.updated
+		var dueTimer Timer
+		if !done {
+			dueTimer = h.nextDueLocked()
+		}
+		h.lock.RUnlock()
+
+		if done {
+			return nil
+		}
+		if err := awaitUpdate(ctx, updated, dueTimer); err != nil {
+			return err
+		}
+	}
+}
+
+// awaitUpdate blocks until the updated channel is closed, the due timer (if any) fires, or the context is done.
+func awaitUpdate(ctx context.Context, updated <-chan struct{}, dueTimer Timer) error {
+	var due <-chan time.Time
+	if dueTimer != nil {
+		defer dueTimer.Stop()
+		due = dueTimer.C()
+	}
+
+	select {
+	case <-updated:
+	case <-due:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// executeDueChecks executes the checks that are due in manual execution mode, and waits for their completion.
+// Expired async checks results are updated as well. It does nothing when checks are executed in the background.
+func (h *health) executeDueChecks() {
+	if !h.manualExecution {
+		return
+	}
+
+	h.executionLock.Lock()
+	defer h.executionLock.Unlock()
+
+	now := h.clock.Now()
+	h.lock.Lock()
+	if h.ctx.Err() != nil {
+		// same as the task go routines, which stop once the context is done
+		for _, task := range h.checkTasks {
+			h.stopCheckTaskLocked(task)
+		}
+		h.lock.Unlock()
+		return
+	}
+
+	var due []*checkTask
+	expired := make(map[string]Result)
+	for name, task := range h.checkTasks {
+		if task.isAsync() {
+			if task.resultTTL > 0 && task.lastResult != nil {
+				expiry := task.lastResult.Timestamp.Add(task.resultTTL)
+				if !now.Before(expiry) {
+					expired[name] = h.updateResultLocked(name, nil, 0, ErrResultExpired, expiry)
+				}
+			}
+			continue
+		}
+		if !now.Before(task.nextRun) {
+			task.nextRun = now.Add(h.minExecutionInterval)
+			due = append(due, task)
+		}
+	}
+	h.lock.Unlock()
+
+	for name, result := range expired {
+		h.checksListener.OnCheckCompleted(name, result)
+	}
+
+	var wg sync.WaitGroup
+	for _, task := range due {
+		wg.Add(1)
+		go func(task *checkTask) {
+			defer wg.Done()
+			h.checkAndUpdateResult(task, now)
+		}(task)
+	}
+	wg.Wait()
+
+	if len(due) > 0 || len(expired) > 0 {
+		h.reportResults()
+	}
+}
+
+// nextDueLocked returns a timer that fires once the next check is due in manual execution mode,
+// or nil when checks are executed in the background. It must be called while holding the lock.
+func (h *health) nextDueLocked() Timer {
+	if !h.manualExecution {
+		return nil
+	}
+
+	var next time.Time
+	for _, task := range h.checkTasks {
+		if !task.isAsync() && (next.IsZero() || task.nextRun.Before(next)) {
+			next = task.nextRun
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+	return h.clock.NewTimer(next.Sub(h.clock.Now()))
+}
+
+// notifyUpdated wakes up all the callers awaiting an update. It must be called while holding the lock.
+func (h *health) notifyUpdated() {
+	close(h.updated)
+	h.updated = make(chan struct{})
+}
+
+// recordExecution updates the result of the given task with the outcome of an execution.
+// The result is discarded, and false is returned, if the task has been replaced or removed in the meantime.
+func (h *health) recordExecution(
+	task *checkTask, details interface{}, checkDuration time.Duration, err error, t time.Time) (Result, bool) {
 
 	h.lock.Lock()
 	defer h.lock.Unlock()
 
-	prevResult, ok := h.results[name]
+	return h.recordExecutionLocked(task, details, checkDuration, err, t)
+}
+
+// recordExecutionLocked is the same as recordExecution, but must be called while holding the lock.
+func (h *health) recordExecutionLocked(
+	task *checkTask, details interface{}, checkDuration time.Duration, err error, t time.Time) (Result, bool) {
+
+	if h.checkTasks[task.check.Name()] != task {
+		return Result{}, false
+	}
+
+	task.executed = true
+	return h.updateResultLocked(task.check.Name(), details, checkDuration, err, t), true
+}
+
+// updateTaskResult updates the result of the given task.
+// The result is discarded, and false is returned, if the task has been replaced or removed in the meantime.
+func (h *health) updateTaskResult(
+	task *checkTask, details interface{}, checkDuration time.Duration, err error, t time.Time) (Result, bool) {
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.checkTasks[task.check.Name()] != task {
+		return Result{}, false
+	}
+
+	return h.updateResultLocked(task.check.Name(), details, checkDuration, err, t), true
+}
+
+// updateResultLocked updates the result of the check with the given name. It must be called while holding the lock.
+func (h *health) updateResultLocked(
+	name string, details interface{}, checkDuration time.Duration, err error, t time.Time) (result Result) {
+
+	task := h.checkTasks[name]
+	prevResult := task.lastResult
 	result = Result{
 		Details:            details,
 		Error:              newMarshalableError(err),
@@ -224,7 +1126,7 @@ func (h *health) updateResult(
 	}
 
 	if !result.IsHealthy() {
-		if ok {
+		if prevResult != nil {
 			result.ContiguousFailures = prevResult.ContiguousFailures + 1
 			if prevResult.IsHealthy() {
 				result.TimeOfFirstFailure = &t
@@ -237,6 +1139,52 @@ func (h *health) updateResult(
 		}
 	}
 
+	rawResult := result
+	task.lastResult = &rawResult
+
+	reportedResult, ok := h.results[name]
+	result = task.debounce(rawResult, reportedResult, ok)
 	h.results[name] = result
+	h.updateStartupLocked(task)
+	h.notifyUpdated()
 	return result
 }
+
+// updateStartupLocked marks the system as started once all the startup checks passed. Once started, the checks classified
+// only as startup checks are completed as soon as they pass, and are no longer executed (see ClassificationStartup).
+// It must be called while holding the lock, with the task whose result was updated.
+func (h *health) updateStartupLocked(updated *checkTask) {
+	if !h.started {
+		startupChecks := 0
+		for name, task := range h.checkTasks {
+			if !task.isClassified(ClassificationStartup) {
+				continue
+			}
+			if !h.results[name].IsHealthy() {
+				return
+			}
+			startupChecks++
+		}
+		if startupChecks == 0 {
+			return
+		}
+
+		h.started = true
+		for _, task := range h.checkTasks {
+			task.startupCompleted = task.isStartupOnly()
+		}
+		return
+	}
+
+	if updated.isStartupOnly() && h.results[updated.check.Name()].IsHealthy() {
+		updated.startupCompleted = true
+	}
+}
+
+// isStartupCompleted returns true when the given task is a completed startup check, which is no longer executed.
+func (h *health) isStartupCompleted(task *checkTask) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return task.startupCompleted
+}
@@ -11,3 +11,23 @@ func (h HealthListeners) OnResultsUpdated(results map[string]Result) {
 		listener.OnResultsUpdated(results)
 	}
 }
+
+// HealthChangeListener is notified only when the overall health of the system changes,
+// unlike HealthListener which is notified after every check execution.
+// Notifications are delivered one at a time, in the order of the transitions.
+// Implementations of this interface **must not block!**
+type HealthChangeListener interface {
+	// OnHealthChanged is called when the overall health changes from `previous` to `current`.
+	// The results that led to the change are passed as an argument
+	OnHealthChanged(previous, current bool, results map[string]Result)
+}
+
+// HealthChangeListeners is a slice of health change listeners
+type HealthChangeListeners []HealthChangeListener
+
+// OnHealthChanged is called when the overall health changes from `previous` to `current`.
+func (h HealthChangeListeners) OnHealthChanged(previous, current bool, results map[string]Result) {
+	for _, listener := range h {
+		listener.OnHealthChanged(previous, current, results)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -141,16 +143,1135 @@ func TestRegisterDeregister(t *testing.T) {
 	assert.False(t, ok2, "check should have been removed")
 	assert.True(t, ok3, "check exists")
 
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.DeregisterAllAndWait(ctx))
+	results, _ = h.Results()
+	assert.Empty(t, results, "results after stop")
+}
+
+func TestSeverity(t *testing.T) {
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(gosundheit.WithCheckListeners(checkWaiter))
+	defer h.DeregisterAll()
+
+	assert.Equal(t, gosundheit.StatusHealthy, h.Status(), "status of empty setup")
+
+	registerCheck(h, passingCheckName, true, false)
+	registerCheck(h, failingCheckName, false, false, gosundheit.Severity(gosundheit.SeverityWarning))
+
+	assert.Equal(t, gosundheit.StatusUnhealthy, h.Status(), "status before first run")
+
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(failingCheckName, passingCheckName))
+
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "status with a failing warning check")
+	assert.True(t, h.IsHealthy(), "failing warning checks should not make the system unhealthy")
+	results, healthy := h.Results()
+	assert.True(t, healthy, "failing warning checks should not make the system unhealthy")
+	assert.False(t, results[failingCheckName].IsHealthy(), "failing check should fail")
+}
+
+func TestDegraded(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(passingCheckName))
+	assert.NoError(t, h.RegisterAsyncCheck(failingCheckName))
+	assert.NoError(t, h.ReportResult(passingCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, gosundheit.Degraded(errors.New(failedMsg))))
+
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "status with a degraded check")
+	results, healthy := h.Results()
+	assert.True(t, healthy, "degraded checks should not make the system unhealthy")
+	assert.False(t, results[failingCheckName].IsHealthy(), "degraded check should fail")
+	assert.EqualError(t, results[failingCheckName].Error, failedMsg)
+	assert.True(t, gosundheit.IsDegraded(results[failingCheckName].Error), "the result error is degraded")
+
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.Equal(t, gosundheit.StatusUnhealthy, h.Status(), "status with a failing check")
+
+	assert.Nil(t, gosundheit.Degraded(nil))
+	assert.True(t, gosundheit.IsDegraded(fmt.Errorf("wrapped: %w", gosundheit.Degraded(errors.New(failedMsg)))),
+		"wrapped degraded errors are degraded")
+	assert.False(t, gosundheit.IsDegraded(errors.New(failedMsg)))
+}
+
+func TestDegraded_policy(t *testing.T) {
+	h := gosundheit.New(gosundheit.WithHealthPolicy(gosundheit.AllPassingPolicy()))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(failingCheckName))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, gosundheit.Degraded(errors.New(failedMsg))))
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "the policy considers degraded failures as passing")
+	assert.True(t, h.IsHealthy())
+
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.Equal(t, gosundheit.StatusUnhealthy, h.Status())
+}
+
+func TestMaxConcurrentChecks(t *testing.T) {
+	const maxConcurrent = 2
+
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(gosundheit.WithCheckListeners(checkWaiter), gosundheit.WithMaxConcurrentChecks(maxConcurrent))
+	defer h.DeregisterAll()
+
+	var running, maxRunning int32
+	started := make(chan string)
+	release := make(chan struct{})
+	checkFunc := func(name string) func(ctx context.Context) (details interface{}, err error) {
+		return func(ctx context.Context) (details interface{}, err error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				observed := atomic.LoadInt32(&maxRunning)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+					break
+				}
+			}
+			started <- name
+			<-release
+			return successMsg, nil
+		}
+	}
+
+	var names []string
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("concurrent.check.%d", i)
+		names = append(names, name)
+		assert.NoError(t, h.RegisterCheck(
+			&checks.CustomCheck{CheckName: name, CheckFunc: checkFunc(name)},
+			gosundheit.ExecutionPeriod(time.Minute),
+		))
+	}
+
+	// every execution blocks until released, so the started executions hold their slots.
+	// Once the limit is reached, an execution is released for each one that starts
+	for i := range names {
+		awaitName(t, started)
+		if i >= maxConcurrent-1 {
+			release <- struct{}{}
+		}
+	}
+	for i := 0; i < maxConcurrent-1; i++ {
+		release <- struct{}{}
+	}
+
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(names...))
+	assert.True(t, h.IsHealthy(), "all checks should have passed")
+	assert.Equal(t, int32(maxConcurrent), atomic.LoadInt32(&maxRunning), "max concurrently running checks")
+}
+
+func TestPanickingCheck(t *testing.T) {
+	const panickingCheckName = "panicking.check"
+
+	listenerMock := &checkListenerMock{}
+	listenerMock.On("OnCheckRegistered", panickingCheckName, mock.AnythingOfType("Result")).Return()
+	listenerMock.On("OnCheckStarted", panickingCheckName).Return()
+	listenerMock.On("OnCheckCompleted", panickingCheckName, mock.AnythingOfType("Result")).Return()
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(gosundheit.WithCheckListeners(listenerMock, checkWaiter))
+	defer h.DeregisterAll()
+
+	err := h.RegisterCheck(
+		&checks.CustomCheck{
+			CheckName: panickingCheckName,
+			CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+				panic("boom")
+			},
+		},
+		gosundheit.ExecutionPeriod(time.Minute),
+	)
+	assert.NoError(t, err)
+
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(panickingCheckName))
+	listenerMock.AssertExpectations(t)
+
+	results, healthy := h.Results()
+	assert.False(t, healthy, "a panicking check should fail")
+	assert.EqualError(t, results[panickingCheckName].Error, "check panicked: boom")
+	assert.Contains(t, results[panickingCheckName].Details, "goroutine", "details should contain the stack trace")
+	assert.Equal(t, int64(2), results[panickingCheckName].ContiguousFailures, "initial failure and the panic")
+}
+
+func TestWithContext(t *testing.T) {
+	const blockingCheckName = "blocking.check"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listenerMock := &checkListenerMock{}
+	listenerMock.On("OnCheckRegistered", blockingCheckName, mock.AnythingOfType("Result")).Return()
+	listenerMock.On("OnCheckStarted", blockingCheckName).Return()
+	listenerMock.On("OnCheckCompleted", blockingCheckName, mock.AnythingOfType("Result")).Return()
+	h := gosundheit.New(gosundheit.WithContext(ctx), gosundheit.WithCheckListeners(listenerMock))
+
+	started := make(chan struct{})
+	err := h.RegisterCheck(
+		&checks.CustomCheck{
+			CheckName: blockingCheckName,
+			CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+		gosundheit.ExecutionPeriod(time.Minute),
+	)
+	assert.NoError(t, err)
+
+	<-started
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		results, _ := h.Results()
+		return len(results) == 0
+	}, time.Second, time.Millisecond, "checks should stop once the context is cancelled")
+
+	completedChecks := listenerMock.getCompletedChecks()
+	assert.Equal(t, 1, len(completedChecks), "num completed checks")
+	assert.EqualError(t, completedChecks[0].res.Error, context.Canceled.Error(), "cancellation should propagate to the check")
+
+	err = h.RegisterCheck(&checks.CustomCheck{CheckName: passingCheckName}, gosundheit.ExecutionPeriod(time.Minute))
+	assert.EqualError(t, err, "health context is done: context canceled")
+}
+
+func TestHardTimeout(t *testing.T) {
+	const hangingCheckName = "hanging.check"
+
+	listenerMock := &checkTimeoutListenerMock{}
+	listenerMock.On("OnCheckRegistered", mock.AnythingOfType("string"), mock.AnythingOfType("Result")).Return()
+	started := make(chan string, 2)
+	listenerMock.On("OnCheckStarted", mock.AnythingOfType("string")).Return().
+		Run(func(args mock.Arguments) { started <- args.String(0) })
+	listenerMock.On("OnCheckTimeout", hangingCheckName, mock.AnythingOfType("Result")).Return().Once()
+	completed := make(chan string, 2)
+	listenerMock.On("OnCheckCompleted", mock.AnythingOfType("string"), mock.AnythingOfType("Result")).Return().
+		Run(func(args mock.Arguments) { completed <- args.String(0) })
+	clock := helper.NewFakeClock(time.Now())
+	h := gosundheit.New(gosundheit.WithCheckListeners(listenerMock), gosundheit.WithClock(clock), gosundheit.WithMaxConcurrentChecks(1))
+	defer h.DeregisterAll()
+
+	release := make(chan struct{})
+	abandonedErr := make(chan error, 1)
+	err := h.RegisterCheck(
+		&checks.CustomCheck{
+			CheckName: hangingCheckName,
+			CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+				// ignores the context until released
+				<-release
+				abandonedErr <- ctx.Err()
+				return successMsg, nil
+			},
+		},
+		gosundheit.ExecutionPeriod(time.Minute),
+		gosundheit.HardTimeout(20*time.Millisecond),
+	)
+	assert.NoError(t, err)
+
+	clock.AwaitWaiters(1) // the initial execution
+	clock.Advance(0)
+	assert.Equal(t, hangingCheckName, awaitName(t, started))
+	clock.AwaitWaiters(1) // the hard timeout
+	clock.Advance(19 * time.Millisecond)
+	assert.Empty(t, completed, "hard timeout is not exceeded yet")
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, hangingCheckName, awaitName(t, completed))
+
+	results, healthy := h.Results()
+	assert.False(t, healthy, "timed out check is failing")
+	assert.Equal(t, gosundheit.ErrExecutionTimeout, results[hangingCheckName].Error)
+	assert.Equal(t, 20*time.Millisecond, results[hangingCheckName].Duration)
+	listenerMock.AssertExpectations(t)
+
+	registerCheck(h, passingCheckName, true, false)
+	clock.AwaitWaiters(2) // the hanging check ticker and the initial execution
+	clock.Advance(20 * time.Millisecond)
+	assert.Empty(t, started, "abandoned execution keeps its execution slot")
+
+	close(release)
+	assert.Equal(t, context.Canceled, <-abandonedErr, "abandoned execution context is cancelled")
+	assert.Equal(t, passingCheckName, awaitName(t, started), "execution slot is released once the abandoned execution returns")
+	assert.Equal(t, passingCheckName, awaitName(t, completed))
+	results, _ = h.Results()
+	assert.Equal(t, gosundheit.ErrExecutionTimeout, results[hangingCheckName].Error, "abandoned execution outcome is discarded")
+}
+
+// awaitName returns the next name sent on the given channel, failing the test if none is sent in time
+func awaitName(t *testing.T, names <-chan string) string {
+	t.Helper()
+	select {
+	case name := <-names:
+		return name
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the listener notification")
+		return ""
+	}
+}
+
+func TestAsyncCheck(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	assert.EqualError(t, h.RegisterAsyncCheck(""), "check name must not be empty")
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName, gosundheit.ResultTTL(50*time.Millisecond)))
+	registerCheck(h, passingCheckName, true, true)
+
+	results, healthy := h.Results()
+	assert.False(t, healthy, "async check should initially fail")
+	assert.True(t, errors.Is(results[asyncCheckName].Error, gosundheit.ErrNotRunYet))
+
+	assert.EqualError(t, h.ReportResult("no.such.check", nil, nil), "no async check named 'no.such.check' is registered")
+	assert.EqualError(t, h.ReportResult(passingCheckName, nil, nil), "no async check named 'passing.check' is registered")
+
+	assert.NoError(t, h.ReportResult(asyncCheckName, "consumer is alive", nil))
+	results, _ = h.Results()
+	assert.True(t, results[asyncCheckName].IsHealthy(), "reported result should pass")
+	assert.Equal(t, "consumer is alive", results[asyncCheckName].Details)
+
+	assert.NoError(t, h.ReportResult(asyncCheckName, "consumer is stuck", errors.New(failedMsg)))
+	results, _ = h.Results()
+	assert.EqualError(t, results[asyncCheckName].Error, failedMsg)
+	assert.Equal(t, int64(1), results[asyncCheckName].ContiguousFailures)
+
+	assert.NoError(t, h.ReportResult(asyncCheckName, "consumer is alive", nil))
+	assert.True(t, h.IsHealthy(), "reported result should pass")
+
+	assert.Eventually(t, func() bool {
+		results, _ := h.Results()
+		return errors.Is(results[asyncCheckName].Error, gosundheit.ErrResultExpired)
+	}, time.Second, 5*time.Millisecond, "async check should fail once its result expires")
+}
+
+func TestMaxStaleness(t *testing.T) {
+	const stalledCheckName = "stalled.check"
+
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(gosundheit.WithCheckListeners(checkWaiter), gosundheit.MaxStaleness(time.Hour))
+	defer h.DeregisterAll()
+
+	stall := make(chan struct{})
+	defer close(stall)
+	executions := 0
+	err := h.RegisterCheck(
+		&checks.CustomCheck{
+			CheckName: stalledCheckName,
+			CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+				executions++
+				if executions > 1 {
+					<-stall
+				}
+				return successMsg, nil
+			},
+		},
+		gosundheit.ExecutionPeriod(10*time.Millisecond),
+		gosundheit.MaxStaleness(50*time.Millisecond),
+	)
+	assert.NoError(t, err)
+	registerCheck(h, passingCheckName, true, false)
+
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(stalledCheckName, passingCheckName))
+	assert.True(t, h.IsHealthy(), "fresh results should pass")
+
+	assert.Eventually(t, func() bool {
+		return !h.IsHealthy()
+	}, time.Second, 5*time.Millisecond, "stale results should fail")
+
+	results, healthy := h.Results()
+	assert.False(t, healthy, "stale results should fail")
+	assert.True(t, errors.Is(results[stalledCheckName].Error, gosundheit.ErrStaleResult))
+	assert.Equal(t, successMsg, results[stalledCheckName].Details, "stale result should keep its details")
+	assert.True(t, results[passingCheckName].IsHealthy(), "check should inherit the health max staleness")
+}
+
+func TestAwaitFirstExecution(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	registerCheck(h, passingCheckName, true, false)
+	registerCheck(h, failingCheckName, false, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.AwaitFirstExecution(ctx, passingCheckName))
+	results, _ := h.Results()
+	assert.NotContains(t, results[passingCheckName].String(), "didn't run yet", "details after first execution")
+
+	assert.NoError(t, h.AwaitFirstExecution(ctx))
+	results, _ = h.Results()
+	assert.NotContains(t, results[failingCheckName].String(), "didn't run yet", "details after first execution")
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer timeoutCancel()
+	assert.Equal(t, context.DeadlineExceeded, h.AwaitFirstExecution(timeoutCtx, "no.such.check"))
+}
+
+func TestAwaitHealthy(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName))
+	registerCheck(h, passingCheckName, true, false)
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer timeoutCancel()
+	assert.Equal(t, context.DeadlineExceeded, h.AwaitHealthy(timeoutCtx), "async check never reported")
+
+	go func() {
+		_ = h.ReportResult(asyncCheckName, successMsg, nil)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.AwaitHealthy(ctx))
+	assert.True(t, h.IsHealthy())
+}
+
+func TestDependsOn(t *testing.T) {
+	const dependentCheckName = "dependent.check"
+
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(gosundheit.WithCheckListeners(checkWaiter))
+	defer h.DeregisterAll()
+
+	executed := false
+	dependentCheck := &checks.CustomCheck{
+		CheckName: dependentCheckName,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			executed = true
+			return successMsg, nil
+		},
+	}
+
+	assert.EqualError(t,
+		h.RegisterCheck(dependentCheck, gosundheit.ExecutionPeriod(time.Minute), gosundheit.DependsOn(dependentCheckName)),
+		"check must not depend on itself")
+
+	registerCheck(h, failingCheckName, false, false)
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(failingCheckName))
+
+	assert.NoError(t, h.RegisterCheck(dependentCheck,
+		gosundheit.ExecutionPeriod(time.Minute),
+		gosundheit.DependsOn(failingCheckName, "no.such.check")))
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(dependentCheckName))
+
+	results, _ := h.Results()
+	assert.False(t, executed, "dependent check should be skipped")
+	assert.True(t, errors.Is(results[dependentCheckName].Error, gosundheit.ErrDependencyFailing))
+	assert.Equal(t, []string{failingCheckName, "no.such.check"}, results[dependentCheckName].Details)
+}
+
+func TestDependsOn_cycle(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	checkA := &checks.CustomCheck{CheckName: "a"}
+	checkB := &checks.CustomCheck{CheckName: "b"}
+	assert.NoError(t, h.RegisterCheck(checkA, gosundheit.ExecutionPeriod(time.Minute), gosundheit.DependsOn("b")))
+	assert.EqualError(t,
+		h.RegisterCheck(checkB, gosundheit.ExecutionPeriod(time.Minute), gosundheit.DependsOn("a")),
+		"check dependencies must not form a cycle: b -> a -> b")
+	results, _ := h.Results()
+	assert.Len(t, results, 1, "the check forming a cycle is not registered")
+
 	h.DeregisterAll()
+	h = gosundheit.New()
+	assert.EqualError(t,
+		h.RegisterChecks(
+			gosundheit.CheckRegistration{Check: checkA, Options: []gosundheit.CheckOption{
+				gosundheit.ExecutionPeriod(time.Minute), gosundheit.DependsOn("b")}},
+			gosundheit.CheckRegistration{Check: checkB, Options: []gosundheit.CheckOption{
+				gosundheit.ExecutionPeriod(time.Minute), gosundheit.DependsOn("a")}},
+		),
+		"check dependencies must not form a cycle: a -> b -> a")
+	results, _ = h.Results()
+	assert.Empty(t, results, "no check is registered")
+
+	assert.NoError(t, h.RegisterAsyncCheck("a", gosundheit.DependsOn("b")))
+	assert.NoError(t, h.RegisterCheck(checkB, gosundheit.ExecutionPeriod(time.Minute), gosundheit.DependsOn("a")),
+		"async checks are never skipped, so they do not form a cycle")
+}
+
+func TestReplaceCheck(t *testing.T) {
+	const replacedCheckName = "replaced.check"
+
+	clock := helper.NewFakeClock(time.Now())
+	h := gosundheit.New(gosundheit.WithClock(clock), gosundheit.WithManualExecution(0), gosundheit.ExecutionPeriod(10*time.Millisecond))
+	defer h.DeregisterAll()
 
-	// await stop
-	// TODO we need to add CheckListener.OnCheckDeregistered, then we can remove this sleep too
-	time.Sleep(20 * time.Millisecond)
+	var oldExecutions, newExecutions int32
+	assert.NoError(t, h.RegisterCheck(&checks.CustomCheck{
+		CheckName: replacedCheckName,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			atomic.AddInt32(&oldExecutions, 1)
+			return failedMsg, errors.New(failedMsg)
+		},
+	}))
+	h.Results()
+	clock.Advance(10 * time.Millisecond)
+	h.Results()
+
+	newCheck := &checks.CustomCheck{
+		CheckName: replacedCheckName,
+		CheckFunc: func(ctx context.Context) (details interface{}, err error) {
+			atomic.AddInt32(&newExecutions, 1)
+			return successMsg, nil
+		},
+	}
+	assert.NoError(t, h.RegisterCheck(newCheck, gosundheit.InitialDelay(50*time.Millisecond), gosundheit.PreserveHistory(true)))
+
+	results, _ := h.Results()
+	assert.Equal(t, 1, len(results), "num results after replacement")
+	assert.EqualError(t, results[replacedCheckName].Error, failedMsg, "result should be preserved until first execution")
+	assert.Equal(t, int64(3), results[replacedCheckName].ContiguousFailures, "history should be preserved, including the initial result")
+
+	clock.Advance(50 * time.Millisecond)
+	assert.True(t, h.IsHealthy(), "replacing check should pass")
+
+	for i := 0; i < 3; i++ {
+		clock.Advance(10 * time.Millisecond)
+		h.Results()
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&oldExecutions), "replaced check should stop")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&newExecutions), "replacing check should keep executing")
+
+	assert.NoError(t, h.RegisterCheck(newCheck, gosundheit.InitialDelay(time.Minute)))
 	results, _ = h.Results()
-	assert.Empty(t, results, "results after stop")
+	assert.True(t, errors.Is(results[replacedCheckName].Error, gosundheit.ErrNotRunYet), "replacing check should start fresh")
+	assert.Equal(t, int64(1), results[replacedCheckName].ContiguousFailures, "replacing check should start fresh")
+
+	h.Deregister(replacedCheckName)
+	results, _ = h.Results()
+	assert.Empty(t, results, "deregistration should remove the replacing check")
 }
 
-func registerCheck(h gosundheit.Health, name string, passing bool, initiallyPassing bool) {
+func TestDebounce(t *testing.T) {
+	const flappingCheckName = "flapping.check"
+	const window = 50 * time.Millisecond
+
+	clock := helper.NewFakeClock(time.Now())
+	h := gosundheit.New(gosundheit.WithClock(clock))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(flappingCheckName, gosundheit.Debounce(window)))
+
+	assert.NoError(t, h.ReportResult(flappingCheckName, successMsg, nil))
+	assert.True(t, h.IsHealthy(), "transition from initial result should not be debounced")
+
+	assert.NoError(t, h.ReportResult(flappingCheckName, failedMsg, errors.New(failedMsg)))
+	results, healthy := h.Results()
+	assert.True(t, healthy, "failure within the debounce window should be suppressed")
+	rawResult, ok := results[flappingCheckName].Details.(gosundheit.Result)
+	assert.True(t, ok, "details of suppressed result should be the raw result")
+	assert.EqualError(t, rawResult.Error, failedMsg)
+	assert.Equal(t, failedMsg, rawResult.Details)
+
+	clock.Advance(window)
+	assert.NoError(t, h.ReportResult(flappingCheckName, failedMsg, errors.New(failedMsg)))
+	results, healthy = h.Results()
+	assert.False(t, healthy, "failure persisting beyond the debounce window should be reported")
+	assert.Equal(t, failedMsg, results[flappingCheckName].Details)
+	assert.Equal(t, int64(2), results[flappingCheckName].ContiguousFailures)
+
+	assert.NoError(t, h.ReportResult(flappingCheckName, successMsg, nil))
+	assert.False(t, h.IsHealthy(), "recovery within the debounce window should be suppressed")
+	assert.NoError(t, h.ReportResult(flappingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.NoError(t, h.ReportResult(flappingCheckName, successMsg, nil))
+
+	clock.Advance(window)
+	assert.NoError(t, h.ReportResult(flappingCheckName, successMsg, nil))
+	assert.True(t, h.IsHealthy(), "recovery persisting beyond the debounce window should be reported")
+}
+
+func TestWithHealthPolicy(t *testing.T) {
+	h := gosundheit.New(gosundheit.WithHealthPolicy(gosundheit.MinPassingPolicy(1, "replica.1", "replica.2")))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck("replica.1"))
+	assert.NoError(t, h.RegisterAsyncCheck("replica.2"))
+	assert.Equal(t, gosundheit.StatusUnhealthy, h.Status(), "no replica is passing")
+
+	assert.NoError(t, h.ReportResult("replica.1", successMsg, nil))
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "quorum is met, but a replica is failing")
+	results, healthy := h.Results()
+	assert.True(t, healthy, "quorum is met")
+	assert.False(t, results["replica.2"].IsHealthy())
+
+	assert.NoError(t, h.ReportResult("replica.2", successMsg, nil))
+	assert.Equal(t, gosundheit.StatusHealthy, h.Status(), "all replicas are passing")
+}
+
+func TestHealthChangeListeners(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	listenerMock := &healthChangeListenerMock{}
+	h := gosundheit.New(gosundheit.WithHealthChangeListeners(listenerMock))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName))
+	assert.NoError(t, h.ReportResult(asyncCheckName, successMsg, nil))
+	assert.Empty(t, listenerMock.changes, "health didn't change")
+
+	assert.NoError(t, h.ReportResult(asyncCheckName, failedMsg, errors.New(failedMsg)))
+	assert.NoError(t, h.ReportResult(asyncCheckName, failedMsg, errors.New(failedMsg)))
+	assert.NoError(t, h.ReportResult(asyncCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(asyncCheckName, successMsg, nil))
+
+	assert.Equal(t, []healthChange{{previous: true, current: false}, {previous: false, current: true}}, listenerMock.changes)
+}
+
+func TestHealthChangeListener_concurrentReports(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	listenerMock := &healthChangeListenerMock{}
+	h := gosundheit.New(gosundheit.WithHealthChangeListeners(listenerMock))
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName, gosundheit.InitiallyPassing(true)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if (i+j)%2 == 0 {
+					_ = h.ReportResult(asyncCheckName, successMsg, nil)
+				} else {
+					_ = h.ReportResult(asyncCheckName, failedMsg, errors.New(failedMsg))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// the listener is not synchronized, as the notifications must be delivered one at a time
+	previous := true
+	for i, change := range listenerMock.changes {
+		assert.Equal(t, previous, change.previous, "change %d is delivered in order", i)
+		assert.NotEqual(t, change.previous, change.current, "change %d is a transition", i)
+		previous = change.current
+	}
+	assert.Equal(t, h.IsHealthy(), previous, "the last change is the current health")
+}
+
+type healthChange struct {
+	previous bool
+	current  bool
+}
+
+type healthChangeListenerMock struct {
+	changes []healthChange
+}
+
+func (l *healthChangeListenerMock) OnHealthChanged(previous, current bool, _ map[string]gosundheit.Result) {
+	l.changes = append(l.changes, healthChange{previous: previous, current: current})
+}
+
+func TestListChecks(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	assert.Empty(t, h.ListChecks(), "no checks are registered")
+
+	registrationTime := time.Now()
+	assert.NoError(t, h.RegisterCheck(
+		&checks.CustomCheck{CheckName: passingCheckName},
+		gosundheit.InitialDelay(time.Minute),
+		gosundheit.ExecutionPeriod(time.Hour),
+		gosundheit.ExecutionTimeout(time.Second),
+		gosundheit.Severity(gosundheit.SeverityWarning),
+	))
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName))
+
+	infos := h.ListChecks()
+	assert.Equal(t, 2, len(infos), "num checks")
+	assert.Equal(t, gosundheit.CheckInfo{Name: asyncCheckName, Async: true}, infos[0])
+
+	passingInfo := infos[1]
+	assert.Equal(t, passingCheckName, passingInfo.Name)
+	assert.False(t, passingInfo.Async)
+	assert.Equal(t, time.Hour, passingInfo.ExecutionPeriod)
+	assert.Equal(t, time.Minute, passingInfo.InitialDelay)
+	assert.Equal(t, time.Second, passingInfo.Timeout)
+	assert.Equal(t, gosundheit.SeverityWarning, passingInfo.Severity)
+	assert.WithinDuration(t, registrationTime.Add(time.Minute), passingInfo.NextRun, time.Second, "next run after initial delay")
+}
+
+func TestClassifications(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(passingCheckName,
+		gosundheit.Classifications(gosundheit.ClassificationLiveness, gosundheit.ClassificationReadiness)))
+	assert.NoError(t, h.RegisterAsyncCheck(failingCheckName, gosundheit.Classifications(gosundheit.ClassificationReadiness)))
+	assert.NoError(t, h.RegisterAsyncCheck(initiallyPassingCheckName, gosundheit.InitiallyPassing(true)))
+	assert.NoError(t, h.ReportResult(passingCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+
+	results, healthy := h.ResultsFor(gosundheit.ClassificationLiveness)
+	assert.True(t, healthy, "liveness checks are passing")
+	assert.Equal(t, []string{passingCheckName}, sortedKeys(results))
+	assert.True(t, h.IsHealthyFor(gosundheit.ClassificationLiveness))
+
+	results, healthy = h.ResultsFor(gosundheit.ClassificationReadiness)
+	assert.False(t, healthy, "a readiness check is failing")
+	assert.Equal(t, []string{failingCheckName, passingCheckName}, sortedKeys(results))
+	assert.False(t, h.IsHealthyFor(gosundheit.ClassificationReadiness))
+
+	results, healthy = h.ResultsFor(gosundheit.ClassificationStartup)
+	assert.True(t, healthy, "no startup checks")
+	assert.Empty(t, results)
+
+	assert.False(t, h.IsHealthy(), "all checks count for the overall health")
+	assert.Equal(t, []gosundheit.Classification{gosundheit.ClassificationReadiness}, h.ListChecks()[0].Classifications)
+}
+
+func TestClassificationStartup(t *testing.T) {
+	const (
+		migrationsCheckName = "migrations.check"
+		warmupCheckName     = "warmup.check"
+	)
+
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := helper.NewFakeClock(start)
+	h := gosundheit.New(gosundheit.WithClock(clock), gosundheit.WithManualExecution(0))
+	defer h.DeregisterAll()
+
+	registerCheck(h, migrationsCheckName, true, false, gosundheit.InitialDelay(0), gosundheit.MaxStaleness(time.Minute),
+		gosundheit.Classifications(gosundheit.ClassificationStartup))
+	assert.NoError(t, h.RegisterAsyncCheck(warmupCheckName,
+		gosundheit.Classifications(gosundheit.ClassificationStartup, gosundheit.ClassificationReadiness)))
+
+	results, started := h.ResultsFor(gosundheit.ClassificationStartup)
+	assert.False(t, started, "a startup check didn't run yet")
+	assert.Equal(t, "success; i=1", results[migrationsCheckName].Details)
+
+	clock.Advance(time.Minute)
+	results, started = h.ResultsFor(gosundheit.ClassificationStartup)
+	assert.False(t, started)
+	assert.Equal(t, "success; i=2", results[migrationsCheckName].Details, "startup checks are executed until started")
+
+	assert.NoError(t, h.ReportResult(warmupCheckName, successMsg, nil))
+	assert.True(t, h.IsHealthyFor(gosundheit.ClassificationStartup), "all the startup checks passed")
+
+	clock.Advance(time.Hour)
+	assert.NoError(t, h.ReportResult(warmupCheckName, failedMsg, errors.New(failedMsg)))
+	results, started = h.ResultsFor(gosundheit.ClassificationStartup)
+	assert.True(t, started, "a started system does not go back to starting")
+	assert.Equal(t, "success; i=2", results[migrationsCheckName].Details, "startup only checks are not executed once started")
+	assert.NoError(t, results[migrationsCheckName].Error, "the results of completed startup checks are not stale")
+	assert.EqualError(t, results[warmupCheckName].Error, failedMsg, "checks of other classifications keep being evaluated")
+	assert.False(t, h.IsHealthyFor(gosundheit.ClassificationReadiness))
+}
+
+func TestFilteredResults(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(passingCheckName))
+	assert.NoError(t, h.RegisterAsyncCheck(failingCheckName))
+	assert.NoError(t, h.RegisterAsyncCheck(initiallyPassingCheckName, gosundheit.InitiallyPassing(true)))
+	assert.NoError(t, h.ReportResult(passingCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+
+	results, healthy := h.FilteredResults(func(name string) bool { return name != failingCheckName })
+	assert.True(t, healthy, "the failing check is filtered out")
+	assert.Equal(t, []string{initiallyPassingCheckName, passingCheckName}, sortedKeys(results))
+
+	results, healthy = h.FilteredResults(func(name string) bool { return name == failingCheckName })
+	assert.False(t, healthy, "the failing check is included")
+	assert.Equal(t, []string{failingCheckName}, sortedKeys(results))
+
+	results, healthy = h.FilteredResults(func(string) bool { return false })
+	assert.True(t, healthy, "no checks")
+	assert.Empty(t, results)
+}
+
+func sortedKeys(results map[string]gosundheit.Result) []string {
+	keys := make([]string, 0, len(results))
+	for name := range results {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	h := gosundheit.New()
+	for _, name := range []string{passingCheckName, failingCheckName, initiallyPassingCheckName} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult(passingCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	snapshotResults, _ := h.Results()
+	data, err := h.Snapshot()
+	assert.NoError(t, err)
+	h.DeregisterAll()
+
+	restored := gosundheit.New(gosundheit.WithRestoredState(data))
+	defer restored.DeregisterAll()
+	for _, name := range []string{passingCheckName, failingCheckName, initiallyPassingCheckName} {
+		assert.NoError(t, restored.RegisterAsyncCheck(name))
+	}
+
+	results, healthy := restored.Results()
+	assert.False(t, healthy)
+	assert.NoError(t, results[passingCheckName].Error, "restored passing result")
+	assert.Equal(t, snapshotResults[passingCheckName].Timestamp.UnixNano(), results[passingCheckName].Timestamp.UnixNano())
+	assert.EqualError(t, results[failingCheckName].Error, failedMsg, "restored failing result")
+	assert.Equal(t, int64(3), results[failingCheckName].ContiguousFailures, "restored contiguous failures")
+	assert.Equal(t, gosundheit.ErrNotRunYet, results[initiallyPassingCheckName].Error, "not executed checks are not restored")
+
+	assert.NoError(t, restored.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	results, _ = restored.Results()
+	assert.Equal(t, int64(4), results[failingCheckName].ContiguousFailures, "failures counted on top of the restored ones")
+	assert.Equal(t, snapshotResults[failingCheckName].TimeOfFirstFailure.UnixNano(), results[failingCheckName].TimeOfFirstFailure.UnixNano())
+}
+
+func TestOverride(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := helper.NewFakeClock(start)
+	h := gosundheit.New(gosundheit.WithClock(clock))
+	defer h.DeregisterAll()
+
+	assert.EqualError(t, h.Override(failingCheckName, gosundheit.Result{}, start.Add(time.Hour)),
+		"no check named 'failing.check' is registered")
+
+	assert.NoError(t, h.RegisterAsyncCheck(failingCheckName))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	until := start.Add(time.Hour)
+	assert.NoError(t, h.Override(failingCheckName, gosundheit.Result{Details: "maintenance"}, until))
+
+	results, healthy := h.Results()
+	assert.True(t, healthy, "failing check is overridden")
+	assert.Equal(t, "maintenance", results[failingCheckName].Details)
+	assert.Equal(t, start, results[failingCheckName].Timestamp)
+	assert.Equal(t, &until, results[failingCheckName].OverriddenUntil)
+
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.True(t, h.IsHealthy(), "override applies to new results")
+
+	clock.Advance(time.Hour)
+	results, healthy = h.Results()
+	assert.False(t, healthy, "override expired")
+	assert.Nil(t, results[failingCheckName].OverriddenUntil)
+	assert.Equal(t, int64(3), results[failingCheckName].ContiguousFailures, "results kept updating while overridden")
+}
+
+func TestUnhealthyAfter(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := helper.NewFakeClock(start)
+	h := gosundheit.New(gosundheit.WithClock(clock))
+	defer h.DeregisterAll()
+
+	assert.NoError(t, h.RegisterAsyncCheck(failingCheckName, gosundheit.InitiallyPassing(true), gosundheit.UnhealthyAfter(time.Minute)))
+	assert.Equal(t, gosundheit.StatusHealthy, h.Status())
+
+	clock.Advance(time.Second)
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "failure is tolerated")
+	assert.True(t, h.IsHealthy())
+
+	clock.Advance(30 * time.Second)
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "failing for less than a minute")
+
+	clock.Advance(31 * time.Second)
+	assert.Equal(t, gosundheit.StatusUnhealthy, h.Status(), "failing for more than a minute")
+
+	assert.NoError(t, h.ReportResult(failingCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+	assert.Equal(t, gosundheit.StatusDegraded, h.Status(), "failure period restarts after a success")
+}
+
+func TestOrderedResults(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{passingCheckName, failingCheckName, initiallyPassingCheckName} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult(passingCheckName, successMsg, nil))
+	assert.NoError(t, h.ReportResult(initiallyPassingCheckName, successMsg, nil))
+
+	results, healthy := h.OrderedResults()
+	assert.False(t, healthy, "healthy")
+	assert.Equal(t, 3, len(results), "num results")
+	assert.Equal(t, failingCheckName, results[0].Name)
+	assert.Equal(t, gosundheit.ErrNotRunYet, results[0].Error)
+	assert.Equal(t, initiallyPassingCheckName, results[1].Name)
+	assert.Equal(t, passingCheckName, results[2].Name)
+	assert.Equal(t, successMsg, results[2].Details)
+}
+
+func TestSummary(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := helper.NewFakeClock(start)
+	h := gosundheit.New(gosundheit.WithClock(clock))
+	defer h.DeregisterAll()
+
+	assert.Equal(t, gosundheit.Summary{Status: gosundheit.StatusHealthy}, h.Summary(), "no checks are registered")
+
+	registerCheck(h, passingCheckName, true, true, gosundheit.InitialDelay(time.Hour))
+	registerCheck(h, failingCheckName, false, false, gosundheit.InitialDelay(time.Hour))
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName, gosundheit.MaxStaleness(time.Minute)))
+
+	clock.Advance(time.Second)
+	assert.NoError(t, h.ReportResult(asyncCheckName, nil, nil))
+
+	summary := h.Summary()
+	assert.Equal(t, gosundheit.StatusUnhealthy, summary.Status)
+	assert.Equal(t, 3, summary.Total, "total")
+	assert.Equal(t, 2, summary.Passing, "passing")
+	assert.Equal(t, 1, summary.Failing, "failing")
+	assert.Equal(t, 0, summary.Stale, "stale")
+	assert.Equal(t, start, *summary.OldestFailure, "oldest failure")
+	assert.Equal(t, start.Add(time.Second), summary.LastUpdate, "last update")
+
+	clock.Advance(2 * time.Minute)
+
+	summary = h.Summary()
+	assert.Equal(t, 1, summary.Passing, "passing")
+	assert.Equal(t, 2, summary.Failing, "failing")
+	assert.Equal(t, 1, summary.Stale, "stale")
+	assert.Equal(t, start, *summary.OldestFailure, "oldest failure")
+	assert.Equal(t, start.Add(time.Second), summary.LastUpdate, "last update")
+}
+
+func TestWithClock(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := helper.NewFakeClock(start)
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(gosundheit.WithClock(clock), gosundheit.WithCheckListeners(checkWaiter))
+	defer h.DeregisterAll()
+
+	registerCheck(h, passingCheckName, true, false, gosundheit.InitialDelay(time.Minute), gosundheit.ExecutionPeriod(time.Minute))
+
+	results, _ := h.Results()
+	assert.Equal(t, start, results[passingCheckName].Timestamp, "initial result time")
+	assert.Equal(t, start.Add(time.Minute), h.ListChecks()[0].NextRun, "first execution after initial delay")
+
+	clock.AwaitWaiters(1)
+	clock.Advance(time.Minute)
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(passingCheckName))
+
+	clock.AwaitWaiters(1)
+	results, _ = h.Results()
+	assert.Equal(t, start.Add(time.Minute), results[passingCheckName].Timestamp, "first execution time")
+	assert.Equal(t, "success; i=1", results[passingCheckName].Details)
+	assert.Equal(t, start.Add(2*time.Minute), h.ListChecks()[0].NextRun, "next execution after execution period")
+
+	clock.Advance(time.Minute)
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(passingCheckName))
+
+	results, _ = h.Results()
+	assert.Equal(t, start.Add(2*time.Minute), results[passingCheckName].Timestamp, "second execution time")
+	assert.Equal(t, "success; i=2", results[passingCheckName].Details)
+}
+
+func TestWithManualExecution(t *testing.T) {
+	const asyncCheckName = "async.check"
+
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := helper.NewFakeClock(start)
+	h := gosundheit.New(gosundheit.WithClock(clock), gosundheit.WithManualExecution(time.Minute))
+
+	registerCheck(h, passingCheckName, true, false, gosundheit.InitialDelay(0))
+	registerCheck(h, failingCheckName, false, false, gosundheit.InitialDelay(time.Hour))
+	assert.NoError(t, h.RegisterAsyncCheck(asyncCheckName, gosundheit.InitiallyPassing(true), gosundheit.ResultTTL(time.Hour)))
+
+	results, healthy := h.Results()
+	assert.False(t, healthy, "failing check didn't run yet")
+	assert.Equal(t, "success; i=1", results[passingCheckName].Details, "executed on demand")
+	assert.Equal(t, gosundheit.ErrNotRunYet, results[failingCheckName].Error, "not executed before initial delay")
+	assert.NoError(t, results[asyncCheckName].Error, "async check result not expired yet")
+
+	clock.Advance(30 * time.Second)
+	results, _ = h.Results()
+	assert.Equal(t, "success; i=1", results[passingCheckName].Details, "cached within min interval")
+	assert.Equal(t, start, results[passingCheckName].Timestamp)
+
+	clock.Advance(time.Hour)
+	results, _ = h.Results()
+	assert.Equal(t, "success; i=2", results[passingCheckName].Details, "executed after min interval")
+	assert.Equal(t, start.Add(time.Hour+30*time.Second), results[passingCheckName].Timestamp)
+	assert.Equal(t, "failed; i=1", results[failingCheckName].Details, "executed after initial delay")
+	assert.Equal(t, gosundheit.ErrResultExpired, results[asyncCheckName].Error, "async check result expired")
+
+	h.Deregister(passingCheckName)
+	results, _ = h.Results()
+	assert.NotContains(t, results, passingCheckName, "deregistered without a task go routine")
+
+	assert.NoError(t, h.DeregisterAllAndWait(context.Background()))
+	results, _ = h.Results()
+	assert.Empty(t, results)
+}
+
+func TestWithCheckInterceptors(t *testing.T) {
+	var calls []string
+	recordingInterceptor := func(id string) gosundheit.Interceptor {
+		return gosundheit.ExecuteInterceptor(
+			func(ctx context.Context, name string, next gosundheit.ExecuteFunc) (details interface{}, err error) {
+				calls = append(calls, id+":"+name)
+				details, err = next(ctx)
+				return fmt.Sprintf("%s(%v)", id, details), err
+			})
+	}
+
+	checkWaiter := helper.NewCheckWaiter()
+	h := gosundheit.New(
+		gosundheit.WithCheckListeners(checkWaiter),
+		gosundheit.WithCheckInterceptors(recordingInterceptor("outer"), recordingInterceptor("inner")),
+	)
+	defer h.DeregisterAll()
+
+	registerCheck(h, passingCheckName, true, false)
+	assert.NoError(t, checkWaiter.AwaitChecksCompletion(passingCheckName))
+
+	results, _ := h.Results()
+	assert.Equal(t, "outer(inner(success; i=1))", results[passingCheckName].Details)
+	assert.Equal(t, []string{"outer:" + passingCheckName, "inner:" + passingCheckName}, calls)
+
+	renamingHealth := gosundheit.New(gosundheit.WithCheckInterceptors(func(next gosundheit.Check) gosundheit.Check {
+		return &checks.CustomCheck{CheckName: "renamed", CheckFunc: next.Execute}
+	}))
+	defer renamingHealth.DeregisterAll()
+
+	err := renamingHealth.RegisterCheck(&checks.CustomCheck{CheckName: passingCheckName}, gosundheit.ExecutionPeriod(time.Minute))
+	assert.EqualError(t, err, "check interceptors must preserve the check name")
+}
+
+func TestRegisterHealth(t *testing.T) {
+	const downstreamName = "downstream"
+
+	sub := gosundheit.New()
+	defer sub.DeregisterAll()
+	assert.NoError(t, sub.RegisterAsyncCheck(passingCheckName))
+	assert.NoError(t, sub.RegisterAsyncCheck(failingCheckName))
+	assert.NoError(t, sub.ReportResult(passingCheckName, successMsg, nil))
+	assert.NoError(t, sub.ReportResult(failingCheckName, failedMsg, errors.New(failedMsg)))
+
+	h := gosundheit.New(gosundheit.ExecutionPeriod(10 * time.Millisecond))
+	defer h.DeregisterAll()
+
+	assert.EqualError(t, h.RegisterHealth(downstreamName, nil), "health must not be nil")
+	assert.EqualError(t, h.RegisterHealth(downstreamName, h), "health must not be registered into itself")
+	assert.NoError(t, h.RegisterHealth(downstreamName, sub))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.AwaitFirstExecution(ctx, downstreamName))
+
+	results, healthy := h.Results()
+	assert.False(t, healthy, "sub health is unhealthy")
+	assert.EqualError(t, results[downstreamName].Error, "failing checks: [failing.check]")
+	subResults, ok := results[downstreamName].Details.(map[string]gosundheit.Result)
+	assert.True(t, ok, "details should be the sub health results")
+	assert.Equal(t, successMsg, subResults[passingCheckName].Details)
+	assert.Equal(t, failedMsg, subResults[failingCheckName].Details)
+
+	assert.NoError(t, sub.ReportResult(failingCheckName, successMsg, nil))
+	assert.NoError(t, h.AwaitHealthy(ctx), "sub health recovered")
+}
+
+func TestRegisterChecks(t *testing.T) {
+	h := gosundheit.New(gosundheit.ExecutionPeriod(time.Minute))
+	defer h.DeregisterAll()
+
+	err := h.RegisterChecks(
+		gosundheit.CheckRegistration{Check: &checks.CustomCheck{CheckName: passingCheckName}},
+		gosundheit.CheckRegistration{Check: &checks.CustomCheck{CheckName: failingCheckName}, Options: []gosundheit.CheckOption{gosundheit.ExecutionPeriod(0)}},
+		gosundheit.CheckRegistration{Check: &checks.CustomCheck{CheckName: passingCheckName}},
+		gosundheit.CheckRegistration{},
+	)
+	assert.EqualError(t, err, "check 'failing.check': execution period must be greater than 0; "+
+		"check 'passing.check': check is registered more than once; check must not be nil")
+	assert.Empty(t, h.ListChecks(), "no check should be registered when any registration is invalid")
+
+	err = h.RegisterChecks(
+		gosundheit.CheckRegistration{Check: &checks.CustomCheck{CheckName: passingCheckName}},
+		gosundheit.CheckRegistration{Check: &checks.CustomCheck{CheckName: failingCheckName}, Options: []gosundheit.CheckOption{gosundheit.Severity(gosundheit.SeverityWarning)}},
+	)
+	assert.NoError(t, err)
+
+	infos := h.ListChecks()
+	assert.Equal(t, 2, len(infos), "all checks should be registered")
+	assert.Equal(t, failingCheckName, infos[0].Name)
+	assert.Equal(t, gosundheit.SeverityWarning, infos[0].Severity)
+	assert.Equal(t, passingCheckName, infos[1].Name)
+	results, _ := h.Results()
+	assert.Equal(t, 2, len(results), "all checks should have initial results")
+}
+
+func TestRegisterCheckFunc(t *testing.T) {
+	h := gosundheit.New(gosundheit.ExecutionPeriod(time.Minute))
+	defer h.DeregisterAll()
+
+	assert.EqualError(t, h.RegisterCheckFunc(passingCheckName, nil), "check function must not be nil")
+	assert.EqualError(t, h.RegisterCheckFunc("", func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	}), "check name must not be empty")
+
+	err := h.RegisterCheckFunc(passingCheckName, func(ctx context.Context) (interface{}, error) {
+		return successMsg, nil
+	}, gosundheit.ExecutionTimeout(time.Second))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.AwaitHealthy(ctx))
+
+	results, _ := h.Results()
+	assert.Equal(t, successMsg, results[passingCheckName].Details)
+	assert.Equal(t, time.Second, h.ListChecks()[0].Timeout)
+}
+
+func TestDeregisterAndWait(t *testing.T) {
+	const slowCheckName = "slow.check"
+
+	h := gosundheit.New(gosundheit.ExecutionPeriod(time.Minute))
+	defer h.DeregisterAll()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	completed := int32(0)
+	assert.NoError(t, h.RegisterCheckFunc(slowCheckName, func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-release
+		atomic.StoreInt32(&completed, 1)
+		return successMsg, nil
+	}))
+	assert.NoError(t, h.RegisterAsyncCheck(passingCheckName))
+
+	<-started
+	cancelledCtx, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.Equal(t, context.Canceled, h.DeregisterAndWait(cancelledCtx, slowCheckName), "should wait for the current execution")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	close(release)
+	assert.NoError(t, h.DeregisterAndWait(ctx, slowCheckName))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&completed), "current execution should complete")
+	results, _ := h.Results()
+	assert.Equal(t, 1, len(results), "deregistered check result should be removed")
+	_, ok := results[slowCheckName]
+	assert.False(t, ok, "deregistered check result should be removed")
+
+	assert.NoError(t, h.DeregisterAndWait(ctx, "no.such.check"))
+}
+
+func registerCheck(h gosundheit.Health, name string, passing bool, initiallyPassing bool, opts ...gosundheit.CheckOption) {
 	i := 0
 	checkFunc := func(ctx context.Context) (details interface{}, err error) {
 		i++
@@ -167,9 +1288,11 @@ func registerCheck(h gosundheit.Health, name string, passing bool, initiallyPass
 			CheckName: name,
 			CheckFunc: checkFunc,
 		},
-		gosundheit.InitialDelay(20*time.Millisecond),
-		gosundheit.ExecutionPeriod(20*time.Millisecond),
-		gosundheit.InitiallyPassing(initiallyPassing),
+		append([]gosundheit.CheckOption{
+			gosundheit.InitialDelay(20 * time.Millisecond),
+			gosundheit.ExecutionPeriod(20 * time.Millisecond),
+			gosundheit.InitiallyPassing(initiallyPassing),
+		}, opts...)...,
 	)
 }
 
@@ -256,6 +1379,14 @@ func (l *checkListenerMock) OnCheckCompleted(name string, res gosundheit.Result)
 	l.completed = append(l.completed, completedCheck{name, res})
 }
 
+type checkTimeoutListenerMock struct {
+	checkListenerMock
+}
+
+func (l *checkTimeoutListenerMock) OnCheckTimeout(name string, res gosundheit.Result) {
+	l.Called(name, res)
+}
+
 type healthListenerMock struct {
 	completedChan chan map[string]gosundheit.Result
 }
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+const (
+	// FormatJSON is the value to be passed in the request parameter `format` for a JSON response, which is the default.
+	FormatJSON = "json"
+	// FormatYAML is the value to be passed in the request parameter `format` for a YAML response, which renders the same fields
+	// as the JSON response. It is also selected by an "application/yaml" Accept header.
+	FormatYAML = "yaml"
+	// FormatText is the value to be passed in the request parameter `format` for a plain text response, in the verbose format
+	// of the Kubernetes health endpoints. It is also selected by a "text/plain" Accept header.
+	FormatText = "text"
+)
+
+// ResultsEncoder renders the check results as a response body, in a custom format.
+type ResultsEncoder interface {
+	// ContentType returns the content type of the response, which is also matched against the Accept request header.
+	ContentType() string
+	// Encode writes the given results, sorted by check name, and the health of the system.
+	Encode(w io.Writer, results []gosundheit.NamedResult, healthy bool) error
+}
+
+// WithEncoder registers an encoder for the given format, which is selected by the `format` request parameter,
+// or by an Accept request header that matches the encoder content type.
+// It replaces the built-in encoder of the same format, e.g. FormatJSON for replacing the default format.
+func WithEncoder(format string, encoder ResultsEncoder) HandlerOption {
+	return handlerOptionFunc(func(cfg *handlerConfig) {
+		if cfg.encoders == nil {
+			cfg.encoders = make(map[string]ResultsEncoder)
+		}
+		if _, ok := cfg.encoders[format]; !ok {
+			cfg.formats = append(cfg.formats, format)
+		}
+		cfg.encoders[format] = encoder
+	})
+}
+
+// builtinFormats are the formats of the built-in encoders, in the order their media types are matched against the Accept header
+var builtinFormats = []struct {
+	format     string
+	mediaTypes []string
+}{
+	{format: FormatJSON, mediaTypes: []string{"application/json"}},
+	{format: FormatYAML, mediaTypes: []string{"application/yaml", "application/x-yaml", "text/yaml"}},
+	{format: FormatText, mediaTypes: []string{"text/plain"}},
+}
+
+// encoder returns the encoder of the format requested by the `format` request parameter, or else by the Accept header,
+// where the first supported media type is used regardless of its quality, and defaults to FormatJSON
+func (cfg handlerConfig) encoder(request *http.Request) ResultsEncoder {
+	if format := request.URL.Query().Get("format"); format != "" {
+		if encoder, ok := cfg.formatEncoder(request, format); ok {
+			return encoder
+		}
+	}
+	for _, accepted := range strings.Split(request.Header.Get("Accept"), ",") {
+		accepted = mediaType(accepted)
+		for _, format := range cfg.formats {
+			if mediaType(cfg.encoders[format].ContentType()) == accepted {
+				return cfg.encoders[format]
+			}
+		}
+		for _, builtin := range builtinFormats {
+			for _, builtinType := range builtin.mediaTypes {
+				if builtinType == accepted {
+					encoder, _ := cfg.formatEncoder(request, builtin.format)
+					return encoder
+				}
+			}
+		}
+	}
+	encoder, _ := cfg.formatEncoder(request, FormatJSON)
+	return encoder
+}
+
+// formatEncoder returns the encoder of the given format, where the built-in encoders render the `type` requested by the request
+func (cfg handlerConfig) formatEncoder(request *http.Request, format string) (ResultsEncoder, bool) {
+	if encoder, ok := cfg.encoders[format]; ok {
+		return encoder, true
+	}
+	short := request.URL.Query().Get("type") == ReportTypeShort
+	switch format {
+	case FormatJSON:
+		return jsonEncoder{short: short, ordered: cfg.ordered}, true
+	case FormatYAML:
+		return yamlEncoder{short: short, ordered: cfg.ordered}, true
+	case FormatText:
+		return textEncoder{}, true
+	}
+	return nil, false
+}
+
+// mediaType returns the media type of the given content type, without its parameters
+func mediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+}
+
+type jsonEncoder struct {
+	short   bool
+	ordered bool
+}
+
+func (e jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (e jsonEncoder) Encode(w io.Writer, results []gosundheit.NamedResult, _ bool) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	if e.short {
+		return encoder.Encode(shortFormat(results, e.ordered))
+	}
+	return encoder.Encode(longFormat(results, e.ordered))
+}
+
+// yamlEncoder renders the same fields and order as jsonEncoder
+type yamlEncoder struct {
+	short   bool
+	ordered bool
+}
+
+func (e yamlEncoder) ContentType() string {
+	return "application/yaml"
+}
+
+func (e yamlEncoder) Encode(w io.Writer, results []gosundheit.NamedResult, _ bool) error {
+	var body strings.Builder
+	if err := (jsonEncoder{short: e.short, ordered: e.ordered}).Encode(&body, results, false); err != nil {
+		return err
+	}
+	// JSON is a subset of YAML, so it's decoded into a node tree, which is re-encoded in the block style
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(body.String()), &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+	return yaml.NewEncoder(w).Encode(&node)
+}
+
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// textEncoder renders the results in the verbose format of the Kubernetes health endpoints,
+// with a "[+]name ok" or "[-]name failed: error" line per check, followed by the overall verdict
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (textEncoder) Encode(w io.Writer, results []gosundheit.NamedResult, healthy bool) error {
+	var text strings.Builder
+	for _, r := range results {
+		if r.IsHealthy() {
+			_, _ = fmt.Fprintf(&text, "[+]%s ok\n", r.Name)
+		} else {
+			_, _ = fmt.Fprintf(&text, "[-]%s failed: %s\n", r.Name, strings.Replace(r.Error.Error(), "\n", " ", -1))
+		}
+	}
+	if healthy {
+		text.WriteString("health check passed\n")
+	} else {
+		text.WriteString("health check failed\n")
+	}
+	_, err := io.WriteString(w, text.String())
+	return err
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// csvEncoder renders a "name,status" line per check
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string {
+	return "text/csv"
+}
+
+func (csvEncoder) Encode(w io.Writer, results []gosundheit.NamedResult, healthy bool) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "%s,%v\n", r.Name, r.IsHealthy()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type failingEncoder struct{}
+
+func (failingEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (failingEncoder) Encode(io.Writer, []gosundheit.NamedResult, bool) error {
+	return fmt.Errorf("encoding failed")
+}
+
+func TestWithEncoder(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"b.check", "a.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "pass", nil))
+
+	execEncoderReq := func(handler http.HandlerFunc, path, accept string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Result(), string(body)
+	}
+	handler := HandleHealthJSON(h, WithEncoder("csv", csvEncoder{}))
+
+	resp, body := execEncoderReq(handler, "/meh?format=csv", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "a.check,true\nb.check,false\n", body)
+
+	_, body = execEncoderReq(handler, "/meh", "text/html, text/csv;q=0.8")
+	assert.Equal(t, "a.check,true\nb.check,false\n", body, "selected by the Accept header")
+
+	resp, _ = execEncoderReq(handler, "/meh?format=xml", "")
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "unknown formats default to JSON")
+
+	resp, body = execEncoderReq(HandleHealthJSON(h, WithEncoder(FormatJSON, csvEncoder{})), "/meh", "")
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"), "the default format is replaced")
+	assert.Equal(t, "a.check,true\nb.check,false\n", body)
+
+	_, body = execEncoderReq(HandleReadiness(h, WithEncoder("csv", csvEncoder{})), "/meh?format=csv", "")
+	assert.Equal(t, "", body, "no readiness checks")
+
+	resp, body = execEncoderReq(HandleHealthJSON(h, WithEncoder(FormatJSON, failingEncoder{})), "/meh", "")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "Failed to render results: encoding failed\n", body)
+}
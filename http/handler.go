@@ -1,9 +1,13 @@
 package http
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	gosundheit "github.com/AppsFlyer/go-sundheit"
 )
@@ -13,37 +17,190 @@ const (
 	ReportTypeShort = "short"
 )
 
-// HandleHealthJSON returns an HandlerFunc that can be used as an endpoints that exposes the service health
-func HandleHealthJSON(h gosundheit.Health) http.HandlerFunc {
+// HandlerOption configures the health handler
+type HandlerOption interface {
+	apply(*handlerConfig)
+}
+
+type handlerConfig struct {
+	ordered         bool
+	refreshInterval time.Duration
+	// status codes of the responses, where zero means the default
+	healthyStatus   int
+	unhealthyStatus int
+	noChecksStatus  int
+	// encoders are the encoders registered using WithEncoder, by format, where formats holds their registration order
+	encoders map[string]ResultsEncoder
+	formats  []string
+}
+
+func newHandlerConfig(opts []HandlerOption) handlerConfig {
+	cfg := handlerConfig{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type handlerOptionFunc func(*handlerConfig)
+
+func (fn handlerOptionFunc) apply(cfg *handlerConfig) {
+	fn(cfg)
+}
+
+// WithHealthyStatusCode sets the response status code when the system is healthy, which defaults to 200.
+func WithHealthyStatusCode(code int) HandlerOption {
+	return handlerOptionFunc(func(cfg *handlerConfig) {
+		cfg.healthyStatus = code
+	})
+}
+
+// WithUnhealthyStatusCode sets the response status code when the system is unhealthy, which defaults to 503,
+// e.g. 200 for dashboards which render the results regardless of the health, or 429 for load balancers which back off on it.
+func WithUnhealthyStatusCode(code int) HandlerOption {
+	return handlerOptionFunc(func(cfg *handlerConfig) {
+		cfg.unhealthyStatus = code
+	})
+}
+
+// WithNoChecksStatusCode sets the response status code when there are no results to render, e.g. when no checks are registered yet,
+// regardless of the health. By default, such a system is healthy.
+func WithNoChecksStatusCode(code int) HandlerOption {
+	return handlerOptionFunc(func(cfg *handlerConfig) {
+		cfg.noChecksStatus = code
+	})
+}
+
+// WithOrderedResults renders the results as a JSON array sorted by check name, where each element holds the check name
+// along with its result, rather than as a JSON object keyed by check name.
+// In the short format, each element holds the check name and its "PASS" / "FAIL" status.
+func WithOrderedResults() HandlerOption {
+	return handlerOptionFunc(func(cfg *handlerConfig) {
+		cfg.ordered = true
+	})
+}
+
+// shortResult is the short format of a named result
+type shortResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// HandleHealthJSON returns an HandlerFunc that can be used as an endpoints that exposes the service health.
+// The `include` and `exclude` request parameters select a subset of the checks by their comma separated names,
+// in which case the response code reflects the health of the selected checks alone, and is 404 when an included check is unknown.
+func HandleHealthJSON(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	cfg := newHandlerConfig(opts)
+
 	return func(w http.ResponseWriter, request *http.Request) {
-		results, healthy := h.Results()
-		w.Header().Set("Content-Type", "application/json")
-		if healthy {
-			w.WriteHeader(200)
-		} else {
-			w.WriteHeader(503)
+		query := request.URL.Query()
+		include, exclude := queryNames(query, "include"), queryNames(query, "exclude")
+		if len(include) == 0 && len(exclude) == 0 {
+			results, healthy := h.OrderedResults()
+			writeResults(w, request, results, healthy, cfg)
+			return
 		}
 
-		encoder := json.NewEncoder(w)
-		encoder.SetIndent("", "\t")
-		var err error
-		if request.URL.Query().Get("type") == ReportTypeShort {
-			shortResults := make(map[string]string)
-			for k, v := range results {
-				if v.IsHealthy() {
-					shortResults[k] = "PASS"
-				} else {
-					shortResults[k] = "FAIL"
-				}
+		resultsMap, healthy := h.FilteredResults(func(name string) bool {
+			return (len(include) == 0 || include[name]) && !exclude[name]
+		})
+		var unknown []string
+		for name := range include {
+			if _, ok := resultsMap[name]; !ok && !exclude[name] {
+				unknown = append(unknown, name)
 			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			http.Error(w, fmt.Sprintf("Unknown checks: %s", strings.Join(unknown, ",")), http.StatusNotFound)
+			return
+		}
+		writeResults(w, request, sortResults(resultsMap), healthy, cfg)
+	}
+}
 
-			err = encoder.Encode(shortResults)
-		} else {
-			err = encoder.Encode(results)
+// queryNames returns the set of the comma separated check names of the given request parameter, which may be repeated
+func queryNames(query url.Values, param string) map[string]bool {
+	names := make(map[string]bool)
+	for _, value := range query[param] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names[name] = true
+			}
 		}
+	}
+	return names
+}
+
+// sortResults returns the given results sorted by check name
+func sortResults(resultsMap map[string]gosundheit.Result) []gosundheit.NamedResult {
+	results := make([]gosundheit.NamedResult, 0, len(resultsMap))
+	for name, result := range resultsMap {
+		results = append(results, gosundheit.NamedResult{Name: name, Result: result})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results
+}
+
+// writeResults renders the given results as the response, using the encoder of the format requested by the `format` request
+// parameter or the Accept header
+func writeResults(w http.ResponseWriter, request *http.Request, results []gosundheit.NamedResult, healthy bool, cfg handlerConfig) {
+	encoder := cfg.encoder(request)
+	var body bytes.Buffer
+	if err := encoder.Encode(&body, results, healthy); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to render results: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", encoder.ContentType())
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(cfg.statusCode(results, healthy))
+	_, _ = w.Write(body.Bytes())
+}
 
-		if err != nil {
-			_, _ = fmt.Fprintf(w, "Failed to render results JSON: %s", err)
+// statusCode returns the response status code for the given results and health
+func (cfg handlerConfig) statusCode(results []gosundheit.NamedResult, healthy bool) int {
+	switch {
+	case len(results) == 0 && cfg.noChecksStatus != 0:
+		return cfg.noChecksStatus
+	case healthy && cfg.healthyStatus != 0:
+		return cfg.healthyStatus
+	case healthy:
+		return http.StatusOK
+	case cfg.unhealthyStatus != 0:
+		return cfg.unhealthyStatus
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+func shortFormat(results []gosundheit.NamedResult, ordered bool) interface{} {
+	shortResults := make([]shortResult, len(results))
+	for i, r := range results {
+		shortResults[i] = shortResult{Name: r.Name, Status: "FAIL"}
+		if r.IsHealthy() {
+			shortResults[i].Status = "PASS"
 		}
 	}
+	if ordered {
+		return shortResults
+	}
+
+	byName := make(map[string]string, len(shortResults))
+	for _, r := range shortResults {
+		byName[r.Name] = r.Status
+	}
+	return byName
+}
+
+func longFormat(results []gosundheit.NamedResult, ordered bool) interface{} {
+	if ordered {
+		return results
+	}
+
+	byName := make(map[string]gosundheit.Result, len(results))
+	for _, r := range results {
+		byName[r.Name] = r.Result
+	}
+	return byName
 }
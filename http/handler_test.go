@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -113,6 +114,38 @@ func TestHandleHealthJSON_shortFormatPassingCheck(t *testing.T) {
 	assert.Equal(t, expectedResponse, respMsg, "body after first run")
 }
 
+func TestHandleHealthJSON_orderedResults(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"b.check", "c.check", "a.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "pass", nil))
+	assert.NoError(t, h.ReportResult("b.check", "pass", nil))
+
+	resp := execReq(h, true, WithOrderedResults())
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var longResults []struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&longResults))
+	assert.Equal(t, 3, len(longResults), "num results")
+	assert.Equal(t, "a.check", longResults[0].Name)
+	assert.Equal(t, "pass", longResults[0].Message)
+	assert.Equal(t, "b.check", longResults[1].Name)
+	assert.Equal(t, "c.check", longResults[2].Name)
+	assert.Equal(t, "didn't run yet", longResults[2].Message)
+
+	resp = execReq(h, false, WithOrderedResults())
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.JSONEq(t,
+		`[{"name":"a.check","status":"PASS"},{"name":"b.check","status":"PASS"},{"name":"c.check","status":"FAIL"}]`,
+		string(body))
+}
+
 func unmarshalShortFormat(r io.Reader) map[string]string {
 	respMsg := make(map[string]string)
 	_ = json.NewDecoder(r).Decode(&respMsg)
@@ -144,13 +177,13 @@ func createCheckOptions(delay time.Duration) []gosundheit.CheckOption {
 	}
 }
 
-func execReq(h gosundheit.Health, longFormat bool) *http.Response {
+func execReq(h gosundheit.Health, longFormat bool, opts ...HandlerOption) *http.Response {
 	var path = "/meh"
 	if !longFormat {
 		path = fmt.Sprintf("%s?type=%s", path, ReportTypeShort)
 	}
 
-	handler := HandleHealthJSON(h)
+	handler := HandleHealthJSON(h, opts...)
 
 	req := httptest.NewRequest(http.MethodGet, path, nil)
 	w := httptest.NewRecorder()
@@ -172,3 +205,140 @@ type checkResult struct {
 type Err struct {
 	Message string `json:"message"`
 }
+
+func TestHandleHealthJSON_filters(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"a.check", "b.check", "c.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "pass", nil))
+	assert.NoError(t, h.ReportResult("b.check", "pass", nil))
+
+	execFilterReq := func(query string) (int, string) {
+		w := httptest.NewRecorder()
+		HandleHealthJSON(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meh?type=short&"+query, nil))
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Result().StatusCode, string(body)
+	}
+
+	code, body := execFilterReq("include=a.check,b.check")
+	assert.Equal(t, http.StatusOK, code, "status of the passing checks")
+	assert.JSONEq(t, `{"a.check":"PASS","b.check":"PASS"}`, body)
+
+	code, body = execFilterReq("include=a.check&include=c.check")
+	assert.Equal(t, http.StatusServiceUnavailable, code, "status with a failing check")
+	assert.JSONEq(t, `{"a.check":"PASS","c.check":"FAIL"}`, body)
+
+	code, body = execFilterReq("exclude=c.check")
+	assert.Equal(t, http.StatusOK, code, "status without the failing check")
+	assert.JSONEq(t, `{"a.check":"PASS","b.check":"PASS"}`, body)
+
+	code, body = execFilterReq("include=a.check,c.check&exclude=c.check")
+	assert.Equal(t, http.StatusOK, code, "exclude overrides include")
+	assert.JSONEq(t, `{"a.check":"PASS"}`, body)
+
+	code, body = execFilterReq("include=a.check,x.check,w.check")
+	assert.Equal(t, http.StatusNotFound, code, "status of unknown checks")
+	assert.Equal(t, "Unknown checks: w.check,x.check\n", body)
+}
+
+func TestHandleHealthJSON_textFormat(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"b.check", "a.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "pass", nil))
+	assert.NoError(t, h.ReportResult("b.check", "fail", errors.New("connection\nrefused")))
+
+	execTextReq := func(path, accept string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		HandleHealthJSON(h).ServeHTTP(w, req)
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Result(), string(body)
+	}
+
+	resp, body := execTextReq("/meh?format=text", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "[+]a.check ok\n[-]b.check failed: connection refused\nhealth check failed\n", body)
+
+	_, body = execTextReq("/meh", "text/plain;q=0.9, application/json")
+	assert.Equal(t, "[+]a.check ok\n[-]b.check failed: connection refused\nhealth check failed\n", body, "text/plain Accept header")
+
+	resp, _ = execTextReq("/meh", "application/json, text/plain")
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "the first supported media type is used")
+
+	resp, _ = execTextReq("/meh?format=json", "text/plain")
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "the format parameter overrides the Accept header")
+
+	assert.NoError(t, h.ReportResult("b.check", "pass", nil))
+	resp, body = execTextReq("/meh?format=text&exclude=a.check", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "[+]b.check ok\nhealth check passed\n", body)
+}
+
+func TestHandleHealthJSON_yamlFormat(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"b.check", "a.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "123", nil))
+	assert.NoError(t, h.ReportResult("b.check", "fail", errors.New("connection refused")))
+
+	execYAMLReq := func(path, accept string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		HandleHealthJSON(h, WithOrderedResults()).ServeHTTP(w, req)
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Result(), string(body)
+	}
+
+	resp, body := execYAMLReq("/meh?format=yaml&type=short", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "- name: a.check\n  status: PASS\n- name: b.check\n  status: FAIL\n", body)
+
+	_, body = execYAMLReq("/meh", "application/yaml")
+	assert.Contains(t, body, "- name: a.check\n  message: \"123\"\n  timestamp: ", "same fields as JSON, with quoted strings")
+	assert.Contains(t, body, "- name: b.check\n  message: fail\n  error:\n    message: connection refused\n")
+	assert.Contains(t, body, "  contiguousFailures: 2\n")
+
+	_, body = execYAMLReq("/meh?format=yaml&include=a.check&exclude=a.check", "")
+	assert.Equal(t, "[]\n", body)
+}
+
+func TestHandleHealthJSON_statusCodes(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	opts := []HandlerOption{
+		WithHealthyStatusCode(http.StatusNoContent),
+		WithUnhealthyStatusCode(http.StatusTooManyRequests),
+		WithNoChecksStatusCode(http.StatusNotImplemented),
+	}
+	resp := execReq(h, false, opts...)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode, "status when no checks are registered")
+
+	assert.NoError(t, h.RegisterAsyncCheck(chkName))
+	resp = execReq(h, false, opts...)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "status when unhealthy")
+	resp = execReq(h, false)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "default status when unhealthy")
+	resp = execReq(h, false, WithUnhealthyStatusCode(http.StatusOK))
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status when unhealthy for dashboards")
+
+	assert.NoError(t, h.ReportResult(chkName, "pass", nil))
+	resp = execReq(h, false, opts...)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode, "status when healthy")
+	resp = execReq(h, false)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "default status when healthy")
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// defaultRefreshInterval is the default auto-refresh interval of the HTML dashboard
+const defaultRefreshInterval = 10 * time.Second
+
+// WithRefreshInterval sets the auto-refresh interval of the HTML dashboard (see HandleHealthHTML), which defaults to 10 seconds.
+// A negative interval disables the auto-refresh.
+func WithRefreshInterval(interval time.Duration) HandlerOption {
+	return handlerOptionFunc(func(cfg *handlerConfig) {
+		cfg.refreshInterval = interval
+	})
+}
+
+// htmlResult is a result as rendered by the HTML dashboard
+type htmlResult struct {
+	Name               string
+	Healthy            bool
+	Duration           time.Duration
+	ContiguousFailures int64
+	Timestamp          string
+	Details            string
+	Error              string
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">
+{{end}}<title>Health</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+.pass { color: #1a7f37; font-weight: bold; }
+.fail { color: #cf222e; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Health: {{if .Healthy}}<span class="pass">HEALTHY</span>{{else}}<span class="fail">UNHEALTHY</span>{{end}}</h1>
+<table>
+<tr><th>Check</th><th>Status</th><th>Duration</th><th>Contiguous failures</th><th>Last run</th><th>Details</th><th>Error</th></tr>
+{{range .Results}}<tr>
+<td>{{.Name}}</td>
+<td>{{if .Healthy}}<span class="pass">PASS</span>{{else}}<span class="fail">FAIL</span>{{end}}</td>
+<td>{{.Duration}}</td>
+<td>{{.ContiguousFailures}}</td>
+<td>{{.Timestamp}}</td>
+<td>{{.Details}}</td>
+<td>{{.Error}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// HandleHealthHTML returns an HandlerFunc that can be used as an endpoint that renders the service health as an HTML dashboard,
+// with the status, duration, contiguous failures, details and last error of each check, which auto-refreshes (see WithRefreshInterval).
+// The response code is 200 when the system is healthy, and 503 otherwise, unless customized using the status code options.
+func HandleHealthHTML(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	cfg := handlerConfig{refreshInterval: defaultRefreshInterval}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	var refresh int
+	if cfg.refreshInterval > 0 {
+		// the refresh interval is rendered in whole seconds
+		if refresh = int(cfg.refreshInterval.Seconds()); refresh == 0 {
+			refresh = 1
+		}
+	}
+
+	return func(w http.ResponseWriter, request *http.Request) {
+		results, healthy := h.OrderedResults()
+		htmlResults := make([]htmlResult, len(results))
+		for i, r := range results {
+			htmlResults[i] = htmlResult{
+				Name:               r.Name,
+				Healthy:            r.IsHealthy(),
+				Duration:           r.Duration,
+				ContiguousFailures: r.ContiguousFailures,
+			}
+			if !r.Timestamp.IsZero() {
+				htmlResults[i].Timestamp = r.Timestamp.Format(time.RFC3339)
+			}
+			if r.Details != nil {
+				htmlResults[i].Details = fmt.Sprintf("%v", r.Details)
+			}
+			if r.Error != nil {
+				htmlResults[i].Error = r.Error.Error()
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(cfg.statusCode(results, healthy))
+
+		err := dashboardTemplate.Execute(w, struct {
+			Refresh int
+			Healthy bool
+			Results []htmlResult
+		}{Refresh: refresh, Healthy: healthy, Results: htmlResults})
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "Failed to render results HTML: %s", err)
+		}
+	}
+}
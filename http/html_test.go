@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+func execHTMLReq(h gosundheit.Health, opts ...HandlerOption) (*http.Response, string) {
+	w := httptest.NewRecorder()
+	HandleHealthHTML(h, opts...).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health.html", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	return w.Result(), string(body)
+}
+
+func TestHandleHealthHTML(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"a.check", "<b>.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "pass", nil))
+	assert.NoError(t, h.ReportResult("<b>.check", "lottery=0.2", errors.New("Sorry, I failed")))
+
+	resp, body := execHTMLReq(h)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, body, `<meta http-equiv="refresh" content="10">`)
+	assert.Contains(t, body, `<span class="fail">UNHEALTHY</span>`)
+	assert.Contains(t, body, "<td>&lt;b&gt;.check</td>\n<td><span class=\"fail\">FAIL</span></td>", "escaped failing check")
+	assert.Contains(t, body, "<td>2</td>", "contiguous failures")
+	assert.Contains(t, body, "<td>lottery=0.2</td>\n<td>Sorry, I failed</td>")
+	assert.Contains(t, body, "<td>a.check</td>\n<td><span class=\"pass\">PASS</span></td>")
+
+	assert.NoError(t, h.ReportResult("<b>.check", "lottery=0.7", nil))
+	resp, body = execHTMLReq(h, WithRefreshInterval(-1))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `<span class="pass">HEALTHY</span>`)
+	assert.NotContains(t, body, "refresh", "auto-refresh disabled")
+
+	_, body = execHTMLReq(h, WithRefreshInterval(time.Minute))
+	assert.Contains(t, body, `<meta http-equiv="refresh" content="60">`)
+}
+
+func TestHandleHealthHTML_statusCodes(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	assert.NoError(t, h.RegisterAsyncCheck("a.check"))
+
+	resp, _ := execHTMLReq(h, WithUnhealthyStatusCode(http.StatusOK))
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status when unhealthy")
+}
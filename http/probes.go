@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// HandleLiveness returns an HandlerFunc that can be used as a Kubernetes liveness probe endpoint.
+// It renders the results of the checks classified as gosundheit.ClassificationLiveness (see gosundheit.Classifications) like HandleHealthJSON,
+// and responds with 503 when they make the system unhealthy. Liveness checks should only fail when the process must be restarted,
+// so checks of external dependencies should not be classified as liveness checks.
+func HandleLiveness(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	return handleClassification(h, gosundheit.ClassificationLiveness, opts...)
+}
+
+// HandleReadiness returns an HandlerFunc that can be used as a Kubernetes readiness probe endpoint.
+// It renders the results of the checks classified as gosundheit.ClassificationReadiness (see gosundheit.Classifications) like HandleHealthJSON,
+// and responds with 503 when they make the system unhealthy, so that traffic is not routed to it.
+func HandleReadiness(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	return handleClassification(h, gosundheit.ClassificationReadiness, opts...)
+}
+
+// HandleStartup returns an HandlerFunc that can be used as a Kubernetes startup probe endpoint.
+// It renders the results of the checks classified as gosundheit.ClassificationStartup (see gosundheit.Classifications) like HandleHealthJSON,
+// and responds with 503 until they pass. Once all the startup checks passed, the system is started for good, so the handler keeps
+// responding with 200, while the checks classified only as startup checks are no longer evaluated (see gosundheit.ClassificationStartup).
+func HandleStartup(h gosundheit.Health, opts ...HandlerOption) http.HandlerFunc {
+	return handleClassification(h, gosundheit.ClassificationStartup, opts...)
+}
+
+func handleClassification(h gosundheit.Health, classification gosundheit.Classification, opts ...HandlerOption) http.HandlerFunc {
+	cfg := newHandlerConfig(opts)
+
+	return func(w http.ResponseWriter, request *http.Request) {
+		results, healthy := classificationResults(h, classification)
+		writeResults(w, request, results, healthy, cfg)
+	}
+}
+
+// classificationResults returns the results of the checks of the given classification, sorted by check name
+func classificationResults(h gosundheit.Health, classification gosundheit.Classification) ([]gosundheit.NamedResult, bool) {
+	resultsMap, healthy := h.ResultsFor(classification)
+	return sortResults(resultsMap), healthy
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+func execProbeReq(handler http.HandlerFunc) (int, string) {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/probe?type="+ReportTypeShort, nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	return w.Result().StatusCode, string(body)
+}
+
+func TestHandleLivenessAndReadiness(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	require.NoError(t, h.RegisterAsyncCheck("deadlock.check", gosundheit.Classifications(gosundheit.ClassificationLiveness)))
+	require.NoError(t, h.RegisterAsyncCheck("db.check", gosundheit.Classifications(gosundheit.ClassificationReadiness)))
+	require.NoError(t, h.RegisterAsyncCheck("cache.check", gosundheit.Classifications(gosundheit.ClassificationReadiness)))
+	require.NoError(t, h.ReportResult("deadlock.check", nil, nil))
+	require.NoError(t, h.ReportResult("db.check", nil, nil))
+
+	code, body := execProbeReq(HandleLiveness(h))
+	assert.Equal(t, http.StatusOK, code, "liveness status")
+	assert.JSONEq(t, `{"deadlock.check":"PASS"}`, body)
+
+	code, body = execProbeReq(HandleReadiness(h, WithOrderedResults()))
+	assert.Equal(t, http.StatusServiceUnavailable, code, "readiness status")
+	assert.JSONEq(t, `[{"name":"cache.check","status":"FAIL"},{"name":"db.check","status":"PASS"}]`, body)
+
+	require.NoError(t, h.ReportResult("cache.check", nil, nil))
+	code, _ = execProbeReq(HandleReadiness(h))
+	assert.Equal(t, http.StatusOK, code, "readiness status")
+}
+
+func TestHandleStartup(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+	handler := HandleStartup(h)
+
+	code, _ := execProbeReq(handler)
+	assert.Equal(t, http.StatusOK, code, "status when no checks are registered")
+
+	require.NoError(t, h.RegisterAsyncCheck("migrations.check", gosundheit.Classifications(gosundheit.ClassificationStartup)))
+	code, body := execProbeReq(handler)
+	assert.Equal(t, http.StatusServiceUnavailable, code, "status before startup")
+	assert.JSONEq(t, `{"migrations.check":"FAIL"}`, body)
+
+	require.NoError(t, h.ReportResult("migrations.check", nil, nil))
+	code, body = execProbeReq(handler)
+	assert.Equal(t, http.StatusOK, code, "status after startup")
+	assert.JSONEq(t, `{"migrations.check":"PASS"}`, body)
+
+	require.NoError(t, h.ReportResult("migrations.check", nil, errors.New("failing")))
+	code, body = execProbeReq(handler)
+	assert.Equal(t, http.StatusOK, code, "startup checks are not evaluated once passed")
+	assert.JSONEq(t, `{"migrations.check":"PASS"}`, body)
+
+	code, body = execProbeReq(HandleStartup(h))
+	assert.Equal(t, http.StatusOK, code, "the startup state is kept by the Health instance, rather than by the handler")
+	assert.JSONEq(t, `{"migrations.check":"PASS"}`, body)
+
+	require.NoError(t, h.RegisterAsyncCheck("warmup.check", gosundheit.Classifications(gosundheit.ClassificationStartup, gosundheit.ClassificationReadiness)))
+	code, body = execProbeReq(handler)
+	assert.Equal(t, http.StatusOK, code, "a started system does not go back to starting")
+	assert.JSONEq(t, `{"migrations.check":"PASS","warmup.check":"FAIL"}`, body)
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// prometheusMetric is a gauge rendered for each check result
+type prometheusMetric struct {
+	name  string
+	help  string
+	value func(r gosundheit.Result) float64
+}
+
+var prometheusCheckMetrics = []prometheusMetric{
+	{
+		name: "health_check_status",
+		help: "The status of the health check (0/1 for fail/pass).",
+		value: func(r gosundheit.Result) float64 {
+			return boolToFloat(r.IsHealthy())
+		},
+	},
+	{
+		name: "health_check_duration_seconds",
+		help: "The execution duration of the last health check run.",
+		value: func(r gosundheit.Result) float64 {
+			return r.Duration.Seconds()
+		},
+	},
+	{
+		name: "health_check_contiguous_failures",
+		help: "The number of health check failures that occurred in a row.",
+		value: func(r gosundheit.Result) float64 {
+			return float64(r.ContiguousFailures)
+		},
+	},
+}
+
+// HandleHealthPrometheus returns an HandlerFunc that can be used as an endpoint that exposes the current check results
+// in the Prometheus text exposition format, so that it can be scraped directly without a metrics library.
+// Each check is reported by its status, last execution duration and contiguous failures gauges, labeled by the check name,
+// along with the overall health_status gauge. The response code is always 200, since the health is reported by the gauges.
+func HandleHealthPrometheus(h gosundheit.Health) http.HandlerFunc {
+	return func(w http.ResponseWriter, request *http.Request) {
+		results, healthy := h.OrderedResults()
+		w.Header().Set("Content-Type", prometheusContentType)
+		w.WriteHeader(200)
+
+		writer := bufio.NewWriter(w)
+		writePrometheusHeader(writer, "health_status", "The overall health status (0/1 for unhealthy/healthy).")
+		_, _ = fmt.Fprintf(writer, "health_status %v\n", boolToFloat(healthy))
+		for _, metric := range prometheusCheckMetrics {
+			writePrometheusHeader(writer, metric.name, metric.help)
+			for _, r := range results {
+				_, _ = fmt.Fprintf(writer, "%s{check=\"%s\"} %v\n", metric.name, prometheusLabelEscaper.Replace(r.Name), metric.value(r.Result))
+			}
+		}
+		_ = writer.Flush()
+	}
+}
+
+func writePrometheusHeader(w *bufio.Writer, name, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// prometheusLabelEscaper escapes label values as required by the Prometheus text exposition format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+func TestHandleHealthPrometheus(t *testing.T) {
+	h := gosundheit.New()
+	defer h.DeregisterAll()
+
+	for _, name := range []string{"b.check", `quoted "check"`, "a.check"} {
+		assert.NoError(t, h.RegisterAsyncCheck(name))
+	}
+	assert.NoError(t, h.ReportResult("a.check", "pass", nil))
+	assert.NoError(t, h.ReportResult("b.check", "fail", errors.New("failing")))
+	assert.NoError(t, h.ReportResult("b.check", "fail", errors.New("failing")))
+
+	w := httptest.NewRecorder()
+	HandleHealthPrometheus(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "status when unhealthy")
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `# HELP health_status The overall health status (0/1 for unhealthy/healthy).
+# TYPE health_status gauge
+health_status 0
+# HELP health_check_status The status of the health check (0/1 for fail/pass).
+# TYPE health_check_status gauge
+health_check_status{check="a.check"} 1
+health_check_status{check="b.check"} 0
+health_check_status{check="quoted \"check\""} 0
+# HELP health_check_duration_seconds The execution duration of the last health check run.
+# TYPE health_check_duration_seconds gauge
+health_check_duration_seconds{check="a.check"} 0
+health_check_duration_seconds{check="b.check"} 0
+health_check_duration_seconds{check="quoted \"check\""} 0
+# HELP health_check_contiguous_failures The number of health check failures that occurred in a row.
+# TYPE health_check_contiguous_failures gauge
+health_check_contiguous_failures{check="a.check"} 0
+health_check_contiguous_failures{check="b.check"} 3
+health_check_contiguous_failures{check="quoted \"check\""} 1
+`, string(body))
+}
+
+func TestHandleHealthPrometheus_noChecks(t *testing.T) {
+	w := httptest.NewRecorder()
+	HandleHealthPrometheus(gosundheit.New()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	assert.Contains(t, string(body), "health_status 1\n")
+	assert.NotContains(t, string(body), "{check=")
+}
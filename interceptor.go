@@ -0,0 +1,47 @@
+package gosundheit
+
+import "context"
+
+// Interceptor wraps a check with cross-cutting behavior, such as tracing, logging or metrics.
+// Interceptors are applied to every check registered using Health.RegisterCheck(), and must preserve the check name.
+type Interceptor func(next Check) Check
+
+// ExecuteFunc is the signature of Check.Execute
+type ExecuteFunc func(ctx context.Context) (details interface{}, err error)
+
+// ExecuteInterceptor returns an Interceptor that wraps the Execute method of the checks using the given function.
+// The function is called with the check name, and the wrapped check's Execute method as `next`.
+// For example, a logging interceptor:
+//
+//	gosundheit.ExecuteInterceptor(func(ctx context.Context, name string, next gosundheit.ExecuteFunc) (interface{}, error) {
+//		details, err := next(ctx)
+//		log.Printf("check %s completed: %v", name, err)
+//		return details, err
+//	})
+func ExecuteInterceptor(
+	wrap func(ctx context.Context, name string, next ExecuteFunc) (details interface{}, err error)) Interceptor {
+
+	return func(next Check) Check {
+		return &interceptedCheck{
+			Check: next,
+			wrap:  wrap,
+		}
+	}
+}
+
+type interceptedCheck struct {
+	Check
+	wrap func(ctx context.Context, name string, next ExecuteFunc) (details interface{}, err error)
+}
+
+func (c *interceptedCheck) Execute(ctx context.Context) (details interface{}, err error) {
+	return c.wrap(ctx, c.Check.Name(), c.Check.Execute)
+}
+
+// intercept applies the given interceptors to the check, such that the first interceptor is the outermost one.
+func intercept(check Check, interceptors []Interceptor) Check {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		check = interceptors[i](check)
+	}
+	return check
+}
@@ -105,6 +105,10 @@ func TestHealthMetricsWithReadinessClassification(t *testing.T) {
 }
 
 func TestHealthMetricsWithCustomClassification(t *testing.T) {
+	runTestHealthMetricsWithClassification(t, WithCheckClassification("demo"), "demo")
+}
+
+func TestHealthMetricsWithDeprecatedClassification(t *testing.T) {
 	runTestHealthMetricsWithClassification(t, WithClassification("demo"), "demo")
 }
 
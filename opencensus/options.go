@@ -1,33 +1,37 @@
 package opencensus
 
+import gosundheit "github.com/AppsFlyer/go-sundheit"
+
 type Option func(*MetricsListener)
 
-// WithClassification set custom classification for metrics
-func WithClassification(classification string) Option {
+// WithCheckClassification tags the metrics with the given classification (see gosundheit.Classifications),
+// e.g. for a listener of a Health instance dedicated to the checks of that classification
+func WithCheckClassification(classification gosundheit.Classification) Option {
 	return func(listener *MetricsListener) {
 		listener.classification = classification
 	}
 }
 
-// WithLivenessClassification sets the classification to "liveness"
+// WithClassification set custom classification for metrics
+//
+// Deprecated: use WithCheckClassification instead.
+func WithClassification(classification string) Option {
+	return WithCheckClassification(gosundheit.Classification(classification))
+}
+
+// WithLivenessClassification sets the classification to gosundheit.ClassificationLiveness
 func WithLivenessClassification() Option {
-	return func(listener *MetricsListener) {
-		listener.classification = "liveness"
-	}
+	return WithCheckClassification(gosundheit.ClassificationLiveness)
 }
 
-// WithReadinessClassification sets the classification to "readiness"
+// WithReadinessClassification sets the classification to gosundheit.ClassificationReadiness
 func WithReadinessClassification() Option {
-	return func(listener *MetricsListener) {
-		listener.classification = "readiness"
-	}
+	return WithCheckClassification(gosundheit.ClassificationReadiness)
 }
 
-// WithStartupClassification sets the classification to "startup"
+// WithStartupClassification sets the classification to gosundheit.ClassificationStartup
 func WithStartupClassification() Option {
-	return func(listener *MetricsListener) {
-		listener.classification = "startup"
-	}
+	return WithCheckClassification(gosundheit.ClassificationStartup)
 }
 
 func WithDefaults() Option {
@@ -8,6 +8,8 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
 )
 
 const (
@@ -54,13 +56,13 @@ var (
 	}
 )
 
-func createMonitoringCtx(classification, checkName string, isPassing bool) (ctx context.Context) {
+func createMonitoringCtx(classification gosundheit.Classification, checkName string, isPassing bool) (ctx context.Context) {
 	tags := []tag.Mutator{
 		tag.Insert(keyCheck, checkName),
 		tag.Insert(keyCheckPassing, strconv.FormatBool(isPassing)),
 	}
 	if classification != "" {
-		tags = append(tags, tag.Insert(keyClassification, classification))
+		tags = append(tags, tag.Insert(keyClassification, string(classification)))
 	}
 	ctx, err := tag.New(context.Background(), tags...)
 	if err != nil {
@@ -1,6 +1,7 @@
 package gosundheit
 
 import (
+	"context"
 	"time"
 )
 
@@ -34,6 +35,86 @@ func WithHealthListeners(listener ...HealthListener) HealthOption {
 	})
 }
 
+// WithHealthChangeListeners allows you to listen to overall health transitions only
+func WithHealthChangeListeners(listener ...HealthChangeListener) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		h.healthChangeListener = listener
+	})
+}
+
+// WithCheckInterceptors sets interceptors that are applied to every check registered using RegisterCheck(),
+// in order to add cross-cutting behavior (e.g. tracing, logging or metrics) without modifying each check.
+// The first interceptor is the outermost one.
+func WithCheckInterceptors(interceptors ...Interceptor) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		h.interceptors = interceptors
+	})
+}
+
+// WithContext sets the parent context of all the registered checks.
+// The context is propagated into each check execution, and once it is done all the scheduled checks are stopped
+// and further registrations fail. Defaults to context.Background()
+func WithContext(ctx context.Context) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		h.ctx = ctx
+	})
+}
+
+// WithClock sets the clock used for scheduling and timing the checks, e.g. for deterministic tests.
+// Note that execution timeouts (see ExecutionTimeout) are enforced by the check context, and therefore always use the system clock,
+// while hard timeouts (see HardTimeout) use the given clock.
+// Defaults to the system clock
+func WithClock(clock Clock) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		h.clock = clock
+	})
+}
+
+// WithMaxConcurrentChecks limits the number of checks that may execute at the same time to n.
+// Checks keep their own schedules, but an execution that becomes due while n other checks are executing
+// waits for one of them to complete. An execution abandoned after its hard timeout (see HardTimeout) counts against the limit
+// until it actually returns. Non positive values mean no limit, which is the default.
+func WithMaxConcurrentChecks(n int) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		if n > 0 {
+			h.executionSlots = make(chan struct{}, n)
+		} else {
+			h.executionSlots = nil
+		}
+	})
+}
+
+// WithHealthPolicy sets the policy that decides the overall health of the system given the checks results,
+// e.g. quorum based or percentage based rules. Defaults to requiring all the critical checks to pass
+func WithHealthPolicy(policy HealthPolicy) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		h.policy = policy
+	})
+}
+
+// WithManualExecution sets the checks to execute on demand rather than in the background: no goroutines are started,
+// and the due checks are executed when the results are queried (e.g. by Results(), IsHealthy() or the HTTP handler).
+// A check is due once its initial delay has passed, and then once minInterval has passed since its last execution,
+// so that frequent queries are served from the cached results. This is useful for serverless functions and CLI tools.
+func WithManualExecution(minInterval time.Duration) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		h.manualExecution = true
+		h.minExecutionInterval = minInterval
+	})
+}
+
+// WithRestoredState restores the checks results state, as returned by Health.Snapshot() of a previous instance.
+// Once a check with a restored state is registered, its restored result (without the details) is reported
+// until its first execution, rather than ErrNotRunYet, and the failure counters carry on from it.
+// Invalid state data is ignored
+func WithRestoredState(data []byte) HealthOption {
+	return healthOptionFunc(func(h *health) {
+		if state, err := parseState(data); err == nil {
+			h.restoredState = state
+		}
+	})
+}
+
 // WithDefaults sets all the Health object settings. It's not required to use this as no options is always default
 // This is a simple placeholder for any future defaults
 func WithDefaults() HealthOption {
@@ -106,6 +187,50 @@ func InitiallyPassing(b bool) Option {
 	return initiallyPassing(b)
 }
 
+type maxStaleness time.Duration
+
+func (o maxStaleness) apply(h *health) {
+	h.defaultMaxStaleness = time.Duration(o)
+}
+
+func (o maxStaleness) applyCheck(c *checkConfig) {
+	c.maxStaleness = time.Duration(o)
+}
+
+// MaxStaleness is the maximum age of a check result. An older result is treated as failing with ErrStaleResult,
+// which guards against stalled checks whose last result would otherwise be reported forever.
+// Defaults to no limit.
+func MaxStaleness(d time.Duration) Option {
+	return maxStaleness(d)
+}
+
+type preserveHistory bool
+
+func (o preserveHistory) applyCheck(c *checkConfig) {
+	c.preserveHistory = bool(o)
+}
+
+// PreserveHistory indicates when true, that a check replacing a previously registered check with the same name
+// keeps the previous result, including its contiguous failures and time of first failure, until its own first execution.
+// Otherwise, the replacing check starts with a fresh initial result; defaults to false
+func PreserveHistory(b bool) CheckOption {
+	return preserveHistory(b)
+}
+
+type debounce time.Duration
+
+func (o debounce) applyCheck(c *checkConfig) {
+	c.debounceWindow = time.Duration(o)
+}
+
+// Debounce smooths rapid healthy/unhealthy oscillations of the check.
+// A change in the check health is only reported (to listeners and in results) once it persisted for the given window.
+// Until then, the previously reported result is kept, with the latest raw Result as its details.
+// Defaults to no debouncing
+func Debounce(window time.Duration) CheckOption {
+	return debounce(window)
+}
+
 type executionTimeout time.Duration
 
 func (o executionTimeout) applyCheck(c *checkConfig) {
@@ -118,3 +243,86 @@ func (o executionTimeout) applyCheck(c *checkConfig) {
 func ExecutionTimeout(d time.Duration) CheckOption {
 	return executionTimeout(d)
 }
+
+type hardTimeout time.Duration
+
+func (o hardTimeout) applyCheck(c *checkConfig) {
+	c.hardTimeout = time.Duration(o)
+}
+
+// HardTimeout sets a hard deadline for the check executions, that is enforced even when the check does not respect
+// its context. Once the deadline is exceeded the execution is abandoned (its context is cancelled and its outcome discarded),
+// the check fails with ErrExecutionTimeout, and the OnCheckTimeout event is fired to listeners implementing CheckTimeoutListener.
+// The abandoned execution keeps its slot of WithMaxConcurrentChecks until it returns.
+// Defaults to no hard timeout
+func HardTimeout(d time.Duration) CheckOption {
+	return hardTimeout(d)
+}
+
+type severity SeverityLevel
+
+func (o severity) applyCheck(c *checkConfig) {
+	c.severity = SeverityLevel(o)
+}
+
+// Severity sets the severity level of the check.
+// Only failing SeverityCritical checks make the system unhealthy, while failing SeverityWarning checks
+// only degrade it. Defaults to SeverityCritical
+func Severity(level SeverityLevel) CheckOption {
+	return severity(level)
+}
+
+type resultTTL time.Duration
+
+func (o resultTTL) applyCheck(c *checkConfig) {
+	c.resultTTL = time.Duration(o)
+}
+
+// ResultTTL sets the time a reported result of an async check remains valid.
+// If no new result is reported within the TTL, the check fails with ErrResultExpired.
+// Defaults to no TTL
+func ResultTTL(d time.Duration) CheckOption {
+	return resultTTL(d)
+}
+
+type unhealthyAfter time.Duration
+
+func (o unhealthyAfter) applyCheck(c *checkConfig) {
+	c.unhealthyAfter = time.Duration(o)
+}
+
+// UnhealthyAfter sets the time a check must be failing continuously (since its TimeOfFirstFailure) before
+// it makes the system unhealthy, in order to absorb brief dependency blips.
+// Until then, the check failure only degrades the system. Defaults to zero, i.e. no grace period
+func UnhealthyAfter(d time.Duration) CheckOption {
+	return unhealthyAfter(d)
+}
+
+type classifications []Classification
+
+func (o classifications) applyCheck(c *checkConfig) {
+	c.classifications = append(c.classifications, o...)
+}
+
+// Classifications sets the classifications the check belongs to, e.g. ClassificationLiveness and ClassificationReadiness.
+// The results of the checks of a classification are available using Health.ResultsFor() and Health.IsHealthyFor(),
+// so that a single Health instance can back both the liveness and readiness endpoints.
+// Checks belong to no classification by default, in which case they only affect the overall health
+func Classifications(c ...Classification) CheckOption {
+	return classifications(c)
+}
+
+type dependsOn []string
+
+func (o dependsOn) applyCheck(c *checkConfig) {
+	c.dependencies = append(c.dependencies, o...)
+}
+
+// DependsOn declares the names of the checks the check depends on.
+// While any of the dependencies is failing (or is not registered), the check is not executed.
+// Instead, it is reported as failing with ErrDependencyFailing, and the failing dependencies as details.
+// This avoids noisy cascading failures, e.g. of a "db.migrations" check that depends on a "db.ping" check.
+// Registering a check whose dependencies form a cycle with the registered checks fails.
+func DependsOn(names ...string) CheckOption {
+	return dependsOn(names)
+}
@@ -0,0 +1,72 @@
+package gosundheit
+
+// HealthPolicy decides the overall health of the system, given the results of all the registered checks.
+// When a policy is set using WithHealthPolicy, it replaces the default policy, where the system is healthy
+// iff all critical checks are passing.
+type HealthPolicy interface {
+	// IsHealthy returns true iff the system is considered healthy given the checks results.
+	IsHealthy(results map[string]Result) bool
+}
+
+// HealthPolicyFunc type is an adapter to allow the use of ordinary functions as HealthPolicy.
+type HealthPolicyFunc func(results map[string]Result) bool
+
+// IsHealthy calls f(results).
+func (f HealthPolicyFunc) IsHealthy(results map[string]Result) bool {
+	return f(results)
+}
+
+// AllPassingPolicy returns a HealthPolicy where the system is healthy iff all the checks are passing, regardless of their severity.
+func AllPassingPolicy() HealthPolicy {
+	return HealthPolicyFunc(func(results map[string]Result) bool {
+		for _, result := range results {
+			if !result.IsHealthy() {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// MinPassingPolicy returns a HealthPolicy where the system is healthy iff at least `minPassing` of the checks with the given names are passing.
+// When no names are given, all the registered checks are considered.
+// For example, MinPassingPolicy(2, "replica.1", "replica.2", "replica.3") requires a quorum of 2 out of 3 replicas.
+func MinPassingPolicy(minPassing int, names ...string) HealthPolicy {
+	return HealthPolicyFunc(func(results map[string]Result) bool {
+		passing, _ := countPassing(results, names)
+		return passing >= minPassing
+	})
+}
+
+// PercentPassingPolicy returns a HealthPolicy where the system is healthy iff at least the given percentage (0-100)
+// of the registered checks are passing. A system without checks is healthy.
+func PercentPassingPolicy(percent float64) HealthPolicy {
+	return HealthPolicyFunc(func(results map[string]Result) bool {
+		passing, total := countPassing(results, nil)
+		if total == 0 {
+			return true
+		}
+		return float64(passing)*100/float64(total) >= percent
+	})
+}
+
+// countPassing counts the passing results of the checks with the given names, or of all the checks when no names are given.
+func countPassing(results map[string]Result, names []string) (passing, total int) {
+	if len(names) == 0 {
+		for _, result := range results {
+			total++
+			if result.IsHealthy() {
+				passing++
+			}
+		}
+		return passing, total
+	}
+
+	for _, name := range names {
+		total++
+		if result, ok := results[name]; ok && result.IsHealthy() {
+			passing++
+		}
+	}
+	return passing, total
+}
@@ -0,0 +1,60 @@
+package gosundheit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+var (
+	passingResult = gosundheit.Result{}
+	failingResult = gosundheit.Result{Error: errors.New(failedMsg)}
+)
+
+func TestAllPassingPolicy(t *testing.T) {
+	policy := gosundheit.AllPassingPolicy()
+
+	assert.True(t, policy.IsHealthy(nil), "no results")
+	assert.True(t, policy.IsHealthy(map[string]gosundheit.Result{"a": passingResult, "b": passingResult}))
+	assert.False(t, policy.IsHealthy(map[string]gosundheit.Result{"a": passingResult, "b": failingResult}))
+}
+
+func TestMinPassingPolicy(t *testing.T) {
+	results := map[string]gosundheit.Result{
+		"replica.1": passingResult,
+		"replica.2": failingResult,
+		"replica.3": passingResult,
+		"other":     failingResult,
+	}
+
+	assert.True(t, gosundheit.MinPassingPolicy(2, "replica.1", "replica.2", "replica.3").IsHealthy(results))
+	assert.False(t, gosundheit.MinPassingPolicy(3, "replica.1", "replica.2", "replica.3").IsHealthy(results))
+	assert.False(t, gosundheit.MinPassingPolicy(1, "replica.2", "missing").IsHealthy(results), "missing checks are not passing")
+	assert.True(t, gosundheit.MinPassingPolicy(2).IsHealthy(results), "all checks are considered when no names are given")
+	assert.False(t, gosundheit.MinPassingPolicy(3).IsHealthy(results), "all checks are considered when no names are given")
+}
+
+func TestPercentPassingPolicy(t *testing.T) {
+	results := map[string]gosundheit.Result{
+		"a": passingResult,
+		"b": passingResult,
+		"c": passingResult,
+		"d": failingResult,
+	}
+
+	assert.True(t, gosundheit.PercentPassingPolicy(75).IsHealthy(results))
+	assert.False(t, gosundheit.PercentPassingPolicy(76).IsHealthy(results))
+	assert.True(t, gosundheit.PercentPassingPolicy(100).IsHealthy(nil), "no results")
+}
+
+func TestHealthPolicyFunc(t *testing.T) {
+	policy := gosundheit.HealthPolicyFunc(func(results map[string]gosundheit.Result) bool {
+		return len(results) > 1
+	})
+
+	assert.False(t, policy.IsHealthy(map[string]gosundheit.Result{"a": passingResult}))
+	assert.True(t, policy.IsHealthy(map[string]gosundheit.Result{"a": failingResult, "b": failingResult}))
+}
@@ -0,0 +1,87 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// healthState is the serialized form of the checks results, as returned by Health.Snapshot()
+type healthState struct {
+	Checks map[string]checkState `json:"checks"`
+}
+
+// checkState is the serialized form of a check result. Details are not serialized, as they may not be serializable.
+type checkState struct {
+	Error              string     `json:"error,omitempty"`
+	Timestamp          time.Time  `json:"timestamp"`
+	ContiguousFailures int64      `json:"contiguousFailures"`
+	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure,omitempty"`
+}
+
+func newCheckState(result Result) checkState {
+	state := checkState{
+		Timestamp:          result.Timestamp,
+		ContiguousFailures: result.ContiguousFailures,
+		TimeOfFirstFailure: result.TimeOfFirstFailure,
+	}
+	if result.Error != nil {
+		state.Error = result.Error.Error()
+	}
+	return state
+}
+
+func (s checkState) result() Result {
+	result := Result{
+		Timestamp:          s.Timestamp,
+		ContiguousFailures: s.ContiguousFailures,
+		TimeOfFirstFailure: s.TimeOfFirstFailure,
+	}
+	if s.Error != "" {
+		result.Error = newMarshalableError(errors.New(s.Error))
+	}
+	return result
+}
+
+// parseState parses the given snapshot data into the states of the checks by name.
+func parseState(data []byte) (map[string]checkState, error) {
+	var state healthState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return state.Checks, nil
+}
+
+func (h *health) Snapshot() ([]byte, error) {
+	h.lock.RLock()
+	state := healthState{Checks: make(map[string]checkState, len(h.results))}
+	for name, result := range h.results {
+		task := h.checkTasks[name]
+		// there's nothing worth restoring before the first execution
+		if task != nil && task.executed {
+			state.Checks[name] = newCheckState(result)
+		}
+	}
+	h.lock.RUnlock()
+
+	return json.Marshal(state)
+}
+
+// restoreResultLocked sets the restored result of the given task, if there's a restored state for it.
+// Each restored state is only used once, by the first registration of a check with its name.
+// It must be called while holding the lock.
+func (h *health) restoreResultLocked(task *checkTask) (Result, bool) {
+	name := task.check.Name()
+	state, ok := h.restoredState[name]
+	if !ok {
+		return Result{}, false
+	}
+	delete(h.restoredState, name)
+
+	result := state.result()
+	rawResult := result
+	task.lastResult = &rawResult
+	h.results[name] = result
+	h.notifyUpdated()
+	return result, true
+}
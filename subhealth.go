@@ -0,0 +1,36 @@
+package gosundheit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// subHealthCheck is a Check that reports the results of another Health instance
+type subHealthCheck struct {
+	name string
+	sub  Health
+}
+
+func (c *subHealthCheck) Name() string {
+	return c.name
+}
+
+// Execute returns the results of the sub Health instance as details,
+// and fails when the sub Health instance is unhealthy.
+func (c *subHealthCheck) Execute(_ context.Context) (details interface{}, err error) {
+	results, healthy := c.sub.Results()
+	if !healthy {
+		var failing []string
+		for name, result := range results {
+			if !result.IsHealthy() {
+				failing = append(failing, name)
+			}
+		}
+		sort.Strings(failing)
+		err = fmt.Errorf("failing checks: [%s]", strings.Join(failing, ", "))
+	}
+
+	return results, err
+}
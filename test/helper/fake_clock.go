@@ -0,0 +1,124 @@
+package helper
+
+import (
+	"sync"
+	"time"
+
+	gosundheit "github.com/AppsFlyer/go-sundheit"
+)
+
+// FakeClock is a gosundheit.Clock whose time only moves when Advance() is called.
+// Timers and tickers created by the clock are pending until they are stopped, and timers until they fire as well.
+type FakeClock struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	clock    *FakeClock
+	deadline time.Time
+	period   time.Duration
+	c        chan time.Time
+}
+
+var _ gosundheit.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock whose current time is now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.lock)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// NewTimer returns a timer that fires once the clock is advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) gosundheit.Timer {
+	return c.addWaiter(d, 0)
+}
+
+// NewTicker returns a ticker that ticks whenever the clock is advanced past each period d.
+func (c *FakeClock) NewTicker(d time.Duration) gosundheit.Ticker {
+	return c.addWaiter(d, d)
+}
+
+// Advance moves the clock forward by d, firing all the timers and tickers that are due.
+// Fired timers are no longer pending. Like time.Ticker, a ticker whose channel is full drops ticks.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+
+		select {
+		case w.c <- c.now:
+		default:
+		}
+		if w.period > 0 {
+			for !w.deadline.After(c.now) {
+				w.deadline = w.deadline.Add(w.period)
+			}
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+	c.cond.Broadcast()
+}
+
+// AwaitWaiters blocks until exactly n timers and tickers are pending on the clock,
+// i.e. neither stopped nor, in the case of timers, fired.
+func (c *FakeClock) AwaitWaiters(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for len(c.waiters) != n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) addWaiter(d, period time.Duration) *fakeWaiter {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	w := &fakeWaiter{
+		clock:    c,
+		deadline: c.now.Add(d),
+		period:   period,
+		c:        make(chan time.Time, 1),
+	}
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+	return w
+}
+
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.c
+}
+
+func (w *fakeWaiter) Stop() {
+	c := w.clock
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+			return
+		}
+	}
+}
@@ -12,8 +12,116 @@ const (
 
 var (
 	ErrNotRunYet = newMarshalableError(errors.New("didn't run yet"))
+	// ErrResultExpired is the error of async checks which did not report a result within their TTL
+	ErrResultExpired = newMarshalableError(errors.New("result expired"))
+	// ErrStaleResult is the error of checks whose last result is older than their max staleness
+	ErrStaleResult = newMarshalableError(errors.New("result is stale"))
+	// ErrDependencyFailing is the error of checks that were skipped because at least one of their dependencies is failing
+	ErrDependencyFailing = newMarshalableError(errors.New("skipped: dependency failing"))
+	// ErrExecutionTimeout is the error of checks whose execution was abandoned after exceeding their hard timeout
+	ErrExecutionTimeout = newMarshalableError(errors.New("execution timed out"))
 )
 
+// SeverityLevel determines how a failing check affects the overall health of the system.
+type SeverityLevel int
+
+const (
+	// SeverityCritical checks make the system unhealthy when failing. This is the default severity.
+	SeverityCritical SeverityLevel = iota
+	// SeverityWarning checks only degrade the system when failing, but do not make it unhealthy.
+	SeverityWarning
+)
+
+func (s SeverityLevel) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("SeverityLevel(%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so a SeverityLevel is rendered by its name.
+func (s SeverityLevel) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Degraded wraps the error of a failing check, so that this failure only degrades the system, as if the check severity was
+// SeverityWarning, e.g. for checks which distinguish a degraded dependency from a failing one.
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+	return degradedError{err: err}
+}
+
+// IsDegraded returns true iff the given check error only degrades the system (see Degraded).
+func IsDegraded(err error) bool {
+	var marshalable marshalableError
+	if errors.As(err, &marshalable) {
+		return marshalable.degraded
+	}
+	return errors.As(err, &degradedError{})
+}
+
+type degradedError struct {
+	err error
+}
+
+func (e degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e degradedError) Unwrap() error {
+	return e.err
+}
+
+// Classification is a category of checks (e.g. liveness or readiness), whose health can be queried separately.
+type Classification string
+
+const (
+	// ClassificationStartup classifies checks that indicate whether the system has started.
+	// Once all the startup checks passed, the system is started for good: its startup health (see Health.IsHealthyFor) stays healthy,
+	// and checks classified only as startup checks are no longer executed once passing, keeping their passing results.
+	ClassificationStartup Classification = "startup"
+	// ClassificationLiveness classifies checks that indicate whether the system is alive, or should be restarted
+	ClassificationLiveness Classification = "liveness"
+	// ClassificationReadiness classifies checks that indicate whether the system is ready to serve
+	ClassificationReadiness Classification = "readiness"
+)
+
+// Status is the overall health status of the system.
+type Status int
+
+const (
+	// StatusHealthy means all checks are passing.
+	StatusHealthy Status = iota
+	// StatusDegraded means some non critical checks are failing, but all critical checks are passing.
+	StatusDegraded
+	// StatusUnhealthy means at least one critical check is failing.
+	StatusUnhealthy
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusHealthy:
+		return "healthy"
+	case StatusDegraded:
+		return "degraded"
+	case StatusUnhealthy:
+		return "unhealthy"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so a Status is rendered by its name.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // Result represents the output of a health check execution.
 type Result struct {
 	// the details of task Result - may be nil
@@ -28,6 +136,8 @@ type Result struct {
 	ContiguousFailures int64 `json:"contiguousFailures"`
 	// the time of the initial transitional failure
 	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure"`
+	// the time an override of the check result expires - nil when the result is not overridden
+	OverriddenUntil *time.Time `json:"overriddenUntil,omitempty"`
 }
 
 // IsHealthy returns true iff the check result snapshot was a success
@@ -40,9 +150,60 @@ func (r Result) String() string {
 		r.Details, r.Error, r.Timestamp, r.ContiguousFailures, r.TimeOfFirstFailure)
 }
 
+// NamedResult is the result of the health check with the given name.
+type NamedResult struct {
+	// Name is the name of the check
+	Name string `json:"name"`
+	Result
+}
+
+func (r NamedResult) String() string {
+	return fmt.Sprintf("%s: %s", r.Name, r.Result)
+}
+
+// Summary is an aggregated view of the health checks results
+type Summary struct {
+	// Status is the overall status of the system
+	Status Status `json:"status"`
+	// Total is the number of registered checks
+	Total int `json:"total"`
+	// Passing is the number of passing checks
+	Passing int `json:"passing"`
+	// Failing is the number of failing checks, including stale ones
+	Failing int `json:"failing"`
+	// Stale is the number of checks failing due to a stale result
+	Stale int `json:"stale"`
+	// OldestFailure is the earliest time of first failure among the failing checks, nil when no check is failing
+	OldestFailure *time.Time `json:"oldestFailure"`
+	// LastUpdate is the time of the most recent result, zero when no check is registered
+	LastUpdate time.Time `json:"lastUpdate"`
+}
+
+// CheckInfo describes the configuration of a registered check, and its scheduling state.
+type CheckInfo struct {
+	// Name is the name of the check
+	Name string `json:"name"`
+	// Async is true for checks that were registered using Health.RegisterAsyncCheck()
+	Async bool `json:"async,omitempty"`
+	// ExecutionPeriod is the period between successive executions, zero for async checks
+	ExecutionPeriod time.Duration `json:"executionPeriod,omitempty"`
+	// InitialDelay is the time the first execution was delayed by, zero for async checks
+	InitialDelay time.Duration `json:"initialDelay,omitempty"`
+	// Timeout is the execution timeout of the check, or zero for no timeout
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Severity is the severity level of the check
+	Severity SeverityLevel `json:"severity"`
+	// Classifications are the classifications of the check, if any
+	Classifications []Classification `json:"classifications,omitempty"`
+	// NextRun is the time of the next scheduled execution, zero for async checks
+	NextRun time.Time `json:"nextRun,omitempty"`
+}
+
 type marshalableError struct {
 	Message string `json:"message,omitempty"`
 	Cause   error  `json:"cause,omitempty"`
+	// degraded indicates the error only degrades the system (see Degraded)
+	degraded bool
 }
 
 func newMarshalableError(err error) error {
@@ -51,7 +212,12 @@ func newMarshalableError(err error) error {
 	}
 
 	mr := marshalableError{
-		Message: err.Error(),
+		Message:  err.Error(),
+		degraded: IsDegraded(err),
+	}
+	if degraded, ok := err.(degradedError); ok {
+		// the degraded wrapper has the same message as the wrapped error
+		err = degraded.err
 	}
 	cause := errors.Unwrap(err)
 	if !errors.Is(cause, err) {
@@ -1,14 +1,9 @@
 package gosundheit
 
-func allHealthy(results map[string]Result) (healthy bool) {
-	for _, v := range results {
-		if !v.IsHealthy() {
-			return false
-		}
-	}
-
-	return true
-}
+import (
+	"sort"
+	"strings"
+)
 
 func copyResultsMap(results map[string]Result) map[string]Result {
 	newMap := make(map[string]Result, len(results))
@@ -17,3 +12,38 @@ func copyResultsMap(results map[string]Result) map[string]Result {
 	}
 	return newMap
 }
+
+// sortedResults returns the given results as a slice sorted by check name
+func sortedResults(results map[string]Result) []NamedResult {
+	sorted := make([]NamedResult, 0, len(results))
+	for name, result := range results {
+		sorted = append(sorted, NamedResult{Name: name, Result: result})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// joinedErrors is an error composed of multiple errors
+type joinedErrors []error
+
+// join returns nil if there are no errors, the error itself if there's a single error, or all the errors otherwise.
+func (e joinedErrors) join() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	default:
+		return e
+	}
+}
+
+func (e joinedErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}